@@ -8,22 +8,45 @@ import (
 	"time"
 
 	"github.com/opentracing/opentracing-go"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 type Client struct {
-	orderClient desc.OrdersClient
-	stockClient desc.StocksClient
-	timeout     time.Duration
+	orderClient  desc.OrdersClient
+	stockClient  desc.StocksClient
+	healthClient healthpb.HealthClient
+	timeout      time.Duration
 }
 
-func New(orderClient desc.OrdersClient, stockClient desc.StocksClient, timeout time.Duration) *Client {
+func New(
+	orderClient desc.OrdersClient,
+	stockClient desc.StocksClient,
+	healthClient healthpb.HealthClient,
+	timeout time.Duration,
+) *Client {
 	return &Client{
-		orderClient: orderClient,
-		stockClient: stockClient,
-		timeout:     timeout,
+		orderClient:  orderClient,
+		stockClient:  stockClient,
+		healthClient: healthClient,
+		timeout:      timeout,
 	}
 }
 
+func (c *Client) Ping(ctx context.Context) error {
+	resp, err := c.healthClient.Check(ctx, &healthpb.HealthCheckRequest{
+		Service: desc.Orders_ServiceDesc.ServiceName,
+	})
+	if err != nil {
+		return fmt.Errorf("healthClient.Check: %w", err)
+	}
+
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("loms orders service is %s", resp.GetStatus())
+	}
+
+	return nil
+}
+
 func (c *Client) OrderCreate(ctx context.Context, userID uint64, items []domain.CartItem) (int64, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "lomsClient.OrderCreate")
 	defer span.Finish()
@@ -68,8 +91,10 @@ func itemsDomainToMap(items []domain.CartItem) []*desc.Item {
 
 	for idx, value := range items {
 		result[idx] = &desc.Item{
-			Sku:   int64(value.Item.Sku), // #nosec G115
-			Count: value.Item.Count,
+			Sku:      int64(value.Item.Sku), // #nosec G115
+			Count:    value.Item.Count,
+			Price:    value.Product.Price,
+			Currency: value.Product.Currency,
 		}
 	}
 
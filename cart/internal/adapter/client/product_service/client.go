@@ -12,29 +12,35 @@ import (
 
 const (
 	GetProductBySkuEndpoint = "http://%s/product/%d"
+
+	pingSku = 0
 )
 
 type Client struct {
 	httpClient http.Client
 	token      string
 	address    string
+	currency   string
 }
 
 type getProductResponseDTO struct {
-	Name  string `json:"name"`
-	Price uint32 `json:"price"`
-	Sku   uint32 `json:"sku"`
+	Name     string `json:"name"`
+	Price    uint32 `json:"price"`
+	Sku      uint32 `json:"sku"`
+	Currency string `json:"currency"`
 }
 
 func New(
 	httpClient http.Client,
 	token string,
 	address string,
+	currency string,
 ) *Client {
 	return &Client{
 		httpClient: httpClient,
 		token:      token,
 		address:    address,
+		currency:   currency,
 	}
 }
 
@@ -73,9 +79,41 @@ func (cl *Client) GetProductBySku(ctx context.Context, sku domain.Sku) (domain.P
 		return domain.Product{}, fmt.Errorf("json.NewDecoder %w", err)
 	}
 
+	currency := resp.Currency
+	if currency == "" {
+		currency = cl.currency
+	}
+
 	return domain.Product{
-		Name:  resp.Name,
-		Price: resp.Price,
-		Sku:   domain.Sku(resp.Sku),
+		Name:     resp.Name,
+		Price:    resp.Price,
+		Sku:      domain.Sku(resp.Sku),
+		Currency: currency,
 	}, nil
 }
+
+func (cl *Client) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodGet,
+		fmt.Sprintf(GetProductBySkuEndpoint, cl.address, pingSku),
+		http.NoBody,
+	)
+	if err != nil {
+		return fmt.Errorf("http.NewRequestWithContext: %w", err)
+	}
+
+	req.Header.Add("X-API-KEY", cl.token)
+
+	response, err := cl.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("httpClient.Do %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("productclient.Ping: %d", response.StatusCode)
+	}
+
+	return nil
+}
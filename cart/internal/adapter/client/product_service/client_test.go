@@ -51,9 +51,11 @@ func TestProductService_GetProductBySku(t *testing.T) {
 		*ts.Client(),
 		"integration-token",
 		url,
+		"RUB",
 	)
 	product, err := service.GetProductBySku(context.Background(), 111)
 	require.NoError(t, err)
+	require.Equal(t, "RUB", product.Currency)
 
 	fmt.Println(product)
 }
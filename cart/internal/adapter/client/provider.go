@@ -6,11 +6,13 @@ import (
 	"time"
 
 	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 func ProvideLOMSClient(conn *grpc.ClientConn, timeout time.Duration) (*lomsclient.Client, error) {
 	orderClient := desc.NewOrdersClient(conn)
 	stockClient := desc.NewStocksClient(conn)
+	healthClient := healthpb.NewHealthClient(conn)
 
-	return lomsclient.New(orderClient, stockClient, timeout), nil
+	return lomsclient.New(orderClient, stockClient, healthClient, timeout), nil
 }
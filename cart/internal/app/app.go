@@ -78,6 +78,13 @@ func (app *App) Run(ctx context.Context) error {
 		<-ctx.Done()
 	}()
 
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		app.serviceProvider.HealthChecker(ctx).Run(ctx, app.serviceProvider.HealthCheckPeriod())
+	}()
+
 	gracefulShutdown(ctx, cancel, wg)
 
 	return nil
@@ -157,7 +164,10 @@ func (app *App) initServiceProvider(_ context.Context) error {
 }
 
 func (app *App) initHTTPServer(ctx context.Context) error {
-	router := app.serviceProvider.AppHandler(ctx).InitRoutes()
+	router := http.NewServeMux()
+	router.Handle("/livez", app.serviceProvider.HealthChecker(ctx).LivenessHandler())
+	router.Handle("/readyz", app.serviceProvider.HealthChecker(ctx).ReadinessHandler())
+	router.Handle("/", app.serviceProvider.AppHandler(ctx).InitRoutes())
 
 	address := fmt.Sprintf("%s:%s", app.config.Server.Host, app.config.Server.Port)
 
@@ -200,7 +210,10 @@ func (app *App) initGRPCLomsClient(ctx context.Context) error {
 	conn, err := grpc.NewClient(
 		address,
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithUnaryInterceptor(middleware.MetricsClientInterceptor),
+		grpc.WithChainUnaryInterceptor(
+			middleware.TracingClientInterceptor,
+			middleware.MetricsClientInterceptor,
+		),
 	)
 	if err != nil {
 		logger.Fatalf(ctx, "failed to run grpc client")
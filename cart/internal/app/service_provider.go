@@ -12,6 +12,7 @@ import (
 	cartservice "route256/cart/internal/business/service/cart"
 	config "route256/cart/internal/infra/config"
 	daemon "route256/cart/internal/infra/daemon"
+	"route256/cart/internal/infra/health"
 	"time"
 
 	"github.com/go-playground/validator"
@@ -31,8 +32,9 @@ type serviceProvider struct {
 
 	lomsClient *lomsclient.Client
 
-	appServer *api.Server
-	validator *validator.Validate
+	appServer     *api.Server
+	validator     *validator.Validate
+	healthChecker *health.Checker
 }
 
 func newServiceProvider() *serviceProvider {
@@ -59,6 +61,7 @@ func (srv *serviceProvider) AppProductClient(_ context.Context) *productclient.C
 			*srv.httpProductClient,
 			srv.config.ProductService.Token,
 			address,
+			srv.config.ProductService.Currency,
 		)
 	}
 
@@ -107,3 +110,29 @@ func (srv *serviceProvider) Daemon(ctx context.Context) *daemon.Daemon {
 
 	return srv.daemon
 }
+
+func (srv *serviceProvider) HealthChecker(ctx context.Context) *health.Checker {
+	if srv.healthChecker == nil {
+		srv.healthChecker = health.New(srv.healthCheckTimeout())
+		srv.healthChecker.Add("product_service", srv.AppProductClient(ctx).Ping)
+		srv.healthChecker.Add("loms", srv.lomsClient.Ping)
+	}
+
+	return srv.healthChecker
+}
+
+func (srv *serviceProvider) HealthCheckPeriod() time.Duration {
+	if srv.config.Health.CheckPeriodMs <= 0 {
+		return 5 * time.Second
+	}
+
+	return time.Duration(srv.config.Health.CheckPeriodMs) * time.Millisecond
+}
+
+func (srv *serviceProvider) healthCheckTimeout() time.Duration {
+	if srv.config.Health.CheckTimeoutMs <= 0 {
+		return 2 * time.Second
+	}
+
+	return time.Duration(srv.config.Health.CheckTimeoutMs) * time.Millisecond
+}
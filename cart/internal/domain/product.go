@@ -3,7 +3,8 @@ package domain
 type Sku uint64
 
 type Product struct {
-	Name  string
-	Price uint32
-	Sku   Sku
+	Name     string
+	Price    uint32
+	Sku      Sku
+	Currency string
 }
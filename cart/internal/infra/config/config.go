@@ -19,12 +19,13 @@ type Config struct {
 		CheckStorageInterval int    `yaml:"check_storage_interval"`
 	} `yaml:"service"`
 	ProductService struct {
-		Host    string `yaml:"host"`
-		Port    string `yaml:"port"`
-		Token   string `yaml:"token"`
-		Timeout int    `yaml:"timeout"`
-		Limit   int    `yaml:"limit"`
-		Burst   int    `yaml:"burst"`
+		Host     string `yaml:"host"`
+		Port     string `yaml:"port"`
+		Token    string `yaml:"token"`
+		Timeout  int    `yaml:"timeout"`
+		Limit    int    `yaml:"limit"`
+		Burst    int    `yaml:"burst"`
+		Currency string `yaml:"currency"`
 	} `yaml:"product_service"`
 	LomsService struct {
 		Host string `yaml:"host"`
@@ -34,6 +35,10 @@ type Config struct {
 		Host string `yaml:"host"`
 		Port string `yaml:"port"`
 	} `yaml:"jaeger"`
+	Health struct {
+		CheckPeriodMs  int `yaml:"check_period_ms"`
+		CheckTimeoutMs int `yaml:"check_timeout_ms"`
+	} `yaml:"health"`
 }
 
 func LoadConfig(filename string) (*Config, error) {
@@ -3,9 +3,13 @@ package middleware
 import (
 	"context"
 	"route256/cart/internal/infra/metrics"
+	"route256/cart/internal/infra/tracing"
 	"time"
 
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 )
 
 func MetricsClientInterceptor(
@@ -31,3 +35,34 @@ func MetricsClientInterceptor(
 
 	return err
 }
+
+func TracingClientInterceptor(
+	ctx context.Context,
+	method string,
+	req, reply interface{},
+	cc *grpc.ClientConn,
+	invoker grpc.UnaryInvoker,
+	opts ...grpc.CallOption,
+) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, method, ext.SpanKindRPCClient)
+	defer span.Finish()
+
+	carrier := make(map[string]string)
+	if err := opentracing.GlobalTracer().Inject(span.Context(), opentracing.TextMap, opentracing.TextMapCarrier(carrier)); err == nil {
+		for key, value := range carrier {
+			ctx = metadata.AppendToOutgoingContext(ctx, key, value)
+		}
+	}
+
+	if requestID := tracing.RequestIDFromContext(ctx); requestID != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, tracing.RequestIDHeader, requestID)
+	}
+
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	if err != nil {
+		ext.Error.Set(span, true)
+		span.SetTag("err", err.Error())
+	}
+
+	return err
+}
@@ -4,8 +4,10 @@ import (
 	"net/http"
 	"route256/cart/internal/infra/logger"
 	"route256/cart/internal/infra/metrics"
+	"route256/cart/internal/infra/tracing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/opentracing/opentracing-go"
 )
 
@@ -75,11 +77,19 @@ func TracingMiddleware(next http.Handler) http.Handler {
 		)
 		defer span.Finish()
 
+		requestID := r.Header.Get(tracing.RequestIDHeader)
+		if requestID == "" {
+			requestID = uuid.NewString()
+		}
+		w.Header().Set(tracing.RequestIDHeader, requestID)
+
 		ctx := opentracing.ContextWithSpan(r.Context(), span)
+		ctx = tracing.WithRequestID(ctx, requestID)
 		r = r.WithContext(ctx)
 
 		span.SetTag("http.method", r.Method)
 		span.SetTag("http.url", r.URL.String())
+		span.SetTag("request_id", requestID)
 
 		next.ServeHTTP(w, r)
 	})
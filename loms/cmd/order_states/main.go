@@ -0,0 +1,10 @@
+package main
+
+import (
+	"fmt"
+	"route256/loms/internal/domain"
+)
+
+func main() {
+	fmt.Print(domain.NewOrderStateMachine(nil).DOT())
+}
@@ -0,0 +1,71 @@
+package kafka
+
+import (
+	"context"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/go-connections/nat"
+	tc "github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+type Opt struct {
+	ContainerName string
+	Port          string
+}
+
+const (
+	kafkaImage = "confluentinc/cp-kafka:7.7.1"
+
+	KafkaContainerName = "kafka-test"
+	KafkaPort          = "9095"
+
+	brokerPort = "9092/tcp"
+
+	Brokers = "localhost:9095"
+)
+
+func env(opt Opt) map[string]string {
+	return map[string]string{
+		"KAFKA_NODE_ID":                                  "1",
+		"KAFKA_LISTENER_SECURITY_PROTOCOL_MAP":           "PLAINTEXT:PLAINTEXT,CONTROLLER:PLAINTEXT,PLAINTEXT_HOST:PLAINTEXT",
+		"KAFKA_ADVERTISED_LISTENERS":                     "PLAINTEXT://" + opt.ContainerName + ":29092,PLAINTEXT_HOST://localhost:" + opt.Port,
+		"KAFKA_LISTENERS":                                "PLAINTEXT://0.0.0.0:29092,CONTROLLER://0.0.0.0:29093,PLAINTEXT_HOST://0.0.0.0:9092",
+		"KAFKA_CONTROLLER_LISTENER_NAMES":                "CONTROLLER",
+		"KAFKA_CONTROLLER_QUORUM_VOTERS":                 "1@localhost:29093",
+		"KAFKA_PROCESS_ROLES":                            "broker,controller",
+		"KAFKA_OFFSETS_TOPIC_REPLICATION_FACTOR":         "1",
+		"KAFKA_TRANSACTION_STATE_LOG_MIN_ISR":            "1",
+		"KAFKA_TRANSACTION_STATE_LOG_REPLICATION_FACTOR": "1",
+		"KAFKA_GROUP_INITIAL_REBALANCE_DELAY_MS":         "0",
+		"KAFKA_AUTO_CREATE_TOPICS_ENABLE":                "false",
+		"KAFKA_LOG_DIRS":                                 "/tmp/kraft-combined-logs",
+		"CLUSTER_ID":                                     "MkU3OEVBNTcwNTJENDM2Qk",
+	}
+}
+
+func NewContainer(ctx context.Context, opt Opt) (tc.Container, error) {
+	req := tc.ContainerRequest{
+		Name:         opt.ContainerName,
+		Image:        kafkaImage,
+		ExposedPorts: []string{brokerPort},
+		Env:          env(opt),
+		WaitingFor:   wait.ForListeningPort(nat.Port(brokerPort)).WithStartupTimeout(60 * time.Second),
+		HostConfigModifier: func(hc *container.HostConfig) {
+			hc.PortBindings = nat.PortMap{
+				nat.Port(brokerPort): []nat.PortBinding{{
+					HostPort: opt.Port,
+				}},
+			}
+		},
+	}
+	container, err := tc.GenericContainer(ctx, tc.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return container, nil
+}
@@ -0,0 +1,98 @@
+//go:build integration
+// +build integration
+
+package producer_test
+
+import (
+	"route256/loms/integration/containers/kafka"
+	syncproducer "route256/loms/internal/adapter/kafka/sync_producer"
+	"route256/loms/internal/domain"
+	"route256/loms/internal/infra/config"
+	"strconv"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+)
+
+func testEvents(topic string) []domain.Event {
+	events := make([]domain.Event, 0, 3)
+	for id := int64(1); id <= 3; id++ {
+		events = append(events, domain.Event{
+			ID:        id,
+			EventID:   "test-event-id-" + strconv.FormatInt(id, 10),
+			Topic:     topic,
+			Key:       strconv.FormatInt(id, 10),
+			Payload:   []byte(`{"OrderID": 1}`),
+			RequestID: "test-request-id",
+		})
+	}
+
+	return events
+}
+
+func (s *Suite) TestSendOrderEventsBatch_Success(t provider.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		topic         string
+		transactional bool
+	}{
+		{
+			name:          "plain producer",
+			topic:         "loms.order-events.plain",
+			transactional: false,
+		},
+		{
+			name:          "transactional producer",
+			topic:         "loms.order-events.txn",
+			transactional: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t provider.T) {
+			t.Parallel()
+
+			t.Title("Order events are published with event id headers: " + tc.name)
+
+			s.createTopic(t, tc.topic)
+
+			producer, err := syncproducer.New(s.ctx, config.Config{
+				Kafka: config.KafkaConfig{
+					Brokers:         kafka.Brokers,
+					OrderTopic:      tc.topic,
+					RetryCountMsg:   3,
+					Transactional:   tc.transactional,
+					TransactionalID: "loms-outbox-relay-test",
+				},
+			})
+			t.Require().NoError(err)
+			defer producer.Close(s.ctx)
+
+			events := testEvents(tc.topic)
+
+			t.WithNewStep("send batch", func(sCtx provider.StepCtx) {
+				successIDs, errorIDs, err := producer.SendOrderEventsBatch(s.ctx, events)
+				sCtx.Require().NoError(err)
+				sCtx.Require().Empty(errorIDs)
+				sCtx.Require().ElementsMatch([]int64{1, 2, 3}, successIDs)
+			})
+
+			t.WithNewStep("consume committed messages", func(sCtx provider.StepCtx) {
+				messages := s.consumeCommitted(t, tc.topic, len(events))
+				sCtx.Require().Len(messages, len(events))
+
+				for idx, msg := range messages {
+					headers := make(map[string]string, len(msg.Headers))
+					for _, header := range msg.Headers {
+						headers[string(header.Key)] = string(header.Value)
+					}
+
+					sCtx.Require().Equal(events[idx].EventID, headers[syncproducer.EventIDHeader])
+					sCtx.Require().Equal(events[idx].RequestID, headers["x-request-id"])
+					sCtx.Require().Equal(events[idx].Payload, msg.Value)
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,122 @@
+//go:build integration
+// +build integration
+
+package producer_test
+
+import (
+	"context"
+	"route256/loms/integration/containers/kafka"
+	"route256/loms/internal/infra/logger"
+	"route256/loms/internal/infra/metrics"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	tc "github.com/testcontainers/testcontainers-go"
+	"go.uber.org/zap/zapcore"
+)
+
+type Suite struct {
+	suite.Suite
+	ctx context.Context
+
+	admin    sarama.ClusterAdmin
+	kafkaCtn tc.Container
+}
+
+func (s *Suite) BeforeAll(t provider.T) {
+	s.ctx = context.Background()
+
+	t.WithNewStep("create kafka container", func(sCtx provider.StepCtx) {
+		opt := kafka.Opt{
+			ContainerName: kafka.KafkaContainerName,
+			Port:          kafka.KafkaPort,
+		}
+
+		ctn, err := kafka.NewContainer(s.ctx, opt)
+		sCtx.Require().NoError(err, "create kafka container")
+		s.kafkaCtn = ctn
+	})
+
+	t.WithNewStep("init producer dependencies", func(sCtx provider.StepCtx) {
+		err := metrics.Init(s.ctx)
+		sCtx.Require().NoError(err)
+
+		err = logger.Init(zapcore.DebugLevel)
+		sCtx.Require().NoError(err)
+	})
+
+	t.WithNewStep("create cluster admin", func(sCtx provider.StepCtx) {
+		var (
+			admin sarama.ClusterAdmin
+			err   error
+		)
+
+		for range 30 {
+			admin, err = sarama.NewClusterAdmin(strings.Split(kafka.Brokers, ","), sarama.NewConfig())
+			if err == nil {
+				break
+			}
+			time.Sleep(time.Second)
+		}
+		sCtx.Require().NoError(err, "create cluster admin")
+		s.admin = admin
+	})
+}
+
+func (s *Suite) AfterAll(t provider.T) {
+	t.WithNewStep("close cluster admin", func(sCtx provider.StepCtx) {
+		err := s.admin.Close()
+		sCtx.Require().NoError(err, "close cluster admin")
+	})
+
+	t.WithNewStep("stop kafka container", func(sCtx provider.StepCtx) {
+		err := s.kafkaCtn.Terminate(s.ctx)
+		sCtx.Require().NoError(err, "terminate kafka container")
+	})
+}
+
+func (s *Suite) createTopic(t provider.T, topic string) {
+	err := s.admin.CreateTopic(topic, &sarama.TopicDetail{
+		NumPartitions:     1,
+		ReplicationFactor: 1,
+	}, false)
+	t.Require().NoError(err, "create topic")
+}
+
+func (s *Suite) consumeCommitted(t provider.T, topic string, count int) []*sarama.ConsumerMessage {
+	config := sarama.NewConfig()
+	config.Consumer.IsolationLevel = sarama.ReadCommitted
+	config.Consumer.Offsets.Initial = sarama.OffsetOldest
+
+	consumer, err := sarama.NewConsumer(strings.Split(kafka.Brokers, ","), config)
+	t.Require().NoError(err, "create consumer")
+	defer consumer.Close()
+
+	partition, err := consumer.ConsumePartition(topic, 0, sarama.OffsetOldest)
+	t.Require().NoError(err, "consume partition")
+	defer partition.Close()
+
+	messages := make([]*sarama.ConsumerMessage, 0, count)
+	timeout := time.After(30 * time.Second)
+
+	for len(messages) < count {
+		select {
+		case msg := <-partition.Messages():
+			messages = append(messages, msg)
+		case <-timeout:
+			return messages
+		}
+	}
+
+	return messages
+}
+
+func TestKafkaProducer(t *testing.T) {
+	t.Parallel()
+
+	suite.RunSuite(t, new(Suite))
+}
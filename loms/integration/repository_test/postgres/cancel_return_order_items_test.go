@@ -0,0 +1,84 @@
+//go:build integration
+// +build integration
+
+package repository_test
+
+import (
+	"context"
+	"route256/loms/internal/domain"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+)
+
+func (s *Suite) TestCancelAndReturnOrderItems_Success(t provider.T) {
+	t.Parallel()
+
+	t.Title("Successful partial cancel and return of order items")
+
+	var (
+		testUserID int64 = 777
+		testItems        = []domain.Item{
+			{
+				Sku:      domain.Sku(1076963),
+				Count:    3,
+				Price:    100,
+				Currency: domain.DefaultCurrency,
+			},
+			{
+				Sku:      domain.Sku(1148162),
+				Count:    1,
+				Price:    50,
+				Currency: domain.DefaultCurrency,
+			},
+		}
+		orderID int64
+	)
+
+	t.WithNewStep("create order", func(sCtx provider.StepCtx) {
+		err := s.txManger.ReadCommitted(s.ctx, func(txCtx context.Context) error {
+			var err error
+
+			orderID, err = s.orderRepo.CreateOrder(txCtx, testUserID)
+			sCtx.Require().NoError(err)
+
+			return s.orderRepo.CreateOrderItems(txCtx, orderID, testItems)
+		})
+		sCtx.Require().NoError(err)
+	})
+
+	t.WithNewStep("cancel order items", func(sCtx provider.StepCtx) {
+		err := s.orderRepo.CancelOrderItems(s.ctx, orderID, []domain.Item{
+			{Sku: domain.Sku(1076963), Count: 1},
+			{Sku: domain.Sku(1148162), Count: 1},
+		})
+		sCtx.Require().NoError(err)
+
+		order, err := s.orderRepo.GetByOrderID(s.ctx, orderID)
+		sCtx.Require().NoError(err)
+		sCtx.Require().Equal([]domain.Item{{
+			Sku:      domain.Sku(1076963),
+			Count:    2,
+			Price:    100,
+			Currency: domain.DefaultCurrency,
+		}}, order.Items)
+		sCtx.Require().Equal(int64(200), order.TotalPrice)
+	})
+
+	t.WithNewStep("return order items", func(sCtx provider.StepCtx) {
+		err := s.orderRepo.ReturnOrderItems(s.ctx, orderID, []domain.Item{
+			{Sku: domain.Sku(1076963), Count: 2},
+		})
+		sCtx.Require().NoError(err)
+
+		order, err := s.orderRepo.GetByOrderID(s.ctx, orderID)
+		sCtx.Require().NoError(err)
+		sCtx.Require().Empty(order.Items)
+	})
+
+	t.WithNewStep("cannot return more than left in order", func(sCtx provider.StepCtx) {
+		err := s.orderRepo.ReturnOrderItems(s.ctx, orderID, []domain.Item{
+			{Sku: domain.Sku(1076963), Count: 1},
+		})
+		sCtx.Require().Error(err)
+	})
+}
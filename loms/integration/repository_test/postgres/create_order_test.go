@@ -19,8 +19,10 @@ func (s *Suite) TestCreateOrder_Success(t provider.T) {
 		testUserID int64 = 1
 		testItem         = []domain.Item{
 			{
-				Sku:   domain.Sku(1625903),
-				Count: 10,
+				Sku:      domain.Sku(1625903),
+				Count:    10,
+				Price:    500,
+				Currency: domain.DefaultCurrency,
 			},
 		}
 		testOrderID int64
@@ -52,12 +54,9 @@ func (s *Suite) TestCreateOrder_Success(t provider.T) {
 
 		sCtx.Require().Equal(orderID.UserID, testUserID)
 		sCtx.Require().Equal(orderID.Status, domain.OrderStatusNew)
-		sCtx.Require().Equal(orderID.Items, []domain.Item{
-			{
-				Sku:   testItem[0].Sku,
-				Count: testItem[0].Count,
-			},
-		})
+		sCtx.Require().Equal(orderID.Items, testItem)
+		sCtx.Require().Equal(int64(5000), orderID.TotalPrice)
+		sCtx.Require().Equal(domain.DefaultCurrency, orderID.Currency)
 	})
 
 	t.WithNewStep("set status", func(sCtx provider.StepCtx) {
@@ -71,12 +70,9 @@ func (s *Suite) TestCreateOrder_Success(t provider.T) {
 
 		sCtx.Require().Equal(orderID.UserID, testUserID)
 		sCtx.Require().Equal(orderID.Status, domain.OrderStatusAwaitingPayment)
-		sCtx.Require().Equal(orderID.Items, []domain.Item{
-			{
-				Sku:   testItem[0].Sku,
-				Count: testItem[0].Count,
-			},
-		})
+		sCtx.Require().Equal(orderID.Items, testItem)
+		sCtx.Require().Equal(int64(5000), orderID.TotalPrice)
+		sCtx.Require().Equal(domain.DefaultCurrency, orderID.Currency)
 	})
 
 	t.WithNewStep("set incorrect status", func(sCtx provider.StepCtx) {
@@ -0,0 +1,51 @@
+//go:build integration
+// +build integration
+
+package repository_test
+
+import (
+	"route256/loms/internal/domain"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+)
+
+func (s *Suite) TestCreateStock_Success(t provider.T) {
+	t.Parallel()
+
+	t.Title("Successful creation of new stock")
+
+	var (
+		testSku        = domain.Sku(5550001)
+		testTotalCount = int64(25)
+	)
+
+	t.WithNewStep("create stock", func(sCtx provider.StepCtx) {
+		err := s.stockRepo.CreateStock(s.ctx, domain.WarehouseStock{
+			WarehouseID: domain.DefaultWarehouseID,
+			Sku:         testSku,
+			TotalCount:  testTotalCount,
+		})
+		sCtx.Require().NoError(err)
+
+		stock, err := s.stockRepo.GetStockBySku(s.ctx, testSku)
+		sCtx.Require().NoError(err)
+
+		sCtx.Require().Equal(testTotalCount, stock.TotalCount)
+		sCtx.Require().Equal(int64(0), stock.Reserved)
+	})
+}
+
+func (s *Suite) TestCreateStock_AlreadyExists(t provider.T) {
+	t.Parallel()
+
+	t.Title("Creation of already existing stock")
+
+	t.WithNewStep("create stock", func(sCtx provider.StepCtx) {
+		err := s.stockRepo.CreateStock(s.ctx, domain.WarehouseStock{
+			WarehouseID: domain.DefaultWarehouseID,
+			Sku:         s.testData.testSku2,
+			TotalCount:  10,
+		})
+		sCtx.Require().ErrorIs(err, domain.ErrStockAlreadyExists)
+	})
+}
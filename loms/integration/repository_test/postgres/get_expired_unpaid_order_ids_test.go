@@ -0,0 +1,95 @@
+//go:build integration
+// +build integration
+
+package repository_test
+
+import (
+	"context"
+	"route256/loms/internal/domain"
+	"time"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+)
+
+func (s *Suite) TestGetExpiredUnpaidOrderIDsForUpdate_Success(t provider.T) {
+	t.Parallel()
+
+	t.Title("Successful selection of expired unpaid orders")
+
+	var (
+		testUserID int64 = 888
+		testItems        = []domain.Item{
+			{
+				Sku:   domain.Sku(1625903),
+				Count: 1,
+			},
+		}
+		expiredOrderID int64
+		freshOrderID   int64
+	)
+
+	t.WithNewStep("create awaiting payment orders", func(sCtx provider.StepCtx) {
+		err := s.txManger.ReadCommitted(s.ctx, func(txCtx context.Context) error {
+			var err error
+
+			expiredOrderID, err = s.orderRepo.CreateOrder(txCtx, testUserID)
+			sCtx.Require().NoError(err)
+			sCtx.Require().NoError(s.orderRepo.CreateOrderItems(txCtx, expiredOrderID, testItems))
+			sCtx.Require().NoError(s.orderRepo.SetStatus(txCtx, expiredOrderID, domain.OrderStatusAwaitingPayment))
+
+			freshOrderID, err = s.orderRepo.CreateOrder(txCtx, testUserID)
+			sCtx.Require().NoError(err)
+			sCtx.Require().NoError(s.orderRepo.CreateOrderItems(txCtx, freshOrderID, testItems))
+			sCtx.Require().NoError(s.orderRepo.SetStatus(txCtx, freshOrderID, domain.OrderStatusAwaitingPayment))
+
+			return nil
+		})
+		sCtx.Require().NoError(err)
+
+		_, err = s.pools.Master.Exec(s.ctx,
+			`UPDATE orders SET updated_at = NOW() - INTERVAL '1 hour' WHERE id = $1`, expiredOrderID)
+		sCtx.Require().NoError(err)
+	})
+
+	t.WithNewStep("get expired unpaid orders", func(sCtx provider.StepCtx) {
+		err := s.txManger.ReadCommitted(s.ctx, func(txCtx context.Context) error {
+			orderIDs, err := s.orderRepo.GetExpiredUnpaidOrderIDsForUpdate(txCtx, 30*time.Minute, nil, 100)
+			sCtx.Require().NoError(err)
+
+			sCtx.Require().Contains(orderIDs, expiredOrderID)
+			sCtx.Require().NotContains(orderIDs, freshOrderID)
+
+			return nil
+		})
+		sCtx.Require().NoError(err)
+	})
+
+	t.WithNewStep("excluded orders are skipped", func(sCtx provider.StepCtx) {
+		err := s.txManger.ReadCommitted(s.ctx, func(txCtx context.Context) error {
+			orderIDs, err := s.orderRepo.GetExpiredUnpaidOrderIDsForUpdate(
+				txCtx, 30*time.Minute, []int64{expiredOrderID}, 100)
+			sCtx.Require().NoError(err)
+			sCtx.Require().NotContains(orderIDs, expiredOrderID)
+
+			return nil
+		})
+		sCtx.Require().NoError(err)
+	})
+
+	t.WithNewStep("locked orders are skipped by concurrent worker", func(sCtx provider.StepCtx) {
+		err := s.txManger.ReadCommitted(s.ctx, func(txCtx context.Context) error {
+			orderIDs, err := s.orderRepo.GetExpiredUnpaidOrderIDsForUpdate(txCtx, 30*time.Minute, nil, 100)
+			sCtx.Require().NoError(err)
+			sCtx.Require().Contains(orderIDs, expiredOrderID)
+
+			return s.txManger.ReadCommitted(context.Background(), func(otherTxCtx context.Context) error {
+				otherOrderIDs, err := s.orderRepo.GetExpiredUnpaidOrderIDsForUpdate(otherTxCtx, 30*time.Minute, nil, 100)
+				sCtx.Require().NoError(err)
+				sCtx.Require().NotContains(otherOrderIDs, expiredOrderID)
+
+				return nil
+			})
+		})
+		sCtx.Require().NoError(err)
+	})
+}
@@ -22,9 +22,11 @@ func (s *Suite) TestGetStocksBySkuForUpate_Success(t provider.T) {
 
 		stock, err := s.stockRepo.GetStocksBySkuForUpdate(s.ctx, data)
 		sCtx.Require().NoError(err)
+		sCtx.Require().Len(stock[s.testData.testSku1], 1)
 
-		sCtx.Require().Equal(stock[s.testData.testSku1].Reserved, s.testData.testReserved1)
-		sCtx.Require().Equal(stock[s.testData.testSku1].TotalCount, s.testData.testTotalCount1)
+		sCtx.Require().Equal(stock[s.testData.testSku1][0].WarehouseID, domain.DefaultWarehouseID)
+		sCtx.Require().Equal(stock[s.testData.testSku1][0].Reserved, s.testData.testReserved1)
+		sCtx.Require().Equal(stock[s.testData.testSku1][0].TotalCount, s.testData.testTotalCount1)
 	})
 }
 
@@ -0,0 +1,69 @@
+//go:build integration
+// +build integration
+
+package repository_test
+
+import (
+	"context"
+	"route256/loms/integration/containers/postgres"
+	"route256/loms/internal/infra/leader"
+	"time"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+)
+
+func (s *Suite) TestLeaderElection_Success(t provider.T) {
+	t.Parallel()
+
+	t.Title("Only one elector holds leadership and hands it over on shutdown")
+
+	var (
+		testLockID        int64 = 777_000_001
+		testCheckInterval       = 100 * time.Millisecond
+	)
+
+	waitFor := func(cond func() bool) bool {
+		deadline := time.Now().Add(10 * time.Second)
+		for time.Now().Before(deadline) {
+			if cond() {
+				return true
+			}
+			time.Sleep(testCheckInterval)
+		}
+
+		return false
+	}
+
+	firstCtx, stopFirst := context.WithCancel(s.ctx)
+	secondCtx, stopSecond := context.WithCancel(s.ctx)
+	defer stopSecond()
+
+	first := leader.New(postgres.MasterDSN, testLockID, testCheckInterval)
+	second := leader.New(postgres.MasterDSN, testLockID, testCheckInterval)
+
+	firstDone := make(chan struct{})
+	go func() {
+		defer close(firstDone)
+		first.Run(firstCtx)
+	}()
+
+	t.WithNewStep("first elector becomes leader", func(sCtx provider.StepCtx) {
+		sCtx.Require().True(waitFor(first.IsLeader))
+	})
+
+	go second.Run(secondCtx)
+
+	t.WithNewStep("second elector stays follower", func(sCtx provider.StepCtx) {
+		time.Sleep(5 * testCheckInterval)
+		sCtx.Require().True(first.IsLeader())
+		sCtx.Require().False(second.IsLeader())
+	})
+
+	t.WithNewStep("leadership is handed over on shutdown", func(sCtx provider.StepCtx) {
+		stopFirst()
+		<-firstDone
+
+		sCtx.Require().False(first.IsLeader())
+		sCtx.Require().True(waitFor(second.IsLeader))
+	})
+}
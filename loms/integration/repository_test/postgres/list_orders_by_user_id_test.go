@@ -0,0 +1,103 @@
+//go:build integration
+// +build integration
+
+package repository_test
+
+import (
+	"context"
+	"route256/loms/internal/domain"
+	"time"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+)
+
+func (s *Suite) TestListByUserID_Success(t provider.T) {
+	t.Parallel()
+
+	t.Title("Successful listing of user orders with keyset pagination")
+
+	var (
+		testUserID int64 = 777
+		testItems        = []domain.Item{
+			{
+				Sku:      domain.Sku(1625903),
+				Count:    1,
+				Price:    300,
+				Currency: domain.DefaultCurrency,
+			},
+		}
+		testOrderIDs []int64
+	)
+
+	t.WithNewStep("create orders", func(sCtx provider.StepCtx) {
+		for i := 0; i < 3; i++ {
+			err := s.txManger.ReadCommitted(s.ctx, func(txCtx context.Context) error {
+				orderID, err := s.orderRepo.CreateOrder(txCtx, testUserID)
+				sCtx.Require().NoError(err)
+
+				err = s.orderRepo.CreateOrderItems(txCtx, orderID, testItems)
+				sCtx.Require().NoError(err)
+
+				testOrderIDs = append(testOrderIDs, orderID)
+
+				return nil
+			})
+			sCtx.Require().NoError(err)
+		}
+
+		err := s.orderRepo.SetStatus(s.ctx, testOrderIDs[0], domain.OrderStatusCancelled)
+		sCtx.Require().NoError(err)
+	})
+
+	t.WithNewStep("list first page", func(sCtx provider.StepCtx) {
+		orders, err := s.orderRepo.ListByUserID(s.ctx, domain.OrderListFilter{
+			UserID: testUserID,
+			Limit:  2,
+		})
+		sCtx.Require().NoError(err)
+
+		sCtx.Require().Len(orders, 2)
+		sCtx.Require().Equal(testOrderIDs[2], orders[0].ID)
+		sCtx.Require().Equal(testOrderIDs[1], orders[1].ID)
+		sCtx.Require().Equal(testItems, orders[0].Items)
+		sCtx.Require().Equal(int64(300), orders[0].TotalPrice)
+		sCtx.Require().False(orders[0].CreatedAt.IsZero())
+	})
+
+	t.WithNewStep("list next page by cursor", func(sCtx provider.StepCtx) {
+		orders, err := s.orderRepo.ListByUserID(s.ctx, domain.OrderListFilter{
+			UserID: testUserID,
+			Cursor: testOrderIDs[1],
+			Limit:  2,
+		})
+		sCtx.Require().NoError(err)
+
+		sCtx.Require().Len(orders, 1)
+		sCtx.Require().Equal(testOrderIDs[0], orders[0].ID)
+		sCtx.Require().Equal(domain.OrderStatusCancelled, orders[0].Status)
+	})
+
+	t.WithNewStep("list by status", func(sCtx provider.StepCtx) {
+		orders, err := s.orderRepo.ListByUserID(s.ctx, domain.OrderListFilter{
+			UserID:   testUserID,
+			Statuses: []domain.OrderStatus{domain.OrderStatusCancelled},
+			Limit:    10,
+		})
+		sCtx.Require().NoError(err)
+
+		sCtx.Require().Len(orders, 1)
+		sCtx.Require().Equal(testOrderIDs[0], orders[0].ID)
+	})
+
+	t.WithNewStep("list by created_at range", func(sCtx provider.StepCtx) {
+		createdTo := time.Now().Add(-time.Hour)
+
+		orders, err := s.orderRepo.ListByUserID(s.ctx, domain.OrderListFilter{
+			UserID:    testUserID,
+			CreatedTo: &createdTo,
+			Limit:     10,
+		})
+		sCtx.Require().NoError(err)
+		sCtx.Require().Empty(orders)
+	})
+}
@@ -0,0 +1,84 @@
+//go:build integration
+// +build integration
+
+package repository_test
+
+import (
+	"context"
+	"route256/loms/internal/domain"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+)
+
+func (s *Suite) TestOrderStatusHistory_Success(t provider.T) {
+	t.Parallel()
+
+	t.Title("Successful record of order status history")
+
+	var (
+		testUserID int64 = 779
+		testItems        = []domain.Item{
+			{
+				Sku:   domain.Sku(1625903),
+				Count: 1,
+			},
+		}
+		testStatuses = []domain.OrderStatus{
+			domain.OrderStatusNew,
+			domain.OrderStatusAwaitingPayment,
+			domain.OrderStatusPayed,
+		}
+		orderID int64
+	)
+
+	t.WithNewStep("create order", func(sCtx provider.StepCtx) {
+		err := s.txManger.ReadCommitted(s.ctx, func(txCtx context.Context) error {
+			var err error
+
+			orderID, err = s.orderRepo.CreateOrder(txCtx, testUserID)
+			sCtx.Require().NoError(err)
+
+			return s.orderRepo.CreateOrderItems(txCtx, orderID, testItems)
+		})
+		sCtx.Require().NoError(err)
+	})
+
+	t.WithNewStep("change order status", func(sCtx provider.StepCtx) {
+		for _, status := range testStatuses[1:] {
+			err := s.orderRepo.SetStatus(s.ctx, orderID, status)
+			sCtx.Require().NoError(err)
+		}
+	})
+
+	t.WithNewStep("get order status history", func(sCtx provider.StepCtx) {
+		history, err := s.orderRepo.GetStatusHistory(s.ctx, orderID)
+		sCtx.Require().NoError(err)
+		sCtx.Require().Len(history, len(testStatuses))
+
+		for idx, change := range history {
+			sCtx.Require().Equal(testStatuses[idx], change.Status)
+			if idx > 0 {
+				sCtx.Require().False(change.ChangedAt.Before(history[idx-1].ChangedAt))
+			}
+		}
+
+		order, err := s.orderRepo.GetByOrderID(s.ctx, orderID)
+		sCtx.Require().NoError(err)
+		sCtx.Require().Equal(history[0].ChangedAt, order.CreatedAt)
+		sCtx.Require().Equal(history[len(history)-1].ChangedAt, order.UpdatedAt)
+	})
+
+	t.WithNewStep("history is not changed by failed status update", func(sCtx provider.StepCtx) {
+		err := s.orderRepo.SetStatus(s.ctx, orderID, domain.OrderStatus("Incorrect status"))
+		sCtx.Require().Error(err)
+
+		history, err := s.orderRepo.GetStatusHistory(s.ctx, orderID)
+		sCtx.Require().NoError(err)
+		sCtx.Require().Len(history, len(testStatuses))
+	})
+
+	t.WithNewStep("get history of unknown order", func(sCtx provider.StepCtx) {
+		_, err := s.orderRepo.GetStatusHistory(s.ctx, orderID+1_000_000)
+		sCtx.Require().ErrorIs(err, domain.ErrOrderNotFound)
+	})
+}
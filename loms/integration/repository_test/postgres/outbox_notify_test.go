@@ -0,0 +1,57 @@
+//go:build integration
+// +build integration
+
+package repository_test
+
+import (
+	"context"
+	"route256/loms/integration/containers/postgres"
+	outboxrepository "route256/loms/internal/adapter/repository/postgtres/outbox"
+	"route256/loms/internal/domain"
+	pg "route256/loms/internal/infra/postgres"
+	"strconv"
+	"time"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+)
+
+func (s *Suite) TestOutboxNotify_Success(t provider.T) {
+	t.Parallel()
+
+	t.Title("Outbox insert wakes up the listener")
+
+	ctx, cancel := context.WithCancel(s.ctx)
+	defer cancel()
+
+	listener := pg.NewListener(postgres.MasterDSN, outboxrepository.NotifyChannel)
+	go listener.Run(ctx)
+
+	t.WithNewStep("listener wakes up after connect", func(sCtx provider.StepCtx) {
+		var received bool
+		select {
+		case <-listener.Notifications():
+			received = true
+		case <-time.After(10 * time.Second):
+		}
+		sCtx.Require().True(received, "listener did not connect")
+	})
+
+	t.WithNewStep("listener wakes up after outbox insert", func(sCtx provider.StepCtx) {
+		err := s.txManger.ReadCommitted(s.ctx, func(txCtx context.Context) error {
+			return s.outboxRepo.CreateEvent(txCtx, domain.Event{
+				Topic:   "test_notify_topic",
+				Key:     strconv.FormatInt(time.Now().UnixNano(), 10),
+				Payload: []byte(`{"OrderID": 1}`),
+			})
+		})
+		sCtx.Require().NoError(err)
+
+		var received bool
+		select {
+		case <-listener.Notifications():
+			received = true
+		case <-time.After(5 * time.Second):
+		}
+		sCtx.Require().True(received, "notification was not received")
+	})
+}
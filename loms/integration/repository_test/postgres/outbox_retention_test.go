@@ -0,0 +1,104 @@
+//go:build integration
+// +build integration
+
+package repository_test
+
+import (
+	"context"
+	"route256/loms/internal/domain"
+	"strconv"
+	"time"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+)
+
+func (s *Suite) TestOutboxRetention_Success(t provider.T) {
+	t.Parallel()
+
+	t.Title("Sent outbox events older than retention are archived and removed")
+
+	var (
+		testArchiveRetention = 30 * 24 * time.Hour
+		testDeleteRetention  = 24 * time.Hour
+		testKeys             = []string{
+			strconv.FormatInt(time.Now().UnixNano(), 10) + "-archived",
+			strconv.FormatInt(time.Now().UnixNano(), 10) + "-deleted",
+		}
+		eventIDs = make([]int64, len(testKeys))
+	)
+
+	t.WithNewStep("create old sent events", func(sCtx provider.StepCtx) {
+		for idx, key := range testKeys {
+			err := s.outboxRepo.CreateEvent(s.ctx, domain.Event{
+				Topic:   "test_retention_topic",
+				Key:     key,
+				Payload: []byte(`{"OrderID": 1}`),
+			})
+			sCtx.Require().NoError(err)
+
+			err = s.pools.Master.QueryRow(s.ctx, "SELECT id FROM outbox WHERE key = $1", key).Scan(&eventIDs[idx])
+			sCtx.Require().NoError(err)
+		}
+
+		err := s.outboxRepo.MarkAsSent(s.ctx, eventIDs)
+		sCtx.Require().NoError(err)
+
+		_, err = s.pools.Master.Exec(s.ctx,
+			"UPDATE outbox SET sent_at = NOW() - INTERVAL '40 days' WHERE id = ANY($1)", eventIDs)
+		sCtx.Require().NoError(err)
+	})
+
+	t.WithNewStep("archive first event", func(sCtx provider.StepCtx) {
+		_, err := s.pools.Master.Exec(s.ctx,
+			"UPDATE outbox SET sent_at = NOW() - INTERVAL '2 days' WHERE id = $1", eventIDs[1])
+		sCtx.Require().NoError(err)
+
+		err = s.outboxRepo.CreateArchivePartitions(s.ctx, testArchiveRetention)
+		sCtx.Require().NoError(err)
+
+		archived, err := s.outboxRepo.ArchiveSentEvents(s.ctx, testArchiveRetention, 1000)
+		sCtx.Require().NoError(err)
+		sCtx.Require().GreaterOrEqual(archived, int64(1))
+
+		var key string
+		err = s.pools.Master.QueryRow(s.ctx, "SELECT key FROM outbox_archive WHERE id = $1", eventIDs[0]).Scan(&key)
+		sCtx.Require().NoError(err)
+		sCtx.Require().Equal(testKeys[0], key)
+
+		var count int
+		err = s.pools.Master.QueryRow(s.ctx, "SELECT COUNT(*) FROM outbox WHERE id = $1", eventIDs[1]).Scan(&count)
+		sCtx.Require().NoError(err)
+		sCtx.Require().Equal(1, count)
+	})
+
+	t.WithNewStep("delete second event without archive", func(sCtx provider.StepCtx) {
+		_, err := s.outboxRepo.DeleteSentEvents(s.ctx, testDeleteRetention, 1000)
+		sCtx.Require().NoError(err)
+
+		var count int
+		err = s.pools.Master.QueryRow(s.ctx,
+			"SELECT COUNT(*) FROM outbox WHERE id = ANY($1)", eventIDs).Scan(&count)
+		sCtx.Require().NoError(err)
+		sCtx.Require().Equal(0, count)
+
+		err = s.pools.Master.QueryRow(s.ctx,
+			"SELECT COUNT(*) FROM outbox_archive WHERE id = $1", eventIDs[1]).Scan(&count)
+		sCtx.Require().NoError(err)
+		sCtx.Require().Equal(0, count)
+	})
+
+	t.WithNewStep("oldest undelivered age", func(sCtx provider.StepCtx) {
+		err := s.txManger.ReadCommitted(s.ctx, func(txCtx context.Context) error {
+			return s.outboxRepo.CreateEvent(txCtx, domain.Event{
+				Topic:   "test_retention_topic",
+				Key:     testKeys[0] + "-pending",
+				Payload: []byte(`{"OrderID": 1}`),
+			})
+		})
+		sCtx.Require().NoError(err)
+
+		age, err := s.outboxRepo.GetOldestUndeliveredAge(s.ctx)
+		sCtx.Require().NoError(err)
+		sCtx.Require().GreaterOrEqual(age, time.Duration(0))
+	})
+}
@@ -0,0 +1,117 @@
+//go:build integration
+// +build integration
+
+package repository_test
+
+import (
+	"context"
+	"route256/loms/internal/domain"
+	"strconv"
+	"time"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+)
+
+func (s *Suite) TestOutboxRetry_Success(t provider.T) {
+	t.Parallel()
+
+	t.Title("Failed outbox event is retried with backoff, becomes dead and is redriven")
+
+	var (
+		testKey   = strconv.FormatInt(time.Now().UnixNano(), 10)
+		testEvent = domain.Event{
+			Topic:   "test_retry_topic",
+			Key:     testKey,
+			Payload: []byte(`{"OrderID": 1}`),
+		}
+		testPolicy = domain.EventRetryPolicy{
+			MaxAttempts: 2,
+			BackoffBase: time.Hour,
+			BackoffMax:  time.Hour,
+		}
+		eventID int64
+	)
+
+	findEvent := func(sCtx provider.StepCtx) (domain.Event, bool) {
+		var (
+			found domain.Event
+			ok    bool
+		)
+
+		err := s.txManger.ReadCommitted(s.ctx, func(txCtx context.Context) error {
+			events, err := s.outboxRepo.FetchNextMessages(txCtx, 1000)
+			if err != nil {
+				return err
+			}
+
+			for _, event := range events {
+				if event.Key == testKey {
+					found, ok = event, true
+				}
+			}
+
+			return nil
+		})
+		sCtx.Require().NoError(err)
+
+		return found, ok
+	}
+
+	t.WithNewStep("create event", func(sCtx provider.StepCtx) {
+		err := s.outboxRepo.CreateEvent(s.ctx, testEvent)
+		sCtx.Require().NoError(err)
+
+		event, ok := findEvent(sCtx)
+		sCtx.Require().True(ok)
+		sCtx.Require().Equal(int32(0), event.Attempts)
+		eventID = event.ID
+	})
+
+	t.WithNewStep("failed event is postponed", func(sCtx provider.StepCtx) {
+		err := s.outboxRepo.MarkAsError(s.ctx, []int64{eventID}, testPolicy)
+		sCtx.Require().NoError(err)
+
+		_, ok := findEvent(sCtx)
+		sCtx.Require().False(ok)
+	})
+
+	t.WithNewStep("event becomes dead after max attempts", func(sCtx provider.StepCtx) {
+		err := s.outboxRepo.MarkAsError(s.ctx, []int64{eventID}, testPolicy)
+		sCtx.Require().NoError(err)
+
+		var dead domain.Event
+		cursor := int64(0)
+		for {
+			events, err := s.outboxRepo.ListDeadEvents(s.ctx, domain.DeadEventsFilter{Cursor: cursor, Limit: 100})
+			sCtx.Require().NoError(err)
+
+			for _, event := range events {
+				if event.ID == eventID {
+					dead = event
+				}
+			}
+
+			if dead.ID != 0 || len(events) < 100 {
+				break
+			}
+
+			cursor = events[len(events)-1].ID
+		}
+
+		sCtx.Require().Equal(eventID, dead.ID)
+		sCtx.Require().Equal(domain.EventStatusDead, dead.Status)
+		sCtx.Require().Equal(testPolicy.MaxAttempts, dead.Attempts)
+		sCtx.Require().Equal(testKey, dead.Key)
+	})
+
+	t.WithNewStep("redrive dead event", func(sCtx provider.StepCtx) {
+		redrivenIDs, err := s.outboxRepo.RedriveDeadEvents(s.ctx, []int64{eventID, eventID + 1_000_000})
+		sCtx.Require().NoError(err)
+		sCtx.Require().Equal([]int64{eventID}, redrivenIDs)
+
+		event, ok := findEvent(sCtx)
+		sCtx.Require().True(ok)
+		sCtx.Require().Equal(int32(0), event.Attempts)
+		sCtx.Require().Equal(domain.EventStatusNew, event.Status)
+	})
+}
@@ -0,0 +1,51 @@
+//go:build integration
+// +build integration
+
+package repository_test
+
+import (
+	"route256/loms/internal/domain"
+	"strconv"
+	"time"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+)
+
+func (s *Suite) TestOutboxTraceContext_Success(t provider.T) {
+	t.Parallel()
+
+	t.Title("Outbox event keeps trace context and request id")
+
+	testKey := strconv.FormatInt(time.Now().UnixNano(), 10)
+
+	testEvent := domain.Event{
+		Topic:   "test_topic",
+		Key:     testKey,
+		Payload: []byte(`{"OrderID": 1}`),
+		TraceContext: map[string]string{
+			"uber-trace-id": "4bf92f3577b34da6:4bf92f3577b34da6:0:1",
+		},
+		RequestID: "test-request-id",
+	}
+
+	t.WithNewStep("create event", func(sCtx provider.StepCtx) {
+		err := s.outboxRepo.CreateEvent(s.ctx, testEvent)
+		sCtx.Require().NoError(err)
+	})
+
+	t.WithNewStep("fetch next message", func(sCtx provider.StepCtx) {
+		events, err := s.outboxRepo.FetchNextMessages(s.ctx, 100)
+		sCtx.Require().NoError(err)
+
+		var actualEvent domain.Event
+
+		for idx, event := range events {
+			if event.Key == testKey {
+				actualEvent = events[idx]
+			}
+		}
+
+		sCtx.Require().Equal(testEvent.TraceContext, actualEvent.TraceContext)
+		sCtx.Require().Equal(testEvent.RequestID, actualEvent.RequestID)
+	})
+}
@@ -0,0 +1,54 @@
+//go:build integration
+// +build integration
+
+package repository_test
+
+import (
+	"route256/loms/integration/containers/postgres"
+	pg "route256/loms/internal/infra/postgres"
+	"time"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+)
+
+func (s *Suite) TestReadYourWrites_ReplicaRouting(t provider.T) {
+	t.Parallel()
+
+	t.Title("Reads with a minimal LSN go to the caught-up replica or fall back to master")
+
+	var lsn string
+
+	t.WithNewStep("get commit lsn after write", func(sCtx provider.StepCtx) {
+		orderID, err := s.orderRepo.CreateOrder(s.ctx, 4100)
+		sCtx.Require().NoError(err)
+		sCtx.Require().NotZero(orderID)
+
+		lsn, err = s.pools.CurrentLSN(s.ctx)
+		sCtx.Require().NoError(err)
+		sCtx.Require().NotEmpty(lsn)
+	})
+
+	t.WithNewStep("replica that replayed the lsn is used", func(sCtx provider.StepCtx) {
+		pools, err := pg.New(s.ctx, postgres.MasterDSN, []string{postgres.ReplicaDSN}, pg.WithReplicaWait(2*time.Second))
+		sCtx.Require().NoError(err)
+		defer pools.Close()
+
+		pool := pools.GetReadReplica(pg.WithMinLSN(s.ctx, lsn))
+		sCtx.Require().Same(pools.Replicas[0].Pool, pool)
+	})
+
+	t.WithNewStep("lsn ahead of the replica falls back to master", func(sCtx provider.StepCtx) {
+		pool := s.pools.GetReadReplica(pg.WithMinLSN(s.ctx, "FFFFFFFF/FFFFFFFF"))
+		sCtx.Require().Same(s.pools.Master, pool)
+	})
+
+	t.WithNewStep("master read mode uses master", func(sCtx provider.StepCtx) {
+		pool := s.pools.GetReadReplica(pg.WithMasterRead(s.ctx))
+		sCtx.Require().Same(s.pools.Master, pool)
+	})
+
+	t.WithNewStep("read without lsn uses replica", func(sCtx provider.StepCtx) {
+		pool := s.pools.GetReadReplica(s.ctx)
+		sCtx.Require().Same(s.pools.Replicas[0].Pool, pool)
+	})
+}
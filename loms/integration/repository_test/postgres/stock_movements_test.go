@@ -0,0 +1,88 @@
+//go:build integration
+// +build integration
+
+package repository_test
+
+import (
+	"route256/loms/internal/domain"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+)
+
+func (s *Suite) TestStockMovements_Success(t provider.T) {
+	t.Parallel()
+
+	t.Title("Successful create and list of stock movements")
+
+	var (
+		testSku       = domain.Sku(5550201)
+		testMovements = []domain.StockMovement{
+			{
+				WarehouseID: domain.DefaultWarehouseID,
+				Sku:         testSku,
+				Kind:        domain.StockMovementCreate,
+				TotalDelta:  10,
+				TotalCount:  10,
+			},
+			{
+				WarehouseID:   domain.DefaultWarehouseID,
+				Sku:           testSku,
+				Kind:          domain.StockMovementReserve,
+				OrderID:       101,
+				ReservedDelta: 3,
+				TotalCount:    10,
+				Reserved:      3,
+			},
+			{
+				WarehouseID: domain.DefaultWarehouseID,
+				Sku:         testSku,
+				Kind:        domain.StockMovementAdjust,
+				Reason:      "inventory",
+				TotalDelta:  -2,
+				TotalCount:  8,
+				Reserved:    3,
+			},
+		}
+	)
+
+	t.WithNewStep("create stock movements", func(sCtx provider.StepCtx) {
+		err := s.stockRepo.CreateStockMovements(s.ctx, testMovements)
+		sCtx.Require().NoError(err)
+	})
+
+	t.WithNewStep("list first page", func(sCtx provider.StepCtx) {
+		movements, err := s.stockRepo.ListStockMovements(s.ctx, domain.StockHistoryFilter{
+			Sku:   testSku,
+			Limit: 2,
+		})
+		sCtx.Require().NoError(err)
+		sCtx.Require().Len(movements, 2)
+
+		sCtx.Require().Equal(domain.StockMovementAdjust, movements[0].Kind)
+		sCtx.Require().Equal("inventory", movements[0].Reason)
+		sCtx.Require().Equal(int64(-2), movements[0].TotalDelta)
+
+		sCtx.Require().Equal(domain.StockMovementReserve, movements[1].Kind)
+		sCtx.Require().Equal(int64(101), movements[1].OrderID)
+		sCtx.Require().Equal(int64(3), movements[1].ReservedDelta)
+
+		next, err := s.stockRepo.ListStockMovements(s.ctx, domain.StockHistoryFilter{
+			Sku:    testSku,
+			Cursor: movements[1].ID,
+			Limit:  2,
+		})
+		sCtx.Require().NoError(err)
+		sCtx.Require().Len(next, 1)
+		sCtx.Require().Equal(domain.StockMovementCreate, next[0].Kind)
+		sCtx.Require().Equal(int64(0), next[0].OrderID)
+		sCtx.Require().Empty(next[0].Reason)
+	})
+
+	t.WithNewStep("stock movements are append-only", func(sCtx provider.StepCtx) {
+		_, err := s.pools.Master.Exec(s.ctx, "UPDATE stock_movements SET total_delta = 0 WHERE sku = $1", testSku)
+		sCtx.Require().Error(err)
+
+		_, err = s.pools.Master.Exec(s.ctx, "DELETE FROM stock_movements WHERE sku = $1", testSku)
+		sCtx.Require().Error(err)
+	})
+}
@@ -100,7 +100,7 @@ func (s *Suite) BeforeAll(t provider.T) {
 		sCtx.Require().NoError(err)
 
 		time.Sleep(5 * time.Second)
-		pools, err := pg.New(s.ctx, postgres.MasterDSN, postgres.ReplicaDSN)
+		pools, err := pg.New(s.ctx, postgres.MasterDSN, []string{postgres.ReplicaDSN})
 		require.NoError(t, err)
 
 		s.pools = pools
@@ -128,11 +128,12 @@ func (s *Suite) BeforeAll(t provider.T) {
 		s.testData.testTotalCount3 = int64(150)
 
 		const insertQuery = `
-			INSERT INTO stocks (sku, total_count, reserved)
-			VALUES ($1, $2, $3), ($4, $5, $6), ($7, $8, $9)`
+			INSERT INTO stocks (warehouse_id, sku, total_count, reserved)
+			VALUES ($1, $2, $3, $4), ($1, $5, $6, $7), ($1, $8, $9, $10)`
 
 		_, err := s.pools.Master.Exec(
 			s.ctx, insertQuery,
+			domain.DefaultWarehouseID,
 			s.testData.testSku1, s.testData.testTotalCount1, s.testData.testReserved1,
 			s.testData.testSku2, s.testData.testTotalCount2, s.testData.testReserved2,
 			s.testData.testSku3, s.testData.testTotalCount3, s.testData.testReserved3,
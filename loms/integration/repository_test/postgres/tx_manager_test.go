@@ -0,0 +1,112 @@
+//go:build integration
+// +build integration
+
+package repository_test
+
+import (
+	"context"
+	"route256/loms/internal/domain"
+	txmanager "route256/loms/internal/infra/tx_manager"
+	"sync"
+	"sync/atomic"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+)
+
+func (s *Suite) TestTxManagerSerializable_RetryOnConflict(t provider.T) {
+	t.Parallel()
+
+	t.Title("Serializable transaction is retried on serialization failure")
+
+	testSku := domain.Sku(5550401)
+
+	t.WithNewStep("insert stock", func(sCtx provider.StepCtx) {
+		_, err := s.pools.Master.Exec(s.ctx,
+			`INSERT INTO stocks (warehouse_id, sku, total_count, reserved) VALUES ($1, $2, 0, 0)`,
+			domain.DefaultWarehouseID, testSku)
+		sCtx.Require().NoError(err)
+	})
+
+	var (
+		attempts atomic.Int32
+		barrier  sync.WaitGroup
+	)
+	barrier.Add(2)
+
+	increment := func(ctx context.Context) error {
+		tx := ctx.Value(txmanager.TxKey).(pgx.Tx)
+
+		var total int64
+		if err := tx.QueryRow(ctx,
+			`SELECT total_count FROM stocks WHERE warehouse_id = $1 AND sku = $2`,
+			domain.DefaultWarehouseID, testSku).Scan(&total); err != nil {
+			return err
+		}
+
+		if attempts.Add(1) <= 2 {
+			barrier.Done()
+			barrier.Wait()
+		}
+
+		_, err := tx.Exec(ctx,
+			`UPDATE stocks SET total_count = $1 WHERE warehouse_id = $2 AND sku = $3`,
+			total+1, domain.DefaultWarehouseID, testSku)
+		return err
+	}
+
+	t.WithNewStep("run concurrent increments", func(sCtx provider.StepCtx) {
+		errs := make([]error, 2)
+
+		var wg sync.WaitGroup
+		for idx := range errs {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs[idx] = s.txManger.Serializable(s.ctx, increment)
+			}()
+		}
+		wg.Wait()
+
+		for _, err := range errs {
+			sCtx.Require().NoError(err)
+		}
+		sCtx.Require().Greater(attempts.Load(), int32(2))
+	})
+
+	t.WithNewStep("check result", func(sCtx provider.StepCtx) {
+		var total int64
+		err := s.pools.Master.QueryRow(s.ctx,
+			`SELECT total_count FROM stocks WHERE warehouse_id = $1 AND sku = $2`,
+			domain.DefaultWarehouseID, testSku).Scan(&total)
+		sCtx.Require().NoError(err)
+		sCtx.Require().Equal(int64(2), total)
+	})
+}
+
+func (s *Suite) TestTxManagerReadOnly_UsesReplica(t provider.T) {
+	t.Parallel()
+
+	t.Title("Read-only transaction runs on the replica")
+
+	txManager := txmanager.New(s.pools.Master, txmanager.WithReplica(s.pools.ReadTransactor()))
+
+	t.WithNewStep("read-only tx is in recovery", func(sCtx provider.StepCtx) {
+		var inRecovery bool
+		err := txManager.ReadOnly(s.ctx, func(ctx context.Context) error {
+			tx := ctx.Value(txmanager.TxKey).(pgx.Tx)
+			return tx.QueryRow(ctx, `SELECT pg_is_in_recovery()`).Scan(&inRecovery)
+		})
+		sCtx.Require().NoError(err)
+		sCtx.Require().True(inRecovery)
+	})
+
+	t.WithNewStep("read-only tx rejects writes", func(sCtx provider.StepCtx) {
+		err := txManager.ReadOnly(s.ctx, func(ctx context.Context) error {
+			tx := ctx.Value(txmanager.TxKey).(pgx.Tx)
+			_, err := tx.Exec(ctx, `UPDATE stocks SET reserved = reserved WHERE sku = 0`)
+			return err
+		})
+		sCtx.Require().Error(err)
+	})
+}
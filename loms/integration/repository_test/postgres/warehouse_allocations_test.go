@@ -0,0 +1,104 @@
+//go:build integration
+// +build integration
+
+package repository_test
+
+import (
+	"context"
+	"route256/loms/internal/domain"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+)
+
+func (s *Suite) TestWarehouseStocks_Success(t provider.T) {
+	t.Parallel()
+
+	t.Title("Successful get of stock per warehouse")
+
+	var (
+		testSku    = domain.Sku(5550101)
+		testStocks = []domain.WarehouseStock{
+			{
+				WarehouseID: domain.DefaultWarehouseID,
+				Sku:         testSku,
+				TotalCount:  10,
+			},
+			{
+				WarehouseID: 2,
+				Sku:         testSku,
+				TotalCount:  15,
+			},
+		}
+	)
+
+	t.WithNewStep("create stock in two warehouses", func(sCtx provider.StepCtx) {
+		for _, stock := range testStocks {
+			err := s.stockRepo.CreateStock(s.ctx, stock)
+			sCtx.Require().NoError(err)
+		}
+	})
+
+	t.WithNewStep("get stock per warehouse", func(sCtx provider.StepCtx) {
+		stocks, err := s.stockRepo.GetWarehouseStocksBySku(s.ctx, testSku)
+		sCtx.Require().NoError(err)
+		sCtx.Require().Equal(testStocks, stocks)
+	})
+
+	t.WithNewStep("get summary stock", func(sCtx provider.StepCtx) {
+		stock, err := s.stockRepo.GetStockBySku(s.ctx, testSku)
+		sCtx.Require().NoError(err)
+		sCtx.Require().Equal(int64(25), stock.TotalCount)
+		sCtx.Require().Equal(int64(0), stock.Reserved)
+	})
+}
+
+func (s *Suite) TestOrderAllocations_Success(t provider.T) {
+	t.Parallel()
+
+	t.Title("Successful create and decrease of order allocations")
+
+	var (
+		testUserID      int64 = 778
+		testAllocations       = []domain.Allocation{
+			{Sku: domain.Sku(1076963), WarehouseID: domain.DefaultWarehouseID, Count: 2},
+			{Sku: domain.Sku(1076963), WarehouseID: 2, Count: 1},
+			{Sku: domain.Sku(1148162), WarehouseID: 2, Count: 1},
+		}
+		orderID int64
+	)
+
+	t.WithNewStep("create order allocations", func(sCtx provider.StepCtx) {
+		err := s.txManger.ReadCommitted(s.ctx, func(txCtx context.Context) error {
+			var err error
+
+			orderID, err = s.orderRepo.CreateOrder(txCtx, testUserID)
+			sCtx.Require().NoError(err)
+
+			return s.orderRepo.CreateOrderAllocations(txCtx, orderID, testAllocations)
+		})
+		sCtx.Require().NoError(err)
+
+		allocations, err := s.orderRepo.GetAllocationsByOrderID(s.ctx, orderID)
+		sCtx.Require().NoError(err)
+		sCtx.Require().Equal(testAllocations, allocations)
+	})
+
+	t.WithNewStep("decrease order allocations", func(sCtx provider.StepCtx) {
+		err := s.orderRepo.DecreaseOrderAllocations(s.ctx, orderID, []domain.Allocation{
+			{Sku: domain.Sku(1076963), WarehouseID: 2, Count: 1},
+			{Sku: domain.Sku(1148162), WarehouseID: 2, Count: 1},
+		})
+		sCtx.Require().NoError(err)
+
+		allocations, err := s.orderRepo.GetAllocationsByOrderID(s.ctx, orderID)
+		sCtx.Require().NoError(err)
+		sCtx.Require().Equal(testAllocations[:1], allocations)
+	})
+
+	t.WithNewStep("cannot decrease more than allocated", func(sCtx provider.StepCtx) {
+		err := s.orderRepo.DecreaseOrderAllocations(s.ctx, orderID, []domain.Allocation{
+			{Sku: domain.Sku(1076963), WarehouseID: domain.DefaultWarehouseID, Count: 3},
+		})
+		sCtx.Require().Error(err)
+	})
+}
@@ -0,0 +1,177 @@
+//go:build integration
+// +build integration
+
+package sharding_test
+
+import (
+	"errors"
+	"route256/loms/integration/containers/postgres"
+	"route256/loms/internal/domain"
+	pg "route256/loms/internal/infra/postgres"
+	"route256/loms/internal/infra/sharding"
+	txmanager "route256/loms/internal/infra/tx_manager"
+	"strconv"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+)
+
+func (s *Suite) TestShardedOrders_CreateRoutesByUserID(t provider.T) {
+	t.Title("Orders are stored on the shard of their user and keep the shard in the order id")
+
+	for _, userID := range []int64{101, 102, 103, 104} {
+		t.WithNewStep("create order", func(sCtx provider.StepCtx) {
+			shard := sharding.ShardByUserID(userID, s.shards.Count())
+
+			orderID, err := s.orderService.OrderCreate(s.ctx, domain.Order{
+				UserID: userID,
+				Items:  []domain.Item{{Sku: testSku, Count: 1}},
+			})
+			sCtx.Require().NoError(err)
+			sCtx.Require().Equal(shard, sharding.ShardByID(orderID))
+
+			for idx, pools := range s.shardPools {
+				expected := 0
+				if idx == shard {
+					expected = 1
+				}
+
+				sCtx.Require().Equal(expected, s.countRows(sCtx, pools, `SELECT count(*) FROM orders WHERE id = $1`, orderID))
+				sCtx.Require().Equal(expected, s.countRows(sCtx, pools, `SELECT count(*) FROM order_items WHERE order_id = $1`, orderID))
+			}
+
+			sCtx.Require().Zero(s.countRows(sCtx, s.stockPools, `SELECT count(*) FROM orders WHERE id = $1`, orderID))
+
+			order, err := s.orderService.OrderInfo(s.ctx, orderID)
+			sCtx.Require().NoError(err)
+			sCtx.Require().Equal(userID, order.UserID)
+			sCtx.Require().Equal(domain.OrderStatusAwaitingPayment, order.Status)
+		})
+	}
+}
+
+func (s *Suite) TestShardedOrders_PayAndCancelRouteByOrderID(t provider.T) {
+	t.Title("Pay and cancel find the shard from the order id and keep outbox events on that shard")
+
+	var paidID, cancelledID int64
+
+	t.WithNewStep("create orders", func(sCtx provider.StepCtx) {
+		var err error
+		paidID, err = s.orderService.OrderCreate(s.ctx, domain.Order{UserID: 201, Items: []domain.Item{{Sku: testSku, Count: 2}}})
+		sCtx.Require().NoError(err)
+
+		cancelledID, err = s.orderService.OrderCreate(s.ctx, domain.Order{UserID: 202, Items: []domain.Item{{Sku: testSku, Count: 3}}})
+		sCtx.Require().NoError(err)
+	})
+
+	t.WithNewStep("pay and cancel", func(sCtx provider.StepCtx) {
+		sCtx.Require().NoError(s.orderService.OrderPay(s.ctx, paidID))
+		sCtx.Require().NoError(s.orderService.OrderCancel(s.ctx, cancelledID))
+
+		paid, err := s.orderService.OrderInfo(s.ctx, paidID)
+		sCtx.Require().NoError(err)
+		sCtx.Require().Equal(domain.OrderStatusPayed, paid.Status)
+
+		cancelled, err := s.orderService.OrderInfo(s.ctx, cancelledID)
+		sCtx.Require().NoError(err)
+		sCtx.Require().Equal(domain.OrderStatusCancelled, cancelled.Status)
+	})
+
+	t.WithNewStep("outbox events stay on the order shard", func(sCtx provider.StepCtx) {
+		for _, orderID := range []int64{paidID, cancelledID} {
+			shard := sharding.ShardByID(orderID)
+
+			for idx, pools := range s.shardPools {
+				count := s.countRows(sCtx, pools, `SELECT count(*) FROM outbox WHERE key = $1`, strconv.FormatInt(orderID, 10))
+				if idx == shard {
+					sCtx.Require().Equal(3, count)
+				} else {
+					sCtx.Require().Zero(count)
+				}
+			}
+
+			sCtx.Require().Zero(s.countRows(sCtx, s.stockPools, `SELECT count(*) FROM outbox`))
+		}
+	})
+
+	t.WithNewStep("outbox ids carry the shard", func(sCtx provider.StepCtx) {
+		for idx := range s.shardPools {
+			events, err := s.outboxRepo.FetchNextMessages(sharding.WithShard(s.ctx, idx), 100)
+			sCtx.Require().NoError(err)
+
+			for _, event := range events {
+				sCtx.Require().Equal(idx, sharding.ShardByID(event.ID))
+			}
+		}
+	})
+}
+
+func (s *Suite) TestShardedOrders_ListByUserID(t provider.T) {
+	t.Title("Order list reads the shard of the user")
+
+	const userID = int64(301)
+
+	t.WithNewStep("create and list orders", func(sCtx provider.StepCtx) {
+		for range 3 {
+			_, err := s.orderService.OrderCreate(s.ctx, domain.Order{UserID: userID, Items: []domain.Item{{Sku: testSku, Count: 1}}})
+			sCtx.Require().NoError(err)
+		}
+
+		orders, _, err := s.orderService.OrderList(s.ctx, domain.OrderListFilter{UserID: userID, Limit: 10})
+		sCtx.Require().NoError(err)
+		sCtx.Require().Len(orders, 3)
+
+		for _, order := range orders {
+			sCtx.Require().Equal(userID, order.UserID)
+		}
+	})
+}
+
+func (s *Suite) TestShardedOrders_StocksStayOnDedicatedDatabase(t provider.T) {
+	t.Title("Stock reservations are written to the stock database only")
+
+	t.WithNewStep("reserve stock", func(sCtx provider.StepCtx) {
+		orderID, err := s.orderService.OrderCreate(s.ctx, domain.Order{UserID: 401, Items: []domain.Item{{Sku: testSku, Count: 5}}})
+		sCtx.Require().NoError(err)
+
+		sCtx.Require().Equal(1, s.countRows(sCtx, s.stockPools,
+			`SELECT count(*) FROM stock_movements WHERE order_id = $1`, orderID))
+
+		for _, pools := range s.shardPools {
+			sCtx.Require().Zero(s.countRows(sCtx, pools, `SELECT count(*) FROM stock_movements`))
+		}
+	})
+
+	t.WithNewStep("not enough stock fails the order on its shard", func(sCtx provider.StepCtx) {
+		_, err := s.orderService.OrderCreate(s.ctx, domain.Order{UserID: 402, Items: []domain.Item{{Sku: testSku, Count: testTotalCount * 2}}})
+		sCtx.Require().ErrorIs(err, domain.ErrNotEnoughStock)
+
+		shard := sharding.ShardByUserID(402, s.shards.Count())
+		sCtx.Require().Equal(1, s.countRows(sCtx, s.shardPools[shard],
+			`SELECT count(*) FROM orders WHERE user_id = $1 AND status = 'failed'`, int64(402)))
+	})
+}
+
+func (s *Suite) TestShardedOrders_ShardMismatch(t provider.T) {
+	t.Title("Swapping shard databases that already hold orders is rejected")
+
+	t.WithNewStep("create order on every shard", func(sCtx provider.StepCtx) {
+		for shard := range s.shardPools {
+			_, err := s.orderRepo.CreateOrder(sharding.WithShard(s.ctx, shard), 500+int64(shard))
+			sCtx.Require().NoError(err)
+		}
+	})
+
+	t.WithNewStep("swapped shards fail", func(sCtx provider.StepCtx) {
+		shard0, err := pg.New(s.ctx, postgres.Shard0DSN, nil)
+		sCtx.Require().NoError(err)
+		defer shard0.Close()
+
+		shard1, err := pg.New(s.ctx, postgres.Shard1DSN, nil)
+		sCtx.Require().NoError(err)
+		defer shard1.Close()
+
+		_, err = pg.NewShards(s.ctx, txmanager.ShardTxKey, shard1, shard0)
+		sCtx.Require().Error(err)
+		sCtx.Require().True(errors.Is(err, pg.ErrShardMismatch))
+	})
+}
@@ -0,0 +1,172 @@
+//go:build integration
+// +build integration
+
+package sharding_test
+
+import (
+	"context"
+	"route256/loms/integration/containers/postgres"
+	"route256/loms/internal/adapter/payment/fake"
+	orderrepository "route256/loms/internal/adapter/repository/postgtres/order"
+	outboxrepository "route256/loms/internal/adapter/repository/postgtres/outbox"
+	paymentrepository "route256/loms/internal/adapter/repository/postgtres/payment"
+	stockrepository "route256/loms/internal/adapter/repository/postgtres/stock"
+	orderservice "route256/loms/internal/business/service/order"
+	"route256/loms/internal/business/service/order/watch"
+	stockservice "route256/loms/internal/business/service/stock"
+	"route256/loms/internal/business/service/stock/allocation"
+	"route256/loms/internal/domain"
+	"route256/loms/internal/infra/logger"
+	"route256/loms/internal/infra/metrics"
+	pg "route256/loms/internal/infra/postgres"
+	txmanager "route256/loms/internal/infra/tx_manager"
+	"route256/loms/migration"
+	"testing"
+	"time"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	tc "github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/network"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	testSku         = domain.Sku(4242)
+	testTotalCount  = int64(1000)
+	testWarehouseID = domain.DefaultWarehouseID
+)
+
+type Suite struct {
+	suite.Suite
+	ctx context.Context
+
+	stockPools *pg.Pools
+	shardPools []*pg.Pools
+	shards     *pg.Shards
+
+	orderRepo    *orderrepository.Repository
+	outboxRepo   *outboxrepository.Repository
+	stockRepo    *stockrepository.Repository
+	orderService *orderservice.Service
+
+	containers []tc.Container
+	network    *tc.DockerNetwork
+}
+
+func (s *Suite) BeforeAll(t provider.T) {
+	s.ctx = context.Background()
+
+	t.WithNewStep("create network", func(sCtx provider.StepCtx) {
+		net, err := network.New(s.ctx)
+		sCtx.Require().NoError(err)
+		s.network = net
+	})
+
+	for _, opt := range []postgres.Opt{
+		{ContainerName: postgres.PostgresStockContainerName, Port: postgres.PostgresStockPort},
+		{ContainerName: postgres.PostgresShard0ContainerName, Port: postgres.PostgresShard0Port},
+		{ContainerName: postgres.PostgresShard1ContainerName, Port: postgres.PostgresShard1Port},
+	} {
+		t.WithNewStep("create "+opt.ContainerName+" container", func(sCtx provider.StepCtx) {
+			opt.Env = postgres.StandaloneENV
+			opt.Network = s.network
+
+			ctn, err := postgres.NewContainer(s.ctx, opt)
+			sCtx.Require().NoError(err, "create postgres container")
+			s.containers = append(s.containers, ctn)
+		})
+	}
+
+	t.WithNewStep("init shards", func(sCtx provider.StepCtx) {
+		err := metrics.Init(s.ctx)
+		sCtx.Require().NoError(err)
+
+		err = logger.Init(zapcore.DebugLevel)
+		sCtx.Require().NoError(err)
+
+		time.Sleep(5 * time.Second)
+
+		for _, dsn := range []string{postgres.StockDSN, postgres.Shard0DSN, postgres.Shard1DSN} {
+			err = migration.RunMigrations(dsn)
+			sCtx.Require().NoError(err)
+		}
+
+		s.stockPools, err = pg.New(s.ctx, postgres.StockDSN, nil)
+		sCtx.Require().NoError(err)
+
+		for _, dsn := range []string{postgres.Shard0DSN, postgres.Shard1DSN} {
+			pools, err := pg.New(s.ctx, dsn, nil)
+			sCtx.Require().NoError(err)
+
+			s.shardPools = append(s.shardPools, pools)
+		}
+
+		s.shards, err = pg.NewShards(s.ctx, txmanager.ShardTxKey, s.shardPools...)
+		sCtx.Require().NoError(err)
+	})
+
+	t.WithNewStep("init services", func(sCtx provider.StepCtx) {
+		s.stockRepo = stockrepository.New(s.stockPools)
+		s.outboxRepo = outboxrepository.New(s.shards)
+		s.orderRepo = orderrepository.New(s.shards, s.outboxRepo)
+
+		stockService := stockservice.New(
+			s.stockRepo,
+			allocation.NewPriorityStrategy(nil),
+			txmanager.New(s.stockPools.Master),
+		)
+
+		s.orderService = orderservice.New(
+			"loms.order-events",
+			s.orderRepo,
+			s.outboxRepo,
+			stockService,
+			txmanager.New(s.shards, txmanager.WithTxKey(s.shards.TxKey())),
+			watch.New(1, watch.SlowConsumerDropOldest),
+			paymentrepository.New(s.shards),
+			fake.New(fake.ModeApprove, time.Second),
+			true,
+		)
+
+		err := s.stockRepo.CreateStock(s.ctx, domain.WarehouseStock{
+			WarehouseID: testWarehouseID,
+			Sku:         testSku,
+			TotalCount:  testTotalCount,
+		})
+		sCtx.Require().NoError(err)
+	})
+}
+
+func (s *Suite) AfterAll(t provider.T) {
+	t.WithNewStep("close pools", func(_ provider.StepCtx) {
+		s.shards.Close()
+		s.stockPools.Close()
+	})
+
+	t.WithNewStep("stop postgres containers", func(sCtx provider.StepCtx) {
+		for _, ctn := range s.containers {
+			err := ctn.Terminate(s.ctx)
+			sCtx.Require().NoError(err, "terminate postgres container")
+		}
+	})
+
+	t.WithNewStep("remove network", func(sCtx provider.StepCtx) {
+		err := s.network.Remove(s.ctx)
+		sCtx.Require().NoError(err, "network removing")
+	})
+}
+
+func (s *Suite) countRows(sCtx provider.StepCtx, pools *pg.Pools, query string, args ...any) int {
+	var count int
+	err := pools.Master.QueryRow(s.ctx, query, args...).Scan(&count)
+	sCtx.Require().NoError(err)
+
+	return count
+}
+
+func TestShardedOrders(t *testing.T) {
+	t.Parallel()
+
+	suite.RunSuite(t, new(Suite))
+}
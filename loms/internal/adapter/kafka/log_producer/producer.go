@@ -0,0 +1,28 @@
+package logproducer
+
+import (
+	"context"
+	"route256/loms/internal/domain"
+	"route256/loms/internal/infra/logger"
+)
+
+type Producer struct{}
+
+func New() *Producer {
+	return &Producer{}
+}
+
+func (p *Producer) SendOrderEventsBatch(ctx context.Context, orderEvents []domain.Event) (successIDs []int64, errorIDs []int64, err error) {
+	successIDs = make([]int64, 0, len(orderEvents))
+
+	for _, event := range orderEvents {
+		logger.Infof(ctx, "order event %d to %s (key %s): %s", event.ID, event.Topic, event.Key, event.Payload)
+		successIDs = append(successIDs, event.ID)
+	}
+
+	return successIDs, nil, nil
+}
+
+func (p *Producer) Ping(_ context.Context) error {
+	return nil
+}
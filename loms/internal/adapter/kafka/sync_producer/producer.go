@@ -2,21 +2,34 @@ package syncproducer
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"route256/loms/internal/domain"
 	"route256/loms/internal/infra/config"
 	"route256/loms/internal/infra/logger"
 	"route256/loms/internal/infra/metrics"
+	"route256/loms/internal/infra/tracing"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/IBM/sarama"
 	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
 )
 
+const EventIDHeader = "x-event-id"
+
 type Producer struct {
-	prc        sarama.SyncProducer
-	orderTopic string
+	client        sarama.Client
+	prc           sarama.SyncProducer
+	orderTopic    string
+	transactional bool
+
+	// txnMu serializes transactions of the per-shard relays sharing the producer,
+	// sarama allows only one open transaction per transactional id.
+	txnMu sync.Mutex
 }
 
 func New(ctx context.Context, kafkaConfig config.Config) (
@@ -27,21 +40,35 @@ func New(ctx context.Context, kafkaConfig config.Config) (
 	config.Producer.Return.Successes = true
 	config.Producer.Return.Errors = true
 
+	if kafkaConfig.Kafka.Transactional {
+		config.Producer.Idempotent = true
+		// The id is the same on every replica: relays run on the leader only, and
+		// a new leader initializing the same id fences the previous one.
+		config.Producer.Transaction.ID = kafkaConfig.Kafka.TransactionalID
+		config.Net.MaxOpenRequests = 1
+	}
+
 	borkerList := strings.Split(kafkaConfig.Kafka.Brokers, ",")
 
-	prc, err := sarama.NewSyncProducer(borkerList, config)
+	client, err := sarama.NewClient(borkerList, config)
 	if err != nil {
-		return nil, fmt.Errorf("sarama.NewSyncProducer %v", err)
+		return nil, fmt.Errorf("sarama.NewClient %v", err)
 	}
 
-	logger.Infof(ctx, "sync producer successfully created")
-
-	producer := Producer{
-		prc:        prc,
-		orderTopic: kafkaConfig.Kafka.OrderTopic,
+	prc, err := sarama.NewSyncProducerFromClient(client)
+	if err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("sarama.NewSyncProducer %v", err)
 	}
 
-	return &producer, nil
+	logger.Infof(ctx, "sync producer successfully created, transactional = %v", kafkaConfig.Kafka.Transactional)
+
+	return &Producer{
+		client:        client,
+		prc:           prc,
+		orderTopic:    kafkaConfig.Kafka.OrderTopic,
+		transactional: kafkaConfig.Kafka.Transactional,
+	}, nil
 }
 
 func (p *Producer) SendOrderEventsBatch(ctx context.Context, orderEvents []domain.Event) (successIDs []int64, errorIDs []int64, err error) {
@@ -50,20 +77,40 @@ func (p *Producer) SendOrderEventsBatch(ctx context.Context, orderEvents []domai
 
 	messages := make([]*sarama.ProducerMessage, 0, len(orderEvents))
 	msgMap := make(map[*sarama.ProducerMessage]int64, len(orderEvents))
+	msgSpans := make(map[int64]opentracing.Span, len(orderEvents))
+
+	defer func() {
+		for _, msgSpan := range msgSpans {
+			msgSpan.Finish()
+		}
+	}()
 
 	for _, event := range orderEvents {
+		msgSpan, msgCtx := p.startMessageSpan(event)
+		msgSpans[event.ID] = msgSpan
+
 		msg := &sarama.ProducerMessage{
-			Key:   sarama.StringEncoder(event.Key),
-			Topic: p.orderTopic,
-			Value: sarama.StringEncoder(event.Payload),
+			Key:     sarama.StringEncoder(event.Key),
+			Topic:   p.orderTopic,
+			Value:   sarama.ByteEncoder(event.Payload),
+			Headers: messageHeaders(msgCtx, event),
 		}
 
 		messages = append(messages, msg)
 		msgMap[msg] = event.ID
 	}
 
+	if p.transactional {
+		p.txnMu.Lock()
+		defer p.txnMu.Unlock()
+	}
+
 	start := time.Now()
-	err = p.prc.SendMessages(messages)
+	if p.transactional {
+		err = p.sendMessagesInTxn(messages)
+	} else {
+		err = p.prc.SendMessages(messages)
+	}
 	duration := time.Since(start).Seconds()
 
 	if err == nil {
@@ -80,6 +127,22 @@ func (p *Producer) SendOrderEventsBatch(ctx context.Context, orderEvents []domai
 	metrics.IncKafkaProduceCounter(p.orderTopic, "error")
 	metrics.KafkaProduceDurationHistogram(p.orderTopic, "error", duration)
 
+	if p.transactional {
+		if p.prc.TxnStatus()&sarama.ProducerTxnFlagFatalError != 0 {
+			return nil, nil, fmt.Errorf("transaction failed: %w", err)
+		}
+
+		logger.Warnf(ctx, "transaction aborted, batch of %d events will be retried: %v", len(orderEvents), err)
+
+		errorIDs = make([]int64, 0, len(orderEvents))
+		for _, event := range orderEvents {
+			errorIDs = append(errorIDs, event.ID)
+			ext.Error.Set(msgSpans[event.ID], true)
+		}
+
+		return nil, errorIDs, nil
+	}
+
 	producerErrors, ok := err.(sarama.ProducerErrors)
 	if !ok {
 		return nil, nil, fmt.Errorf("prc.SendMessages: %w", err)
@@ -94,6 +157,9 @@ func (p *Producer) SendOrderEventsBatch(ctx context.Context, orderEvents []domai
 
 			errorIDs = append(errorIDs, id)
 			failed[id] = struct{}{}
+
+			ext.Error.Set(msgSpans[id], true)
+			msgSpans[id].SetTag("err", pe.Err.Error())
 		}
 	}
 
@@ -107,11 +173,98 @@ func (p *Producer) SendOrderEventsBatch(ctx context.Context, orderEvents []domai
 	return successIDs, errorIDs, nil
 }
 
+func (p *Producer) startMessageSpan(event domain.Event) (opentracing.Span, context.Context) {
+	msgSpan := opentracing.StartSpan(
+		"kafkaProducer.SendOrderEvent",
+		opentracing.FollowsFrom(tracing.Extract(event.TraceContext)),
+		ext.SpanKindProducer,
+	)
+	ext.MessageBusDestination.Set(msgSpan, p.orderTopic)
+	msgSpan.SetTag("outbox.event_id", event.ID)
+	msgSpan.SetTag("request_id", event.RequestID)
+
+	return msgSpan, opentracing.ContextWithSpan(context.Background(), msgSpan)
+}
+
+func (p *Producer) sendMessagesInTxn(messages []*sarama.ProducerMessage) error {
+	if err := p.prc.BeginTxn(); err != nil {
+		return fmt.Errorf("prc.BeginTxn: %w", err)
+	}
+
+	if err := p.prc.SendMessages(messages); err != nil {
+		return p.abortTxn(fmt.Errorf("prc.SendMessages: %w", err))
+	}
+
+	if err := p.prc.CommitTxn(); err != nil {
+		return p.abortTxn(fmt.Errorf("prc.CommitTxn: %w", err))
+	}
+
+	return nil
+}
+
+func (p *Producer) abortTxn(cause error) error {
+	if p.prc.TxnStatus()&sarama.ProducerTxnFlagAbortableError == 0 {
+		return cause
+	}
+
+	if err := p.prc.AbortTxn(); err != nil {
+		return errors.Join(cause, fmt.Errorf("prc.AbortTxn: %w", err))
+	}
+
+	return cause
+}
+
+func messageHeaders(ctx context.Context, event domain.Event) []sarama.RecordHeader {
+	carrier := tracing.Inject(ctx)
+
+	headers := make([]sarama.RecordHeader, 0, len(carrier)+2)
+	for key, value := range carrier {
+		headers = append(headers, sarama.RecordHeader{Key: []byte(key), Value: []byte(value)})
+	}
+
+	headers = append(headers, sarama.RecordHeader{
+		Key:   []byte(EventIDHeader),
+		Value: []byte(eventID(event)),
+	})
+
+	if event.RequestID != "" {
+		headers = append(headers, sarama.RecordHeader{Key: []byte(tracing.RequestIDHeader), Value: []byte(event.RequestID)})
+	}
+
+	return headers
+}
+
+// eventID is the id of the event payload, so the header and the message agree.
+// Rows written before the id was stored fall back to the outbox row id.
+func eventID(event domain.Event) string {
+	if event.EventID != "" {
+		return event.EventID
+	}
+
+	return strconv.FormatInt(event.ID, 10)
+}
+
+func (p *Producer) Ping(_ context.Context) error {
+	if p.client.Closed() {
+		return sarama.ErrClosedClient
+	}
+
+	if err := p.client.RefreshMetadata(p.orderTopic); err != nil {
+		return fmt.Errorf("client.RefreshMetadata: %w", err)
+	}
+
+	return nil
+}
+
 func (p *Producer) Close(ctx context.Context) error {
 	if err := p.prc.Close(); err != nil {
 		return fmt.Errorf("producer.Close: %w", err)
 	}
 
+	if err := p.client.Close(); err != nil {
+		return fmt.Errorf("client.Close: %w", err)
+	}
+
 	logger.Infof(ctx, "Kafka producer closed successfully")
 
 	return nil
@@ -0,0 +1,111 @@
+package fake
+
+import (
+	"context"
+	"errors"
+	"route256/loms/internal/domain"
+	"time"
+)
+
+const (
+	Name = "fake"
+
+	paymentIDPrefix = "fake_"
+	declineReason   = "card declined"
+)
+
+type Mode string
+
+const (
+	ModeApprove Mode = "approve"
+	ModeDecline Mode = "decline"
+	ModeTimeout Mode = "timeout"
+	ModePending Mode = "pending"
+)
+
+var ErrTimeout = errors.New("fake payment provider timed out")
+
+type Provider struct {
+	mode    Mode
+	timeout time.Duration
+}
+
+func New(mode Mode, timeout time.Duration) *Provider {
+	return &Provider{
+		mode:    mode,
+		timeout: timeout,
+	}
+}
+
+func (p *Provider) Name() string {
+	return Name
+}
+
+func (p *Provider) Authorize(ctx context.Context, req domain.PaymentRequest) (domain.PaymentResult, error) {
+	result := domain.PaymentResult{
+		ProviderPaymentID: paymentIDPrefix + req.IdempotencyKey,
+		Amount:            req.Amount,
+		Currency:          req.Currency,
+	}
+
+	switch p.mode {
+	case ModeDecline:
+		result.Status = domain.PaymentStatusDeclined
+		result.Reason = declineReason
+	case ModeTimeout:
+		return domain.PaymentResult{}, p.wait(ctx)
+	case ModePending:
+		result.Status = domain.PaymentStatusPending
+	default:
+		result.Status = domain.PaymentStatusAuthorized
+	}
+
+	return result, nil
+}
+
+func (p *Provider) Capture(
+	ctx context.Context,
+	providerPaymentID string,
+	amount int64,
+	currency string) (domain.PaymentResult, error) {
+	if p.mode == ModeTimeout {
+		return domain.PaymentResult{}, p.wait(ctx)
+	}
+
+	return domain.PaymentResult{
+		ProviderPaymentID: providerPaymentID,
+		Status:            domain.PaymentStatusCaptured,
+		Amount:            amount,
+		Currency:          currency,
+	}, nil
+}
+
+func (p *Provider) Refund(
+	ctx context.Context,
+	providerPaymentID string,
+	amount int64,
+	currency string,
+	_ string) (domain.PaymentResult, error) {
+	if p.mode == ModeTimeout {
+		return domain.PaymentResult{}, p.wait(ctx)
+	}
+
+	return domain.PaymentResult{
+		ProviderPaymentID: providerPaymentID,
+		Status:            domain.PaymentStatusRefunded,
+		Amount:            amount,
+		Currency:          currency,
+	}, nil
+}
+
+func (p *Provider) wait(ctx context.Context) error {
+	timer := time.NewTimer(p.timeout)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return ErrTimeout
+	}
+}
@@ -1,61 +1,325 @@
 package order
 
 import (
+	"cmp"
+	"context"
+	"fmt"
+	"route256/loms/internal/adapter/repository/in_memory/storage"
 	"route256/loms/internal/domain"
-	"sync"
+	"slices"
+	"time"
 )
 
-type orderByID = map[int64]domain.Order
-
-type sequenceGenerator interface {
-	Add(delta int64) (updated int64)
+type outboxRepository interface {
+	CreateEvent(ctx context.Context, events domain.Event) error
 }
 
 type Repository struct {
-	orderByID orderByID
-	mx        sync.RWMutex
-
-	sequenceGenerator sequenceGenerator
+	storage          *storage.Storage
+	outboxRepository outboxRepository
 }
 
-func New(sequenceGenerator sequenceGenerator) *Repository {
+func New(storage *storage.Storage, outboxRepository outboxRepository) *Repository {
 	return &Repository{
-		orderByID:         make(orderByID),
-		sequenceGenerator: sequenceGenerator,
+		storage:          storage,
+		outboxRepository: outboxRepository,
 	}
 }
 
-func (r *Repository) CreateOrder(order domain.Order) (int64, error) {
-	r.mx.Lock()
-	defer r.mx.Unlock()
+func (r *Repository) CreateOrder(ctx context.Context, userID int64) (orderID int64, err error) {
+	err = r.storage.Do(ctx, func(tx *storage.Tx) error {
+		now := time.Now()
+		orderID = tx.NextOrderID()
+
+		tx.PutOrder(storage.Order{
+			ID:        orderID,
+			UserID:    userID,
+			Status:    domain.OrderStatusNew,
+			CreatedAt: now,
+			UpdatedAt: now,
+			Currency:  domain.DefaultCurrency,
+			History: []domain.OrderStatusChange{{
+				Status:    domain.OrderStatusNew,
+				ChangedAt: now,
+			}},
+		})
+
+		return nil
+	})
+
+	return orderID, err
+}
+
+func (r *Repository) CreateOrderItems(ctx context.Context, orderID int64, items []domain.Item) error {
+	return r.updateOrder(ctx, orderID, func(order *storage.Order) {
+		for _, item := range items {
+			currency := item.Currency
+			if currency == "" {
+				currency = domain.DefaultCurrency
+			}
+
+			order.Items = append(order.Items, storage.OrderItem{
+				Sku:      item.Sku,
+				Count:    item.Count,
+				Price:    item.Price,
+				Currency: currency,
+			})
+			order.TotalPrice += item.Count * item.Price
+			order.Currency = currency
+		}
+	})
+}
+
+func (r *Repository) GetByOrderID(ctx context.Context, orderID int64) (order domain.Order, err error) {
+	err = r.storage.Do(ctx, func(tx *storage.Tx) error {
+		stored, ok := tx.Order(orderID)
+		if !ok || len(stored.Items) == 0 {
+			return domain.ErrOrderNotFound
+		}
+
+		order = toDomainOrder(stored)
+		return nil
+	})
+
+	return order, err
+}
+
+func (r *Repository) GetByOrderIDForUpdate(ctx context.Context, orderID int64) (domain.Order, error) {
+	return r.GetByOrderID(ctx, orderID)
+}
+
+func (r *Repository) ListByUserID(ctx context.Context, filter domain.OrderListFilter) (orders []domain.Order, err error) {
+	err = r.storage.Do(ctx, func(tx *storage.Tx) error {
+		stored := tx.Orders()
+		slices.SortFunc(stored, func(a, b storage.Order) int {
+			return cmp.Compare(b.ID, a.ID)
+		})
+
+		for _, order := range stored {
+			if filter.Limit > 0 && len(orders) >= int(filter.Limit) {
+				break
+			}
+
+			if !matchFilter(order, filter) {
+				continue
+			}
+
+			result := toDomainOrder(order)
+			result.UpdatedAt = time.Time{}
+			orders = append(orders, result)
+		}
+
+		return nil
+	})
+
+	return orders, err
+}
+
+func (r *Repository) GetExpiredUnpaidOrderIDsForUpdate(
+	ctx context.Context,
+	paymentDeadline time.Duration,
+	excludedIDs []int64,
+	limit int32) (orderIDs []int64, err error) {
+	deadline := time.Now().Add(-paymentDeadline)
+
+	err = r.storage.Do(ctx, func(tx *storage.Tx) error {
+		var expired []storage.Order
+		for _, order := range tx.Orders() {
+			if order.Status == domain.OrderStatusAwaitingPayment && order.UpdatedAt.Before(deadline) &&
+				!slices.Contains(excludedIDs, order.ID) {
+				expired = append(expired, order)
+			}
+		}
+
+		slices.SortFunc(expired, func(a, b storage.Order) int {
+			return cmp.Or(a.UpdatedAt.Compare(b.UpdatedAt), cmp.Compare(a.ID, b.ID))
+		})
+
+		for _, order := range expired {
+			if len(orderIDs) >= int(limit) {
+				break
+			}
+
+			orderIDs = append(orderIDs, order.ID)
+		}
+
+		return nil
+	})
+
+	return orderIDs, err
+}
+
+func (r *Repository) SetStatus(ctx context.Context, orderID int64, status domain.OrderStatus) error {
+	return r.updateOrder(ctx, orderID, func(order *storage.Order) {
+		now := time.Now()
 
-	id := r.sequenceGenerator.Add(1)
+		order.Status = status
+		order.UpdatedAt = now
+		order.History = append(order.History, domain.OrderStatusChange{
+			Status:    status,
+			ChangedAt: now,
+		})
+	})
+}
+
+func (r *Repository) SetStatusAndCreateEvent(
+	ctx context.Context,
+	orderID int64,
+	status domain.OrderStatus,
+	event domain.Event) error {
+	return r.storage.Do(ctx, func(tx *storage.Tx) error {
+		ctx := storage.WithTx(ctx, tx)
+
+		if err := r.SetStatus(ctx, orderID, status); err != nil {
+			return fmt.Errorf("SetStatus failed: %w", err)
+		}
+
+		if err := r.outboxRepository.CreateEvent(ctx, event); err != nil {
+			return fmt.Errorf("CreateEvent failed: %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (r *Repository) CancelOrderItems(ctx context.Context, orderID int64, items []domain.Item) error {
+	return r.updateOrder(ctx, orderID, func(order *storage.Order) {
+		for _, item := range items {
+			for idx := range order.Items {
+				if order.Items[idx].Sku == item.Sku {
+					order.Items[idx].Cancelled += item.Count
+					order.TotalPrice -= item.Count * order.Items[idx].Price
+				}
+			}
+		}
+	})
+}
 
-	r.orderByID[id] = order
+func (r *Repository) ReturnOrderItems(ctx context.Context, orderID int64, items []domain.Item) error {
+	return r.updateOrder(ctx, orderID, func(order *storage.Order) {
+		for _, item := range items {
+			for idx := range order.Items {
+				if order.Items[idx].Sku == item.Sku {
+					order.Items[idx].Returned += item.Count
+				}
+			}
+		}
+	})
+}
+
+func (r *Repository) CreateOrderAllocations(ctx context.Context, orderID int64, allocations []domain.Allocation) error {
+	return r.updateOrder(ctx, orderID, func(order *storage.Order) {
+		order.Allocations = append(order.Allocations, allocations...)
+	})
+}
+
+func (r *Repository) GetAllocationsByOrderID(ctx context.Context, orderID int64) (result []domain.Allocation, err error) {
+	err = r.storage.Do(ctx, func(tx *storage.Tx) error {
+		order, ok := tx.Order(orderID)
+		if !ok {
+			return nil
+		}
 
-	return id, nil
+		for _, allocation := range order.Allocations {
+			if allocation.Count > 0 {
+				result = append(result, allocation)
+			}
+		}
+
+		return nil
+	})
+
+	slices.SortFunc(result, func(a, b domain.Allocation) int {
+		return cmp.Or(cmp.Compare(a.Sku, b.Sku), cmp.Compare(a.WarehouseID, b.WarehouseID))
+	})
+
+	return result, err
 }
 
-func (r *Repository) GetByOrderID(orderID int64) (domain.Order, error) {
-	r.mx.RLock()
-	defer r.mx.RUnlock()
+func (r *Repository) DecreaseOrderAllocations(ctx context.Context, orderID int64, allocations []domain.Allocation) error {
+	return r.updateOrder(ctx, orderID, func(order *storage.Order) {
+		for _, allocation := range allocations {
+			for idx := range order.Allocations {
+				if order.Allocations[idx].Sku == allocation.Sku &&
+					order.Allocations[idx].WarehouseID == allocation.WarehouseID {
+					order.Allocations[idx].Count -= allocation.Count
+				}
+			}
+		}
+	})
+}
 
-	order, ok := r.orderByID[orderID]
-	if !ok {
-		return domain.Order{}, domain.ErrOrderNotFound
+func (r *Repository) GetStatusHistory(ctx context.Context, orderID int64) (history []domain.OrderStatusChange, err error) {
+	err = r.storage.Do(ctx, func(tx *storage.Tx) error {
+		order, ok := tx.Order(orderID)
+		if !ok || len(order.History) == 0 {
+			return domain.ErrOrderNotFound
+		}
+
+		history = order.History
+		return nil
+	})
+
+	return history, err
+}
+
+func (r *Repository) updateOrder(ctx context.Context, orderID int64, update func(order *storage.Order)) error {
+	return r.storage.Do(ctx, func(tx *storage.Tx) error {
+		order, ok := tx.Order(orderID)
+		if !ok {
+			return nil
+		}
+
+		update(&order)
+		tx.PutOrder(order)
+		return nil
+	})
+}
+
+func matchFilter(order storage.Order, filter domain.OrderListFilter) bool {
+	if order.UserID != filter.UserID {
+		return false
 	}
 
-	return order, nil
+	if len(filter.Statuses) > 0 && !slices.Contains(filter.Statuses, order.Status) {
+		return false
+	}
+
+	if filter.CreatedFrom != nil && order.CreatedAt.Before(*filter.CreatedFrom) {
+		return false
+	}
+
+	if filter.CreatedTo != nil && !order.CreatedAt.Before(*filter.CreatedTo) {
+		return false
+	}
+
+	return filter.Cursor == 0 || order.ID < filter.Cursor
 }
 
-func (r *Repository) SetStatus(orderID int64, status domain.OrderStatus) error {
-	r.mx.Lock()
-	defer r.mx.Unlock()
+func toDomainOrder(order storage.Order) domain.Order {
+	result := domain.Order{
+		ID:         order.ID,
+		UserID:     order.UserID,
+		Status:     order.Status,
+		CreatedAt:  order.CreatedAt,
+		UpdatedAt:  order.UpdatedAt,
+		Items:      make([]domain.Item, 0, len(order.Items)),
+		TotalPrice: order.TotalPrice,
+		Currency:   order.Currency,
+	}
 
-	order := r.orderByID[orderID]
+	for _, item := range order.Items {
+		if item.Remaining() == 0 {
+			continue
+		}
 
-	order.Status = status
-	r.orderByID[orderID] = order
+		result.Items = append(result.Items, domain.Item{
+			Sku:      item.Sku,
+			Count:    item.Remaining(),
+			Price:    item.Price,
+			Currency: item.Currency,
+		})
+	}
 
-	return nil
+	return result
 }
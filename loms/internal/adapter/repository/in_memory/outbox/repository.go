@@ -0,0 +1,232 @@
+package outbox
+
+import (
+	"context"
+	"route256/loms/internal/adapter/repository/in_memory/storage"
+	"route256/loms/internal/domain"
+	"slices"
+	"time"
+)
+
+const maxBackoffShift = 30
+
+type Repository struct {
+	storage *storage.Storage
+}
+
+func New(storage *storage.Storage) *Repository {
+	return &Repository{
+		storage: storage,
+	}
+}
+
+func (r *Repository) CreateEvent(ctx context.Context, events domain.Event) error {
+	return r.storage.Do(ctx, func(tx *storage.Tx) error {
+		now := time.Now()
+
+		events.ID = tx.NextEventID()
+		events.Status = domain.EventStatusNew
+		events.Attempts = 0
+		events.CreatedAt = now
+		events.NextAttemptAt = now
+
+		tx.PutEvent(storage.Event{Event: events})
+		return nil
+	})
+}
+
+func (r *Repository) FetchNextMessages(ctx context.Context, limit int32) (domainEvents []domain.Event, err error) {
+	err = r.storage.Do(ctx, func(tx *storage.Tx) error {
+		now := time.Now()
+		for _, event := range tx.Events() {
+			if len(domainEvents) >= int(limit) {
+				break
+			}
+
+			if event.Status != domain.EventStatusNew && event.Status != domain.EventStatusPending {
+				continue
+			}
+
+			if event.NextAttemptAt.After(now) {
+				continue
+			}
+
+			domainEvents = append(domainEvents, event.Event)
+		}
+
+		return nil
+	})
+
+	return domainEvents, err
+}
+
+func (r *Repository) MarkAsSent(ctx context.Context, orderIDs []int64) error {
+	return r.updateEvents(ctx, orderIDs, func(event *storage.Event) {
+		event.Status = domain.EventStatusSent
+		event.SentAt = time.Now()
+	})
+}
+
+func (r *Repository) MarkAsError(ctx context.Context, orderIDs []int64, policy domain.EventRetryPolicy) error {
+	return r.updateEvents(ctx, orderIDs, func(event *storage.Event) {
+		backoff := min(policy.BackoffBase<<min(event.Attempts, maxBackoffShift), policy.BackoffMax)
+
+		event.Attempts++
+		event.Status = domain.EventStatusPending
+		if event.Attempts >= policy.MaxAttempts {
+			event.Status = domain.EventStatusDead
+		}
+
+		event.NextAttemptAt = time.Now().Add(backoff)
+	})
+}
+
+func (r *Repository) ListDeadEvents(ctx context.Context, filter domain.DeadEventsFilter) (domainEvents []domain.Event, err error) {
+	err = r.storage.Do(ctx, func(tx *storage.Tx) error {
+		events := tx.Events()
+		slices.Reverse(events)
+
+		domainEvents = make([]domain.Event, 0)
+		for _, event := range events {
+			if len(domainEvents) >= int(filter.Limit) {
+				break
+			}
+
+			if event.Status != domain.EventStatusDead || (filter.Cursor != 0 && event.ID >= filter.Cursor) {
+				continue
+			}
+
+			event.TraceContext = nil
+			event.RequestID = ""
+			event.EventID = ""
+			domainEvents = append(domainEvents, event.Event)
+		}
+
+		return nil
+	})
+
+	return domainEvents, err
+}
+
+func (r *Repository) RedriveDeadEvents(ctx context.Context, ids []int64) (redrivenIDs []int64, err error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	err = r.storage.Do(ctx, func(tx *storage.Tx) error {
+		for _, id := range ids {
+			event, ok := tx.Event(id)
+			if !ok || event.Status != domain.EventStatusDead {
+				continue
+			}
+
+			event.Status = domain.EventStatusNew
+			event.Attempts = 0
+			event.NextAttemptAt = time.Now()
+
+			tx.PutEvent(event)
+			redrivenIDs = append(redrivenIDs, id)
+		}
+
+		return nil
+	})
+
+	return redrivenIDs, err
+}
+
+func (r *Repository) CreateArchivePartitions(_ context.Context, _ time.Duration) error {
+	return nil
+}
+
+func (r *Repository) ArchiveSentEvents(ctx context.Context, retention time.Duration, limit int32) (int64, error) {
+	return r.removeSentEvents(ctx, retention, limit, true)
+}
+
+func (r *Repository) DeleteSentEvents(ctx context.Context, retention time.Duration, limit int32) (int64, error) {
+	return r.removeSentEvents(ctx, retention, limit, false)
+}
+
+func (r *Repository) GetOldestUndeliveredAge(ctx context.Context) (time.Duration, error) {
+	return r.oldestAge(ctx, func(event storage.Event) bool {
+		return event.Status != domain.EventStatusSent
+	})
+}
+
+func (r *Repository) GetOldestPendingAge(ctx context.Context) (time.Duration, error) {
+	return r.oldestAge(ctx, func(event storage.Event) bool {
+		return event.Status == domain.EventStatusNew || event.Status == domain.EventStatusPending
+	})
+}
+
+func (r *Repository) oldestAge(ctx context.Context, match func(event storage.Event) bool) (age time.Duration, err error) {
+	err = r.storage.Do(ctx, func(tx *storage.Tx) error {
+		var oldest time.Time
+		for _, event := range tx.Events() {
+			if !match(event) {
+				continue
+			}
+
+			if oldest.IsZero() || event.CreatedAt.Before(oldest) {
+				oldest = event.CreatedAt
+			}
+		}
+
+		if !oldest.IsZero() {
+			age = time.Since(oldest)
+		}
+
+		return nil
+	})
+
+	return age, err
+}
+
+func (r *Repository) removeSentEvents(ctx context.Context, retention time.Duration, limit int32, archive bool) (removed int64, err error) {
+	err = r.storage.Do(ctx, func(tx *storage.Tx) error {
+		threshold := time.Now().Add(-retention)
+
+		var expired []storage.Event
+		for _, event := range tx.Events() {
+			if event.Status == domain.EventStatusSent && event.SentAt.Before(threshold) {
+				expired = append(expired, event)
+			}
+		}
+
+		slices.SortStableFunc(expired, func(a, b storage.Event) int {
+			return a.SentAt.Compare(b.SentAt)
+		})
+
+		for _, event := range expired {
+			if removed >= int64(limit) {
+				break
+			}
+
+			if archive {
+				tx.ArchiveEvent(event)
+			}
+
+			tx.DeleteEvent(event.ID)
+			removed++
+		}
+
+		return nil
+	})
+
+	return removed, err
+}
+
+func (r *Repository) updateEvents(ctx context.Context, ids []int64, update func(event *storage.Event)) error {
+	return r.storage.Do(ctx, func(tx *storage.Tx) error {
+		for _, id := range ids {
+			event, ok := tx.Event(id)
+			if !ok {
+				continue
+			}
+
+			update(&event)
+			tx.PutEvent(event)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,78 @@
+package payment
+
+import (
+	"context"
+	"route256/loms/internal/adapter/repository/in_memory/storage"
+	"route256/loms/internal/domain"
+	"time"
+)
+
+type Repository struct {
+	storage *storage.Storage
+}
+
+func New(storage *storage.Storage) *Repository {
+	return &Repository{
+		storage: storage,
+	}
+}
+
+func (r *Repository) CreatePaymentIntent(ctx context.Context, intent domain.PaymentIntent) (domain.PaymentIntent, error) {
+	err := r.storage.Do(ctx, func(tx *storage.Tx) error {
+		now := time.Now()
+
+		intent.Status = domain.PaymentStatusPending
+		intent.CreatedAt = now
+		intent.UpdatedAt = now
+
+		tx.PutPaymentIntent(intent)
+		return nil
+	})
+
+	return intent, err
+}
+
+func (r *Repository) GetPaymentIntent(ctx context.Context, orderID int64) (intent domain.PaymentIntent, err error) {
+	err = r.storage.Do(ctx, func(tx *storage.Tx) error {
+		var ok bool
+		if intent, ok = tx.PaymentIntent(orderID); !ok {
+			return domain.ErrPaymentNotFound
+		}
+
+		return nil
+	})
+
+	return intent, err
+}
+
+func (r *Repository) UpdatePaymentIntent(ctx context.Context, orderID int64, result domain.PaymentResult) error {
+	return r.storage.Do(ctx, func(tx *storage.Tx) error {
+		intent, ok := tx.PaymentIntent(orderID)
+		if !ok {
+			return nil
+		}
+
+		intent.Status = result.Status
+		intent.DeclineReason = result.Reason
+		intent.UpdatedAt = time.Now()
+
+		if result.ProviderPaymentID != "" {
+			intent.ProviderPaymentID = result.ProviderPaymentID
+		}
+
+		if result.Amount != 0 {
+			intent.Amount = result.Amount
+		}
+
+		if result.Currency != "" {
+			intent.Currency = result.Currency
+		}
+
+		if result.Status == domain.PaymentStatusDeclined {
+			intent.Attempts++
+		}
+
+		tx.PutPaymentIntent(intent)
+		return nil
+	})
+}
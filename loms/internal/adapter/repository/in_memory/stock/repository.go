@@ -1,11 +1,13 @@
 package stock
 
 import (
+	"context"
 	_ "embed"
 	"encoding/json"
-	"maps"
+	"route256/loms/internal/adapter/repository/in_memory/storage"
 	"route256/loms/internal/domain"
-	"sync"
+	"slices"
+	"time"
 )
 
 //go:embed stock-data.json
@@ -17,125 +19,157 @@ type stockDTO struct {
 	Reserved   int64      `json:"reserved"`
 }
 
-type stockBySku = map[domain.Sku]domain.Stock
-
 type Repository struct {
-	stockBySku stockBySku
-	mx         sync.RWMutex
+	storage *storage.Storage
 }
 
-func New() (*Repository, error) {
+func New(ctx context.Context, store *storage.Storage) (*Repository, error) {
 	var data []stockDTO
 	err := json.Unmarshal(stockData, &data)
 	if err != nil {
 		return nil, err
 	}
 
-	stockMap := make(stockBySku, len(data))
+	repo := &Repository{
+		storage: store,
+	}
 
-	for _, item := range data {
-		stockMap[item.Sku] = domain.Stock{
-			TotalCount: item.TotalCount,
-			Reserved:   item.Reserved,
+	err = repo.storage.Do(ctx, func(tx *storage.Tx) error {
+		for _, item := range data {
+			tx.PutStock(domain.WarehouseStock{
+				WarehouseID: domain.DefaultWarehouseID,
+				Sku:         item.Sku,
+				TotalCount:  item.TotalCount,
+				Reserved:    item.Reserved,
+			})
 		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return &Repository{
-		stockBySku: stockMap,
-	}, nil
+	return repo, nil
 }
 
-func (r *Repository) GetStockBySku(sku domain.Sku) (int64, error) {
-	r.mx.RLock()
-	defer r.mx.RUnlock()
-
-	stocks, ok := r.stockBySku[sku]
-	if !ok {
-		return 0, domain.ErrStockNotFound
+func (r *Repository) GetStockBySku(ctx context.Context, sku domain.Sku) (stock domain.Stock, err error) {
+	stocks, err := r.GetWarehouseStocksBySku(ctx, sku)
+	if err != nil {
+		return domain.Stock{}, err
 	}
 
-	remainderCount := stocks.TotalCount - stocks.Reserved
-
-	if remainderCount <= 0 {
-		return 0, domain.ErrNotEnoughStock
+	for _, value := range stocks {
+		stock.TotalCount += value.TotalCount
+		stock.Reserved += value.Reserved
 	}
 
-	return remainderCount, nil
+	return stock, nil
 }
 
-func (r *Repository) Reserve(items []domain.Item) error {
-	r.mx.Lock()
-	defer r.mx.Unlock()
-
-	updatedStocks := make(map[domain.Sku]domain.Stock)
-
-	for _, value := range items {
-		stocks, ok := r.stockBySku[value.Sku]
-		if !ok {
+func (r *Repository) GetWarehouseStocksBySku(ctx context.Context, sku domain.Sku) (result []domain.WarehouseStock, err error) {
+	err = r.storage.Do(ctx, func(tx *storage.Tx) error {
+		result = tx.StocksBySku(sku)
+		if len(result) == 0 {
 			return domain.ErrStockNotFound
 		}
 
-		if stocks.TotalCount < (stocks.Reserved + value.Count) {
-			return domain.ErrNotEnoughStock
-		}
-
-		stocks.Reserved += value.Count
-		updatedStocks[value.Sku] = stocks
-	}
-
-	maps.Copy(r.stockBySku, updatedStocks)
+		return nil
+	})
 
-	return nil
+	return result, err
 }
 
-func (r *Repository) ReserveRemove(items []domain.Item) error {
-	r.mx.Lock()
-	defer r.mx.Unlock()
+func (r *Repository) GetStocksBySkuForUpdate(
+	ctx context.Context,
+	items []domain.Item) (result map[domain.Sku][]domain.WarehouseStock, err error) {
+	if len(items) == 0 {
+		return nil, nil
+	}
 
-	updatedStocks := make(map[domain.Sku]domain.Stock)
+	err = r.storage.Do(ctx, func(tx *storage.Tx) error {
+		result = make(map[domain.Sku][]domain.WarehouseStock, len(items))
+		for _, item := range items {
+			if _, ok := result[item.Sku]; ok {
+				continue
+			}
 
-	for _, value := range items {
-		stocks, ok := r.stockBySku[value.Sku]
-		if !ok {
-			return domain.ErrStockNotFound
+			if stocks := tx.StocksBySku(item.Sku); len(stocks) > 0 {
+				result[item.Sku] = stocks
+			}
 		}
 
-		if stocks.TotalCount < value.Count || stocks.Reserved < value.Count {
-			return domain.ErrInvalidReserveOperation
+		if len(result) == 0 {
+			return domain.ErrStockNotFound
 		}
 
-		stocks.TotalCount -= value.Count
-		stocks.Reserved -= value.Count
+		return nil
+	})
 
-		updatedStocks[value.Sku] = stocks
-	}
+	return result, err
+}
 
-	maps.Copy(r.stockBySku, updatedStocks)
+func (r *Repository) UpdateStocks(ctx context.Context, stocks []domain.WarehouseStock) error {
+	return r.storage.Do(ctx, func(tx *storage.Tx) error {
+		for _, stock := range stocks {
+			if _, ok := tx.Stock(stock.WarehouseID, stock.Sku); ok {
+				tx.PutStock(stock)
+			}
+		}
 
-	return nil
+		return nil
+	})
 }
 
-func (r *Repository) ReserveCancel(items []domain.Item) error {
-	r.mx.Lock()
-	defer r.mx.Unlock()
+func (r *Repository) CreateStock(ctx context.Context, stock domain.WarehouseStock) error {
+	return r.storage.Do(ctx, func(tx *storage.Tx) error {
+		if _, ok := tx.Stock(stock.WarehouseID, stock.Sku); ok {
+			return domain.ErrStockAlreadyExists
+		}
+
+		stock.Reserved = 0
+		tx.PutStock(stock)
 
-	updatedStocks := make(map[domain.Sku]domain.Stock)
+		return nil
+	})
+}
 
-	for _, value := range items {
-		stocks, ok := r.stockBySku[value.Sku]
-		if !ok {
-			return domain.ErrStockNotFound
+func (r *Repository) CreateStockMovements(ctx context.Context, movements []domain.StockMovement) error {
+	return r.storage.Do(ctx, func(tx *storage.Tx) error {
+		now := time.Now()
+		for _, movement := range movements {
+			movement.CreatedAt = now
+			tx.AppendMovement(movement)
 		}
 
-		if stocks.Reserved < value.Count {
-			return domain.ErrInvalidReserveOperation
-		}
+		return nil
+	})
+}
 
-		stocks.Reserved -= value.Count
-		updatedStocks[value.Sku] = stocks
-	}
+func (r *Repository) ListStockMovements(
+	ctx context.Context,
+	filter domain.StockHistoryFilter) (movements []domain.StockMovement, err error) {
+	err = r.storage.Do(ctx, func(tx *storage.Tx) error {
+		all := tx.Movements()
+		slices.Reverse(all)
+
+		movements = make([]domain.StockMovement, 0)
+		for _, movement := range all {
+			if len(movements) >= int(filter.Limit) {
+				break
+			}
+
+			if movement.Sku != filter.Sku ||
+				(filter.WarehouseID != 0 && movement.WarehouseID != filter.WarehouseID) ||
+				(filter.Cursor != 0 && movement.ID >= filter.Cursor) {
+				continue
+			}
+
+			movements = append(movements, movement)
+		}
 
-	maps.Copy(r.stockBySku, updatedStocks)
+		return nil
+	})
 
-	return nil
+	return movements, err
 }
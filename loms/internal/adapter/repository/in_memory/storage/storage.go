@@ -0,0 +1,307 @@
+package storage
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"route256/loms/internal/domain"
+	"slices"
+	"sync"
+	"time"
+)
+
+type txKeyType struct{}
+
+var txKey = txKeyType{}
+
+type OrderItem struct {
+	Sku       domain.Sku
+	Count     int64
+	Cancelled int64
+	Returned  int64
+	Price     int64
+	Currency  string
+}
+
+func (i OrderItem) Remaining() int64 {
+	return i.Count - i.Cancelled - i.Returned
+}
+
+type Order struct {
+	ID          int64
+	UserID      int64
+	Status      domain.OrderStatus
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Items       []OrderItem
+	Allocations []domain.Allocation
+	History     []domain.OrderStatusChange
+	TotalPrice  int64
+	Currency    string
+}
+
+func (o Order) clone() Order {
+	o.Items = slices.Clone(o.Items)
+	o.Allocations = slices.Clone(o.Allocations)
+	o.History = slices.Clone(o.History)
+	return o
+}
+
+type Event struct {
+	domain.Event
+	SentAt time.Time
+}
+
+func (e Event) clone() Event {
+	e.Payload = slices.Clone(e.Payload)
+	e.TraceContext = maps.Clone(e.TraceContext)
+	return e
+}
+
+type stockKey struct {
+	warehouseID int64
+	sku         domain.Sku
+}
+
+type Storage struct {
+	mx sync.Mutex
+
+	orders    map[int64]Order
+	stocks    map[stockKey]domain.WarehouseStock
+	movements []domain.StockMovement
+	events    map[int64]Event
+	archive   []Event
+	payments  map[int64]domain.PaymentIntent
+
+	orderSeq    int64
+	eventSeq    int64
+	movementSeq int64
+}
+
+func New() *Storage {
+	return &Storage{
+		orders:   make(map[int64]Order),
+		stocks:   make(map[stockKey]domain.WarehouseStock),
+		events:   make(map[int64]Event),
+		payments: make(map[int64]domain.PaymentIntent),
+	}
+}
+
+type Tx struct {
+	storage *Storage
+	undo    []func()
+}
+
+func WithTx(ctx context.Context, tx *Tx) context.Context {
+	return context.WithValue(ctx, txKey, tx)
+}
+
+func (s *Storage) Do(ctx context.Context, fnc func(tx *Tx) error) error {
+	if tx, ok := ctx.Value(txKey).(*Tx); ok && tx.storage == s {
+		return fnc(tx)
+	}
+
+	return s.run(ctx, func(_ context.Context, tx *Tx) error {
+		return fnc(tx)
+	})
+}
+
+func (s *Storage) run(ctx context.Context, fnc func(ctx context.Context, tx *Tx) error) (err error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	tx := &Tx{storage: s}
+	ctx = WithTx(ctx, tx)
+
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("panica recovered: %v", rec)
+		}
+
+		if err != nil {
+			tx.rollback()
+		}
+	}()
+
+	return fnc(ctx, tx)
+}
+
+func (tx *Tx) rollback() {
+	for idx := len(tx.undo) - 1; idx >= 0; idx-- {
+		tx.undo[idx]()
+	}
+
+	tx.undo = nil
+}
+
+func (tx *Tx) NextOrderID() int64 {
+	tx.storage.orderSeq++
+	return tx.storage.orderSeq
+}
+
+func (tx *Tx) Order(id int64) (Order, bool) {
+	order, ok := tx.storage.orders[id]
+	if !ok {
+		return Order{}, false
+	}
+
+	return order.clone(), true
+}
+
+func (tx *Tx) Orders() []Order {
+	orders := make([]Order, 0, len(tx.storage.orders))
+	for _, order := range tx.storage.orders {
+		orders = append(orders, order.clone())
+	}
+
+	return orders
+}
+
+func (tx *Tx) PutOrder(order Order) {
+	prev, existed := tx.storage.orders[order.ID]
+	tx.undo = append(tx.undo, func() {
+		if existed {
+			tx.storage.orders[order.ID] = prev
+			return
+		}
+
+		delete(tx.storage.orders, order.ID)
+	})
+
+	tx.storage.orders[order.ID] = order.clone()
+}
+
+func (tx *Tx) Stock(warehouseID int64, sku domain.Sku) (domain.WarehouseStock, bool) {
+	stock, ok := tx.storage.stocks[stockKey{warehouseID: warehouseID, sku: sku}]
+	return stock, ok
+}
+
+func (tx *Tx) StocksBySku(sku domain.Sku) []domain.WarehouseStock {
+	var stocks []domain.WarehouseStock
+	for key, stock := range tx.storage.stocks {
+		if key.sku == sku {
+			stocks = append(stocks, stock)
+		}
+	}
+
+	slices.SortFunc(stocks, func(a, b domain.WarehouseStock) int {
+		return cmp.Compare(a.WarehouseID, b.WarehouseID)
+	})
+
+	return stocks
+}
+
+func (tx *Tx) PutStock(stock domain.WarehouseStock) {
+	key := stockKey{warehouseID: stock.WarehouseID, sku: stock.Sku}
+
+	prev, existed := tx.storage.stocks[key]
+	tx.undo = append(tx.undo, func() {
+		if existed {
+			tx.storage.stocks[key] = prev
+			return
+		}
+
+		delete(tx.storage.stocks, key)
+	})
+
+	tx.storage.stocks[key] = stock
+}
+
+func (tx *Tx) AppendMovement(movement domain.StockMovement) {
+	tx.storage.movementSeq++
+	movement.ID = tx.storage.movementSeq
+
+	size := len(tx.storage.movements)
+	tx.undo = append(tx.undo, func() {
+		tx.storage.movements = tx.storage.movements[:size]
+	})
+
+	tx.storage.movements = append(tx.storage.movements, movement)
+}
+
+func (tx *Tx) Movements() []domain.StockMovement {
+	return slices.Clone(tx.storage.movements)
+}
+
+func (tx *Tx) NextEventID() int64 {
+	tx.storage.eventSeq++
+	return tx.storage.eventSeq
+}
+
+func (tx *Tx) Event(id int64) (Event, bool) {
+	event, ok := tx.storage.events[id]
+	if !ok {
+		return Event{}, false
+	}
+
+	return event.clone(), true
+}
+
+func (tx *Tx) Events() []Event {
+	events := make([]Event, 0, len(tx.storage.events))
+	for _, event := range tx.storage.events {
+		events = append(events, event.clone())
+	}
+
+	slices.SortFunc(events, func(a, b Event) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	return events
+}
+
+func (tx *Tx) PutEvent(event Event) {
+	prev, existed := tx.storage.events[event.ID]
+	tx.undo = append(tx.undo, func() {
+		if existed {
+			tx.storage.events[event.ID] = prev
+			return
+		}
+
+		delete(tx.storage.events, event.ID)
+	})
+
+	tx.storage.events[event.ID] = event.clone()
+}
+
+func (tx *Tx) DeleteEvent(id int64) {
+	prev, existed := tx.storage.events[id]
+	if !existed {
+		return
+	}
+
+	tx.undo = append(tx.undo, func() {
+		tx.storage.events[id] = prev
+	})
+
+	delete(tx.storage.events, id)
+}
+
+func (tx *Tx) ArchiveEvent(event Event) {
+	size := len(tx.storage.archive)
+	tx.undo = append(tx.undo, func() {
+		tx.storage.archive = tx.storage.archive[:size]
+	})
+
+	tx.storage.archive = append(tx.storage.archive, event.clone())
+}
+
+func (tx *Tx) PaymentIntent(orderID int64) (domain.PaymentIntent, bool) {
+	intent, ok := tx.storage.payments[orderID]
+	return intent, ok
+}
+
+func (tx *Tx) PutPaymentIntent(intent domain.PaymentIntent) {
+	prev, existed := tx.storage.payments[intent.OrderID]
+	tx.undo = append(tx.undo, func() {
+		if existed {
+			tx.storage.payments[intent.OrderID] = prev
+			return
+		}
+
+		delete(tx.storage.payments, intent.OrderID)
+	})
+
+	tx.storage.payments[intent.OrderID] = intent
+}
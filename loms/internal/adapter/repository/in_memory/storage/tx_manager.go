@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	txmanager "route256/loms/internal/infra/tx_manager"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+type TxManager struct {
+	storage *Storage
+}
+
+func NewTxManager(storage *Storage) *TxManager {
+	return &TxManager{storage: storage}
+}
+
+func (mgr *TxManager) transaction(ctx context.Context, fnc txmanager.Handler) error {
+	if tx, ok := ctx.Value(txKey).(*Tx); ok && tx.storage == mgr.storage {
+		return fnc(ctx)
+	}
+
+	return mgr.storage.run(ctx, func(ctx context.Context, _ *Tx) error {
+		if err := fnc(ctx); err != nil {
+			return fmt.Errorf("failed executing code inside transaction: %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (mgr *TxManager) ReadCommitted(ctx context.Context, fnc txmanager.Handler) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "txmanager.ReadCommitted")
+	defer span.Finish()
+
+	return mgr.transaction(ctx, fnc)
+}
+
+func (mgr *TxManager) RepeatableRead(ctx context.Context, fnc txmanager.Handler) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "txmanager.RepeatableRead")
+	defer span.Finish()
+
+	return mgr.transaction(ctx, fnc)
+}
+
+func (mgr *TxManager) Serializable(ctx context.Context, fnc txmanager.Handler) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "txmanager.Serializable")
+	defer span.Finish()
+
+	return mgr.transaction(ctx, fnc)
+}
+
+func (mgr *TxManager) ReadOnly(ctx context.Context, fnc txmanager.Handler) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "txmanager.ReadOnly")
+	defer span.Finish()
+
+	return mgr.transaction(ctx, fnc)
+}
@@ -6,10 +6,10 @@ import (
 	sqlc "route256/loms/internal/adapter/repository/postgtres/queries_sqlc_generated"
 	"route256/loms/internal/domain"
 	"route256/loms/internal/infra/metrics"
-	txmanager "route256/loms/internal/infra/tx_manager"
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/opentracing/opentracing-go"
 )
@@ -21,8 +21,9 @@ type outboxRepository interface {
 }
 
 type postgresPools interface {
-	GetWriteReplica() *pgxpool.Pool
-	GetReadReplica() *pgxpool.Pool
+	GetWriteReplica(ctx context.Context) *pgxpool.Pool
+	GetReadReplica(ctx context.Context) *pgxpool.Pool
+	TxKey() any
 }
 
 type Repository struct {
@@ -38,21 +39,21 @@ func New(connPools postgresPools, outboxRepository outboxRepository) *Repository
 }
 
 func (r *Repository) getMasterQuerier(ctx context.Context) *sqlc.Queries {
-	tx, ok := ctx.Value(txmanager.TxKey).(pgx.Tx)
+	tx, ok := ctx.Value(r.connPools.TxKey()).(pgx.Tx)
 	if ok {
 		return sqlc.New(tx)
 	}
 
-	return sqlc.New(r.connPools.GetWriteReplica())
+	return sqlc.New(r.connPools.GetWriteReplica(ctx))
 }
 
 func (r *Repository) getReplicaQuerier(ctx context.Context) *sqlc.Queries {
-	tx, ok := ctx.Value(txmanager.TxKey).(pgx.Tx)
+	tx, ok := ctx.Value(r.connPools.TxKey()).(pgx.Tx)
 	if ok {
 		return sqlc.New(tx)
 	}
 
-	return sqlc.New(r.connPools.GetReadReplica())
+	return sqlc.New(r.connPools.GetReadReplica(ctx))
 }
 
 func (r *Repository) GetByOrderID(ctx context.Context, orderID int64) (order domain.Order, err error) {
@@ -80,15 +81,25 @@ func (r *Repository) GetByOrderID(ctx context.Context, orderID int64) (order dom
 	}
 
 	order = domain.Order{
-		UserID: rows[0].UserID,
-		Status: domain.OrderStatus(rows[0].Status),
-		Items:  make([]domain.Item, 0, len(rows)),
+		UserID:     rows[0].UserID,
+		Status:     domain.OrderStatus(rows[0].Status),
+		CreatedAt:  rows[0].CreatedAt.Time,
+		UpdatedAt:  rows[0].UpdatedAt.Time,
+		Items:      make([]domain.Item, 0, len(rows)),
+		TotalPrice: rows[0].TotalPrice,
+		Currency:   rows[0].Currency,
 	}
 
 	for _, row := range rows {
+		if row.Count == 0 {
+			continue
+		}
+
 		item := domain.Item{
-			Sku:   domain.Sku(row.Sku),
-			Count: row.Count,
+			Sku:      domain.Sku(row.Sku),
+			Count:    row.Count,
+			Price:    row.Price,
+			Currency: row.ItemCurrency,
 		}
 		order.Items = append(order.Items, item)
 	}
@@ -121,15 +132,23 @@ func (r *Repository) GetByOrderIDForUpdate(ctx context.Context, orderID int64) (
 	}
 
 	order = domain.Order{
-		UserID: rows[0].UserID,
-		Status: domain.OrderStatus(rows[0].Status),
-		Items:  make([]domain.Item, 0, len(rows)),
+		UserID:     rows[0].UserID,
+		Status:     domain.OrderStatus(rows[0].Status),
+		Items:      make([]domain.Item, 0, len(rows)),
+		TotalPrice: rows[0].TotalPrice,
+		Currency:   rows[0].Currency,
 	}
 
 	for _, row := range rows {
+		if row.Count == 0 {
+			continue
+		}
+
 		item := domain.Item{
-			Sku:   domain.Sku(row.Sku),
-			Count: row.Count,
+			Sku:      domain.Sku(row.Sku),
+			Count:    row.Count,
+			Price:    row.Price,
+			Currency: row.ItemCurrency,
 		}
 		order.Items = append(order.Items, item)
 	}
@@ -137,6 +156,115 @@ func (r *Repository) GetByOrderIDForUpdate(ctx context.Context, orderID int64) (
 	return order, nil
 }
 
+func (r *Repository) ListByUserID(ctx context.Context, filter domain.OrderListFilter) (orders []domain.Order, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "orderRepository.ListByUserID")
+	defer func(now time.Time) {
+		status := string(metrics.DBQueryStatusOK)
+		if err != nil {
+			status = string(metrics.DBQueryStatusError)
+		}
+
+		metrics.IncDBQueryCounter(string(metrics.Select), status)
+		metrics.DBQueryDurationHistogram(string(metrics.Select), status, time.Since(now).Seconds())
+
+		span.Finish()
+	}(time.Now())
+
+	querier := r.getReplicaQuerier(ctx)
+
+	params := &sqlc.ListOrdersByUserIDParams{
+		UserID:      filter.UserID,
+		Statuses:    make([]string, len(filter.Statuses)),
+		CreatedFrom: toPgTimestamp(filter.CreatedFrom),
+		CreatedTo:   toPgTimestamp(filter.CreatedTo),
+		Cursor:      filter.Cursor,
+		RowLimit:    filter.Limit,
+	}
+
+	for idx, status := range filter.Statuses {
+		params.Statuses[idx] = string(status)
+	}
+
+	rows, err := querier.ListOrdersByUserID(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("querier.ListOrdersByUserID: %w", err)
+	}
+
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	orderIDs := make([]int64, len(rows))
+	orders = make([]domain.Order, len(rows))
+	positions := make(map[int64]int, len(rows))
+
+	for idx, row := range rows {
+		orderIDs[idx] = row.ID
+		positions[row.ID] = idx
+		orders[idx] = domain.Order{
+			ID:         row.ID,
+			UserID:     filter.UserID,
+			Status:     domain.OrderStatus(row.Status),
+			CreatedAt:  row.CreatedAt.Time,
+			TotalPrice: row.TotalPrice,
+			Currency:   row.Currency,
+		}
+	}
+
+	items, err := querier.GetItemsByOrderIDs(ctx, orderIDs)
+	if err != nil {
+		return nil, fmt.Errorf("querier.GetItemsByOrderIDs: %w", err)
+	}
+
+	for _, item := range items {
+		if item.Count == 0 {
+			continue
+		}
+
+		idx := positions[item.OrderID]
+		orders[idx].Items = append(orders[idx].Items, domain.Item{
+			Sku:      domain.Sku(item.Sku),
+			Count:    item.Count,
+			Price:    item.Price,
+			Currency: item.Currency,
+		})
+	}
+
+	return orders, nil
+}
+
+func (r *Repository) GetExpiredUnpaidOrderIDsForUpdate(
+	ctx context.Context,
+	paymentDeadline time.Duration,
+	excludedIDs []int64,
+	limit int32) (orderIDs []int64, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "orderRepository.GetExpiredUnpaidOrderIDsForUpdate")
+	defer func(now time.Time) {
+		status := string(metrics.DBQueryStatusOK)
+		if err != nil {
+			status = string(metrics.DBQueryStatusError)
+		}
+
+		metrics.IncDBQueryCounter(string(metrics.Select), status)
+		metrics.DBQueryDurationHistogram(string(metrics.Select), status, time.Since(now).Seconds())
+
+		span.Finish()
+	}(time.Now())
+
+	querier := r.getMasterQuerier(ctx)
+
+	orderIDs, err = querier.GetExpiredUnpaidOrderIDsForUpdate(ctx, &sqlc.GetExpiredUnpaidOrderIDsForUpdateParams{
+		PaymentDeadlineSec: int64(paymentDeadline.Seconds()),
+		ExcludedIds:        excludedIDs,
+		RowLimit:           limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("querier.GetExpiredUnpaidOrderIDsForUpdate: %w", err)
+	}
+
+	return orderIDs, nil
+}
+
 func (r *Repository) SetStatus(ctx context.Context, orderID int64, status domain.OrderStatus) (err error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "orderRepository.SetStatus")
 	defer func(now time.Time) {
@@ -218,15 +346,22 @@ func (r *Repository) CreateOrderItems(ctx context.Context, orderID int64, items
 		batch := items[start:end]
 
 		params := &sqlc.CreateOrderItemsParams{
-			OrderIds: make([]int64, len(batch)),
-			Skus:     make([]int64, len(batch)),
-			Counts:   make([]int64, len(batch)),
+			OrderIds:   make([]int64, len(batch)),
+			Skus:       make([]int64, len(batch)),
+			Counts:     make([]int64, len(batch)),
+			Prices:     make([]int64, len(batch)),
+			Currencies: make([]string, len(batch)),
 		}
 
 		for i, item := range batch {
 			params.OrderIds[i] = orderID
 			params.Skus[i] = int64(item.Sku)
 			params.Counts[i] = item.Count
+			params.Prices[i] = item.Price
+			params.Currencies[i] = item.Currency
+			if item.Currency == "" {
+				params.Currencies[i] = domain.DefaultCurrency
+			}
 		}
 
 		if err := querier.CreateOrderItems(ctx, params); err != nil {
@@ -237,6 +372,183 @@ func (r *Repository) CreateOrderItems(ctx context.Context, orderID int64, items
 	return nil
 }
 
+func (r *Repository) CancelOrderItems(ctx context.Context, orderID int64, items []domain.Item) (err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "orderRepository.CancelOrderItems")
+	defer func(now time.Time) {
+		status := string(metrics.DBQueryStatusOK)
+		if err != nil {
+			status = string(metrics.DBQueryStatusError)
+		}
+
+		metrics.IncDBQueryCounter(string(metrics.Update), status)
+		metrics.DBQueryDurationHistogram(string(metrics.Update), status, time.Since(now).Seconds())
+
+		span.Finish()
+	}(time.Now())
+
+	if len(items) == 0 {
+		return nil
+	}
+
+	querier := r.getMasterQuerier(ctx)
+
+	skus, counts := splitItems(items)
+
+	err = querier.CancelOrderItems(ctx, &sqlc.CancelOrderItemsParams{
+		OrderID: orderID,
+		Skus:    skus,
+		Counts:  counts,
+	})
+	if err != nil {
+		return fmt.Errorf("querier.CancelOrderItems: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) ReturnOrderItems(ctx context.Context, orderID int64, items []domain.Item) (err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "orderRepository.ReturnOrderItems")
+	defer func(now time.Time) {
+		status := string(metrics.DBQueryStatusOK)
+		if err != nil {
+			status = string(metrics.DBQueryStatusError)
+		}
+
+		metrics.IncDBQueryCounter(string(metrics.Update), status)
+		metrics.DBQueryDurationHistogram(string(metrics.Update), status, time.Since(now).Seconds())
+
+		span.Finish()
+	}(time.Now())
+
+	if len(items) == 0 {
+		return nil
+	}
+
+	querier := r.getMasterQuerier(ctx)
+
+	skus, counts := splitItems(items)
+
+	err = querier.ReturnOrderItems(ctx, &sqlc.ReturnOrderItemsParams{
+		OrderID: orderID,
+		Skus:    skus,
+		Counts:  counts,
+	})
+	if err != nil {
+		return fmt.Errorf("querier.ReturnOrderItems: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) CreateOrderAllocations(
+	ctx context.Context,
+	orderID int64,
+	allocations []domain.Allocation) (err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "orderRepository.CreateOrderAllocations")
+	defer func(now time.Time) {
+		status := string(metrics.DBQueryStatusOK)
+		if err != nil {
+			status = string(metrics.DBQueryStatusError)
+		}
+
+		metrics.IncDBQueryCounter(string(metrics.Create), status)
+		metrics.DBQueryDurationHistogram(string(metrics.Create), status, time.Since(now).Seconds())
+
+		span.Finish()
+	}(time.Now())
+
+	if len(allocations) == 0 {
+		return nil
+	}
+
+	querier := r.getMasterQuerier(ctx)
+
+	skus, warehouseIDs, counts := splitAllocations(allocations)
+
+	err = querier.CreateOrderAllocations(ctx, &sqlc.CreateOrderAllocationsParams{
+		OrderID:      orderID,
+		Skus:         skus,
+		WarehouseIds: warehouseIDs,
+		Counts:       counts,
+	})
+	if err != nil {
+		return fmt.Errorf("querier.CreateOrderAllocations: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) GetAllocationsByOrderID(ctx context.Context, orderID int64) (result []domain.Allocation, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "orderRepository.GetAllocationsByOrderID")
+	defer func(now time.Time) {
+		status := string(metrics.DBQueryStatusOK)
+		if err != nil {
+			status = string(metrics.DBQueryStatusError)
+		}
+
+		metrics.IncDBQueryCounter(string(metrics.Select), status)
+		metrics.DBQueryDurationHistogram(string(metrics.Select), status, time.Since(now).Seconds())
+
+		span.Finish()
+	}(time.Now())
+
+	querier := r.getReplicaQuerier(ctx)
+
+	rows, err := querier.GetAllocationsByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("querier.GetAllocationsByOrderID: %w", err)
+	}
+
+	result = make([]domain.Allocation, len(rows))
+	for idx, row := range rows {
+		result[idx] = domain.Allocation{
+			Sku:         domain.Sku(row.Sku),
+			WarehouseID: row.WarehouseID,
+			Count:       row.Count,
+		}
+	}
+
+	return result, nil
+}
+
+func (r *Repository) DecreaseOrderAllocations(
+	ctx context.Context,
+	orderID int64,
+	allocations []domain.Allocation) (err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "orderRepository.DecreaseOrderAllocations")
+	defer func(now time.Time) {
+		status := string(metrics.DBQueryStatusOK)
+		if err != nil {
+			status = string(metrics.DBQueryStatusError)
+		}
+
+		metrics.IncDBQueryCounter(string(metrics.Update), status)
+		metrics.DBQueryDurationHistogram(string(metrics.Update), status, time.Since(now).Seconds())
+
+		span.Finish()
+	}(time.Now())
+
+	if len(allocations) == 0 {
+		return nil
+	}
+
+	querier := r.getMasterQuerier(ctx)
+
+	skus, warehouseIDs, counts := splitAllocations(allocations)
+
+	err = querier.DecreaseOrderAllocations(ctx, &sqlc.DecreaseOrderAllocationsParams{
+		OrderID:      orderID,
+		Skus:         skus,
+		WarehouseIds: warehouseIDs,
+		Counts:       counts,
+	})
+	if err != nil {
+		return fmt.Errorf("querier.DecreaseOrderAllocations: %w", err)
+	}
+
+	return nil
+}
+
 func (r *Repository) SetStatusAndCreateEvent(
 	ctx context.Context,
 	orderID int64,
@@ -257,3 +569,75 @@ func (r *Repository) SetStatusAndCreateEvent(
 
 	return nil
 }
+
+func (r *Repository) GetStatusHistory(
+	ctx context.Context,
+	orderID int64) (history []domain.OrderStatusChange, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "orderRepository.GetStatusHistory")
+	defer func(now time.Time) {
+		status := string(metrics.DBQueryStatusOK)
+		if err != nil {
+			status = string(metrics.DBQueryStatusError)
+		}
+
+		metrics.IncDBQueryCounter(string(metrics.Select), status)
+		metrics.DBQueryDurationHistogram(string(metrics.Select), status, time.Since(now).Seconds())
+
+		span.Finish()
+	}(time.Now())
+
+	querier := r.getReplicaQuerier(ctx)
+
+	rows, err := querier.GetStatusHistoryByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("querier.GetStatusHistoryByOrderID: %w", err)
+	}
+
+	if len(rows) == 0 {
+		return nil, domain.ErrOrderNotFound
+	}
+
+	history = make([]domain.OrderStatusChange, len(rows))
+	for idx, row := range rows {
+		history[idx] = domain.OrderStatusChange{
+			Status:    domain.OrderStatus(row.Status),
+			ChangedAt: row.CreatedAt.Time,
+		}
+	}
+
+	return history, nil
+}
+
+func splitItems(items []domain.Item) (skus []int64, counts []int64) {
+	skus = make([]int64, len(items))
+	counts = make([]int64, len(items))
+
+	for idx, item := range items {
+		skus[idx] = int64(item.Sku)
+		counts[idx] = item.Count
+	}
+
+	return skus, counts
+}
+
+func splitAllocations(allocations []domain.Allocation) (skus []int64, warehouseIDs []int64, counts []int64) {
+	skus = make([]int64, len(allocations))
+	warehouseIDs = make([]int64, len(allocations))
+	counts = make([]int64, len(allocations))
+
+	for idx, allocation := range allocations {
+		skus[idx] = int64(allocation.Sku)
+		warehouseIDs[idx] = allocation.WarehouseID
+		counts[idx] = allocation.Count
+	}
+
+	return skus, warehouseIDs, counts
+}
+
+func toPgTimestamp(t *time.Time) pgtype.Timestamp {
+	if t == nil {
+		return pgtype.Timestamp{}
+	}
+
+	return pgtype.Timestamp{Time: t.UTC(), Valid: true}
+}
@@ -2,11 +2,11 @@ package outbox
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	sqlc "route256/loms/internal/adapter/repository/postgtres/queries_sqlc_generated"
 	"route256/loms/internal/domain"
 	"route256/loms/internal/infra/metrics"
-	txmanager "route256/loms/internal/infra/tx_manager"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -14,11 +14,15 @@ import (
 	"github.com/opentracing/opentracing-go"
 )
 
-const queueSize = 100
+const (
+	queueSize     = 100
+	NotifyChannel = "outbox_events"
+)
 
 type postgresPools interface {
-	GetWriteReplica() *pgxpool.Pool
-	GetReadReplica() *pgxpool.Pool
+	GetWriteReplica(ctx context.Context) *pgxpool.Pool
+	GetReadReplica(ctx context.Context) *pgxpool.Pool
+	TxKey() any
 }
 
 type Repository struct {
@@ -32,12 +36,21 @@ func New(connPools postgresPools) *Repository {
 }
 
 func (r *Repository) getMasterQuerier(ctx context.Context) *sqlc.Queries {
-	tx, ok := ctx.Value(txmanager.TxKey).(pgx.Tx)
+	tx, ok := ctx.Value(r.connPools.TxKey()).(pgx.Tx)
+	if ok {
+		return sqlc.New(tx)
+	}
+
+	return sqlc.New(r.connPools.GetWriteReplica(ctx))
+}
+
+func (r *Repository) getReplicaQuerier(ctx context.Context) *sqlc.Queries {
+	tx, ok := ctx.Value(r.connPools.TxKey()).(pgx.Tx)
 	if ok {
 		return sqlc.New(tx)
 	}
 
-	return sqlc.New(r.connPools.GetWriteReplica())
+	return sqlc.New(r.connPools.GetReadReplica(ctx))
 }
 
 func (r *Repository) CreateEvent(ctx context.Context, events domain.Event) (err error) {
@@ -53,12 +66,20 @@ func (r *Repository) CreateEvent(ctx context.Context, events domain.Event) (err
 		metrics.DBQueryDurationHistogram(string(metrics.Create), status, time.Since(now).Seconds())
 	}(time.Now())
 
+	traceContext, err := json.Marshal(events.TraceContext)
+	if err != nil {
+		return fmt.Errorf("json.Marshal trace context: %w", err)
+	}
+
 	querier := r.getMasterQuerier(ctx)
 
 	arg := &sqlc.CreateEventParams{
-		Topic:   events.Topic,
-		Key:     &events.Key,
-		Payload: events.Payload,
+		Topic:        events.Topic,
+		Key:          &events.Key,
+		Payload:      events.Payload,
+		TraceContext: traceContext,
+		RequestID:    events.RequestID,
+		EventID:      events.EventID,
 	}
 
 	if err := querier.CreateEvent(ctx, arg); err != nil {
@@ -97,12 +118,22 @@ func (r *Repository) FetchNextMessages(ctx context.Context, limit int32) (domain
 	domainEvents = make([]domain.Event, len(eventsSqlc))
 
 	for idx, event := range eventsSqlc {
+		var traceContext map[string]string
+		if err := json.Unmarshal(event.TraceContext, &traceContext); err != nil {
+			return nil, fmt.Errorf("json.Unmarshal trace context of event %d: %w", event.ID, err)
+		}
+
 		domainEvents[idx] = domain.Event{
-			ID:      event.ID,
-			Topic:   event.Topic,
-			Key:     *event.Key,
-			Payload: event.Payload,
-			Status:  domain.EventStatus(event.Status),
+			ID:           event.ID,
+			Topic:        event.Topic,
+			Key:          *event.Key,
+			Payload:      event.Payload,
+			Status:       domain.EventStatus(event.Status),
+			Attempts:     event.Attempts,
+			CreatedAt:    event.CreatedAt.Time,
+			TraceContext: traceContext,
+			RequestID:    event.RequestID,
+			EventID:      event.EventID,
 		}
 	}
 
@@ -147,7 +178,10 @@ func (r *Repository) MarkAsSent(ctx context.Context, orderIDs []int64) (err erro
 	return nil
 }
 
-func (r *Repository) MarkAsError(ctx context.Context, orderIDs []int64) (err error) {
+func (r *Repository) MarkAsError(
+	ctx context.Context,
+	orderIDs []int64,
+	policy domain.EventRetryPolicy) (err error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "outboxRepository.MarkAsError")
 
 	defer func(now time.Time) {
@@ -176,7 +210,12 @@ func (r *Repository) MarkAsError(ctx context.Context, orderIDs []int64) (err err
 
 		queue := orderIDs[start:end]
 
-		err := querier.MarkAsError(ctx, queue)
+		err := querier.MarkAsError(ctx, &sqlc.MarkAsErrorParams{
+			MaxAttempts:   policy.MaxAttempts,
+			BackoffBaseMs: policy.BackoffBase.Milliseconds(),
+			BackoffMaxMs:  policy.BackoffMax.Milliseconds(),
+			Ids:           queue,
+		})
 		if err != nil {
 			return fmt.Errorf("querier.MarkAsError sqlc failed: %w", err)
 		}
@@ -184,3 +223,220 @@ func (r *Repository) MarkAsError(ctx context.Context, orderIDs []int64) (err err
 
 	return nil
 }
+
+func (r *Repository) ListDeadEvents(
+	ctx context.Context,
+	filter domain.DeadEventsFilter) (domainEvents []domain.Event, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "outboxRepository.ListDeadEvents")
+	defer func(now time.Time) {
+		status := string(metrics.DBQueryStatusOK)
+		if err != nil {
+			status = string(metrics.DBQueryStatusError)
+		}
+
+		metrics.IncDBQueryCounter(string(metrics.Select), status)
+		metrics.DBQueryDurationHistogram(string(metrics.Select), status, time.Since(now).Seconds())
+
+		span.Finish()
+	}(time.Now())
+
+	querier := r.getReplicaQuerier(ctx)
+
+	eventsSqlc, err := querier.ListDeadEvents(ctx, &sqlc.ListDeadEventsParams{
+		Cursor:   filter.Cursor,
+		RowLimit: filter.Limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("querier.ListDeadEvents sqlc failed: %w", err)
+	}
+
+	domainEvents = make([]domain.Event, len(eventsSqlc))
+
+	for idx, event := range eventsSqlc {
+		var key string
+		if event.Key != nil {
+			key = *event.Key
+		}
+
+		domainEvents[idx] = domain.Event{
+			ID:            event.ID,
+			Topic:         event.Topic,
+			Key:           key,
+			Payload:       event.Payload,
+			Status:        domain.EventStatus(event.Status),
+			Attempts:      event.Attempts,
+			CreatedAt:     event.CreatedAt.Time,
+			NextAttemptAt: event.NextAttemptAt.Time,
+		}
+	}
+
+	return domainEvents, nil
+}
+
+func (r *Repository) RedriveDeadEvents(ctx context.Context, ids []int64) (redrivenIDs []int64, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "outboxRepository.RedriveDeadEvents")
+	defer func(now time.Time) {
+		status := string(metrics.DBQueryStatusOK)
+		if err != nil {
+			status = string(metrics.DBQueryStatusError)
+		}
+
+		metrics.IncDBQueryCounter(string(metrics.Update), status)
+		metrics.DBQueryDurationHistogram(string(metrics.Update), status, time.Since(now).Seconds())
+
+		span.Finish()
+	}(time.Now())
+
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	querier := r.getMasterQuerier(ctx)
+
+	for start := 0; start < len(ids); start += queueSize {
+		end := start + queueSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		queue, err := querier.RedriveDeadEvents(ctx, ids[start:end])
+		if err != nil {
+			return nil, fmt.Errorf("querier.RedriveDeadEvents sqlc failed: %w", err)
+		}
+
+		redrivenIDs = append(redrivenIDs, queue...)
+	}
+
+	return redrivenIDs, nil
+}
+
+func (r *Repository) CreateArchivePartitions(ctx context.Context, retention time.Duration) (err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "outboxRepository.CreateArchivePartitions")
+	defer func(now time.Time) {
+		status := string(metrics.DBQueryStatusOK)
+		if err != nil {
+			status = string(metrics.DBQueryStatusError)
+		}
+
+		metrics.IncDBQueryCounter(string(metrics.Create), status)
+		metrics.DBQueryDurationHistogram(string(metrics.Create), status, time.Since(now).Seconds())
+
+		span.Finish()
+	}(time.Now())
+
+	querier := r.getMasterQuerier(ctx)
+
+	if err := querier.CreateArchivePartitions(ctx, int64(retention.Seconds())); err != nil {
+		return fmt.Errorf("querier.CreateArchivePartitions sqlc failed: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) ArchiveSentEvents(
+	ctx context.Context,
+	retention time.Duration,
+	limit int32) (archived int64, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "outboxRepository.ArchiveSentEvents")
+	defer func(now time.Time) {
+		status := string(metrics.DBQueryStatusOK)
+		if err != nil {
+			status = string(metrics.DBQueryStatusError)
+		}
+
+		metrics.IncDBQueryCounter(string(metrics.Delete), status)
+		metrics.DBQueryDurationHistogram(string(metrics.Delete), status, time.Since(now).Seconds())
+
+		span.Finish()
+	}(time.Now())
+
+	querier := r.getMasterQuerier(ctx)
+
+	archived, err = querier.ArchiveSentEvents(ctx, &sqlc.ArchiveSentEventsParams{
+		RetentionSec: int64(retention.Seconds()),
+		RowLimit:     limit,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("querier.ArchiveSentEvents sqlc failed: %w", err)
+	}
+
+	return archived, nil
+}
+
+func (r *Repository) DeleteSentEvents(
+	ctx context.Context,
+	retention time.Duration,
+	limit int32) (deleted int64, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "outboxRepository.DeleteSentEvents")
+	defer func(now time.Time) {
+		status := string(metrics.DBQueryStatusOK)
+		if err != nil {
+			status = string(metrics.DBQueryStatusError)
+		}
+
+		metrics.IncDBQueryCounter(string(metrics.Delete), status)
+		metrics.DBQueryDurationHistogram(string(metrics.Delete), status, time.Since(now).Seconds())
+
+		span.Finish()
+	}(time.Now())
+
+	querier := r.getMasterQuerier(ctx)
+
+	deleted, err = querier.DeleteSentEvents(ctx, &sqlc.DeleteSentEventsParams{
+		RetentionSec: int64(retention.Seconds()),
+		RowLimit:     limit,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("querier.DeleteSentEvents sqlc failed: %w", err)
+	}
+
+	return deleted, nil
+}
+
+func (r *Repository) GetOldestUndeliveredAge(ctx context.Context) (age time.Duration, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "outboxRepository.GetOldestUndeliveredAge")
+	defer func(now time.Time) {
+		status := string(metrics.DBQueryStatusOK)
+		if err != nil {
+			status = string(metrics.DBQueryStatusError)
+		}
+
+		metrics.IncDBQueryCounter(string(metrics.Select), status)
+		metrics.DBQueryDurationHistogram(string(metrics.Select), status, time.Since(now).Seconds())
+
+		span.Finish()
+	}(time.Now())
+
+	querier := r.getMasterQuerier(ctx)
+
+	ageSec, err := querier.GetOldestUndeliveredAge(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("querier.GetOldestUndeliveredAge sqlc failed: %w", err)
+	}
+
+	return time.Duration(ageSec * float64(time.Second)), nil
+}
+
+func (r *Repository) GetOldestPendingAge(ctx context.Context) (age time.Duration, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "outboxRepository.GetOldestPendingAge")
+	defer func(now time.Time) {
+		status := string(metrics.DBQueryStatusOK)
+		if err != nil {
+			status = string(metrics.DBQueryStatusError)
+		}
+
+		metrics.IncDBQueryCounter(string(metrics.Select), status)
+		metrics.DBQueryDurationHistogram(string(metrics.Select), status, time.Since(now).Seconds())
+
+		span.Finish()
+	}(time.Now())
+
+	querier := r.getMasterQuerier(ctx)
+
+	ageSec, err := querier.GetOldestPendingAge(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("querier.GetOldestPendingAge sqlc failed: %w", err)
+	}
+
+	return time.Duration(ageSec * float64(time.Second)), nil
+}
@@ -0,0 +1,163 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	sqlc "route256/loms/internal/adapter/repository/postgtres/queries_sqlc_generated"
+	"route256/loms/internal/domain"
+	"route256/loms/internal/infra/metrics"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/opentracing/opentracing-go"
+)
+
+type postgresPools interface {
+	GetWriteReplica(ctx context.Context) *pgxpool.Pool
+	TxKey() any
+}
+
+type Repository struct {
+	connPools postgresPools
+}
+
+func New(connPools postgresPools) *Repository {
+	return &Repository{
+		connPools: connPools,
+	}
+}
+
+func (r *Repository) getMasterQuerier(ctx context.Context) *sqlc.Queries {
+	tx, ok := ctx.Value(r.connPools.TxKey()).(pgx.Tx)
+	if ok {
+		return sqlc.New(tx)
+	}
+
+	return sqlc.New(r.connPools.GetWriteReplica(ctx))
+}
+
+func (r *Repository) CreatePaymentIntent(ctx context.Context, intent domain.PaymentIntent) (_ domain.PaymentIntent, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "paymentRepository.CreatePaymentIntent")
+	defer func(now time.Time) {
+		status := string(metrics.DBQueryStatusOK)
+		if err != nil {
+			status = string(metrics.DBQueryStatusError)
+		}
+
+		metrics.IncDBQueryCounter(string(metrics.Create), status)
+		metrics.DBQueryDurationHistogram(string(metrics.Create), status, time.Since(now).Seconds())
+
+		span.Finish()
+	}(time.Now())
+
+	querier := r.getMasterQuerier(ctx)
+
+	row, err := querier.CreatePaymentIntent(ctx, &sqlc.CreatePaymentIntentParams{
+		OrderID:  intent.OrderID,
+		Provider: intent.Provider,
+		Amount:   intent.Amount,
+		Currency: intent.Currency,
+	})
+	if err != nil {
+		return domain.PaymentIntent{}, fmt.Errorf("querier.CreatePaymentIntent: %w", err)
+	}
+
+	return repackPaymentIntent(row), nil
+}
+
+func (r *Repository) GetPaymentIntent(ctx context.Context, orderID int64) (intent domain.PaymentIntent, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "paymentRepository.GetPaymentIntent")
+	defer func(now time.Time) {
+		status := string(metrics.DBQueryStatusOK)
+		if err != nil {
+			status = string(metrics.DBQueryStatusError)
+		}
+
+		metrics.IncDBQueryCounter(string(metrics.Select), status)
+		metrics.DBQueryDurationHistogram(string(metrics.Select), status, time.Since(now).Seconds())
+
+		span.Finish()
+	}(time.Now())
+
+	querier := r.getMasterQuerier(ctx)
+
+	row, err := querier.GetPaymentIntent(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.PaymentIntent{}, domain.ErrPaymentNotFound
+		}
+
+		return domain.PaymentIntent{}, fmt.Errorf("querier.GetPaymentIntent: %w", err)
+	}
+
+	return repackPaymentIntent(row), nil
+}
+
+func (r *Repository) UpdatePaymentIntent(ctx context.Context, orderID int64, result domain.PaymentResult) (err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "paymentRepository.UpdatePaymentIntent")
+	defer func(now time.Time) {
+		status := string(metrics.DBQueryStatusOK)
+		if err != nil {
+			status = string(metrics.DBQueryStatusError)
+		}
+
+		metrics.IncDBQueryCounter(string(metrics.Update), status)
+		metrics.DBQueryDurationHistogram(string(metrics.Update), status, time.Since(now).Seconds())
+
+		span.Finish()
+	}(time.Now())
+
+	querier := r.getMasterQuerier(ctx)
+
+	arg := &sqlc.UpdatePaymentIntentParams{
+		Status:  sqlc.PaymentStatus(result.Status),
+		OrderID: orderID,
+	}
+
+	if result.ProviderPaymentID != "" {
+		arg.ProviderPaymentID = &result.ProviderPaymentID
+	}
+
+	if result.Reason != "" {
+		arg.DeclineReason = &result.Reason
+	}
+
+	if result.Amount != 0 {
+		arg.Amount = &result.Amount
+	}
+
+	if result.Currency != "" {
+		arg.Currency = &result.Currency
+	}
+
+	if err := querier.UpdatePaymentIntent(ctx, arg); err != nil {
+		return fmt.Errorf("querier.UpdatePaymentIntent: %w", err)
+	}
+
+	return nil
+}
+
+func repackPaymentIntent(row *sqlc.PaymentIntent) domain.PaymentIntent {
+	intent := domain.PaymentIntent{
+		OrderID:   row.OrderID,
+		Provider:  row.Provider,
+		Status:    domain.PaymentStatus(row.Status),
+		Attempts:  row.Attempts,
+		Amount:    row.Amount,
+		Currency:  row.Currency,
+		CreatedAt: row.CreatedAt.Time,
+		UpdatedAt: row.UpdatedAt.Time,
+	}
+
+	if row.ProviderPaymentID != nil {
+		intent.ProviderPaymentID = *row.ProviderPaymentID
+	}
+
+	if row.DeclineReason != nil {
+		intent.DeclineReason = *row.DeclineReason
+	}
+
+	return intent
+}
@@ -1,35 +1,159 @@
 -- name: CreateOrder :one
-INSERT INTO orders (user_id)
-VALUES ($1)
-RETURNING id;
+WITH created AS (
+    INSERT INTO orders (user_id)
+    VALUES ($1)
+    RETURNING id, status, created_at
+)
+INSERT INTO order_status_history (order_id, status, created_at)
+SELECT c.id, c.status, c.created_at
+FROM created c
+RETURNING order_id;
 
 -- name: GetByOrderID :many
 SELECT 
     o.user_id,
     o.status,
+    o.created_at,
+    COALESCE(o.updated_at, o.created_at)::timestamp AS updated_at,
+    o.total_price,
+    o.currency,
     oi.sku,
-    oi.count
+    (oi.count - oi.cancelled_count - oi.returned_count)::bigint AS count,
+    oi.price,
+    oi.currency AS item_currency
 FROM orders o
 JOIN order_items oi ON o.id = oi.order_id
 WHERE o.id = $1;
 
 -- name: SetStatus :exec
-UPDATE orders
-SET status = $1,
-    updated_at = NOW()
-WHERE id = $2;
+WITH updated AS (
+    UPDATE orders
+    SET status = $1,
+        updated_at = NOW()
+    WHERE orders.id = $2
+    RETURNING id, status, updated_at
+)
+INSERT INTO order_status_history (order_id, status, created_at)
+SELECT u.id, u.status, u.updated_at
+FROM updated u;
 
 -- name: GetByOrderIDForUpdate :many
 SELECT 
     o.user_id,
     o.status,
+    o.total_price,
+    o.currency,
     oi.sku,
-    oi.count
+    (oi.count - oi.cancelled_count - oi.returned_count)::bigint AS count,
+    oi.price,
+    oi.currency AS item_currency
 FROM orders o
 JOIN order_items oi ON o.id = oi.order_id
 WHERE o.id = $1
 FOR UPDATE;
 
 -- name: CreateOrderItems :exec
-INSERT INTO order_items (order_id, sku, count)
-SELECT unnest(@order_ids::bigint[]), unnest(@skus::bigint[]), unnest(@counts::bigint[]);
+WITH inserted AS (
+    INSERT INTO order_items (order_id, sku, count, price, currency)
+    SELECT
+        unnest(@order_ids::bigint[]),
+        unnest(@skus::bigint[]),
+        unnest(@counts::bigint[]),
+        unnest(@prices::bigint[]),
+        unnest(@currencies::text[])
+    RETURNING order_id, count, price, currency
+)
+UPDATE orders o
+SET total_price = o.total_price + t.total,
+    currency = t.currency
+FROM (
+    SELECT order_id, SUM(count * price)::bigint AS total, MIN(currency) AS currency
+    FROM inserted
+    GROUP BY order_id
+) AS t
+WHERE o.id = t.order_id;
+
+-- name: ListOrdersByUserID :many
+SELECT id, status, created_at, total_price, currency
+FROM orders
+WHERE user_id = @user_id
+  AND (cardinality(@statuses::text[]) = 0 OR status::text = ANY(@statuses::text[]))
+  AND (sqlc.narg(created_from)::timestamp IS NULL OR created_at >= sqlc.narg(created_from)::timestamp)
+  AND (sqlc.narg(created_to)::timestamp IS NULL OR created_at < sqlc.narg(created_to)::timestamp)
+  AND (@cursor::bigint = 0 OR id < @cursor::bigint)
+ORDER BY id DESC
+LIMIT @row_limit;
+
+-- name: GetItemsByOrderIDs :many
+SELECT order_id, sku, (count - cancelled_count - returned_count)::bigint AS count, price, currency
+FROM order_items
+WHERE order_id = ANY(@order_ids::bigint[]);
+
+-- name: GetExpiredUnpaidOrderIDsForUpdate :many
+SELECT id
+FROM orders
+WHERE status = 'awaiting payment'
+  AND updated_at < NOW() - (sqlc.arg(payment_deadline_sec)::bigint * INTERVAL '1 second')
+  AND NOT (id = ANY(COALESCE(sqlc.arg(excluded_ids)::bigint[], '{}')))
+ORDER BY updated_at, id
+LIMIT @row_limit
+FOR UPDATE SKIP LOCKED;
+
+-- name: CancelOrderItems :exec
+WITH cancelled AS (
+    UPDATE order_items oi
+    SET cancelled_count = oi.cancelled_count + u.count
+    FROM (
+        SELECT
+            unnest(sqlc.arg(skus)::bigint[]) AS sku,
+            unnest(sqlc.arg(counts)::bigint[]) AS count
+    ) AS u
+    WHERE oi.order_id = sqlc.arg(order_id) AND oi.sku = u.sku
+    RETURNING oi.price * u.count AS amount
+)
+UPDATE orders
+SET total_price = total_price - (SELECT COALESCE(SUM(amount), 0) FROM cancelled)::bigint
+WHERE id = sqlc.arg(order_id);
+
+-- name: ReturnOrderItems :exec
+UPDATE order_items oi
+SET returned_count = oi.returned_count + u.count
+FROM (
+    SELECT
+        unnest(sqlc.arg(skus)::bigint[]) AS sku,
+        unnest(sqlc.arg(counts)::bigint[]) AS count
+) AS u
+WHERE oi.order_id = sqlc.arg(order_id) AND oi.sku = u.sku;
+
+-- name: CreateOrderAllocations :exec
+INSERT INTO order_item_allocations (order_id, sku, warehouse_id, count)
+SELECT
+    sqlc.arg(order_id)::bigint,
+    unnest(sqlc.arg(skus)::bigint[]),
+    unnest(sqlc.arg(warehouse_ids)::bigint[]),
+    unnest(sqlc.arg(counts)::bigint[]);
+
+-- name: GetAllocationsByOrderID :many
+SELECT sku, warehouse_id, count
+FROM order_item_allocations
+WHERE order_id = $1 AND count > 0
+ORDER BY sku, warehouse_id;
+
+-- name: DecreaseOrderAllocations :exec
+UPDATE order_item_allocations a
+SET count = a.count - u.count
+FROM (
+    SELECT
+        unnest(sqlc.arg(skus)::bigint[]) AS sku,
+        unnest(sqlc.arg(warehouse_ids)::bigint[]) AS warehouse_id,
+        unnest(sqlc.arg(counts)::bigint[]) AS count
+) AS u
+WHERE a.order_id = sqlc.arg(order_id)
+  AND a.sku = u.sku
+  AND a.warehouse_id = u.warehouse_id;
+
+-- name: GetStatusHistoryByOrderID :many
+SELECT status, created_at
+FROM order_status_history
+WHERE order_id = $1
+ORDER BY id;
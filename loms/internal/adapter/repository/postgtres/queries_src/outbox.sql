@@ -1,11 +1,12 @@
 -- name: CreateEvent :exec
-INSERT INTO outbox (topic, key, payload)
-VALUES ($1, $2, $3);
+INSERT INTO outbox (topic, key, payload, trace_context, request_id, event_id)
+VALUES ($1, $2, $3, $4, $5, $6);
 
 -- name: FetchNextMessages :many
-SELECT id, topic, key, payload, status 
+SELECT id, topic, key, payload, status, attempts, created_at, trace_context, request_id, event_id
 FROM outbox
-WHERE status = 'new'
+WHERE status IN ('new', 'pending')
+  AND next_attempt_at <= NOW()
 ORDER BY id
 LIMIT $1
 FOR UPDATE SKIP LOCKED;
@@ -20,6 +21,87 @@ WHERE id = ANY(sqlc.arg(ids)::bigint[]);
 -- name: MarkAsError :exec
 UPDATE outbox
 SET
-    status = 'error',
-    sent_at = NOW()
-WHERE id = ANY(sqlc.arg(ids)::bigint[]);
\ No newline at end of file
+    attempts = attempts + 1,
+    status = CASE
+        WHEN attempts + 1 >= sqlc.arg(max_attempts)::int THEN 'dead'::outbox_status
+        ELSE 'pending'::outbox_status
+    END,
+    next_attempt_at = NOW() + LEAST(
+        sqlc.arg(backoff_base_ms)::bigint * power(2, attempts),
+        sqlc.arg(backoff_max_ms)::bigint
+    ) * INTERVAL '1 millisecond'
+WHERE id = ANY(sqlc.arg(ids)::bigint[]);
+
+-- name: ListDeadEvents :many
+SELECT id, topic, key, payload, status, attempts, created_at, next_attempt_at
+FROM outbox
+WHERE status = 'dead'
+  AND (@cursor::bigint = 0 OR id < @cursor::bigint)
+ORDER BY id DESC
+LIMIT @row_limit;
+
+-- name: RedriveDeadEvents :many
+UPDATE outbox
+SET
+    status = 'new',
+    attempts = 0,
+    next_attempt_at = NOW()
+WHERE id = ANY(sqlc.arg(ids)::bigint[])
+  AND status = 'dead'
+RETURNING id;
+
+-- name: CreateArchivePartitions :exec
+SELECT outbox_archive_create_partition(m::timestamp)
+FROM generate_series(
+    date_trunc('month', (
+        SELECT MIN(sent_at)
+        FROM outbox
+        WHERE status = 'sent'
+          AND sent_at < NOW() - (sqlc.arg(retention_sec)::bigint * INTERVAL '1 second')
+    )),
+    NOW() - (sqlc.arg(retention_sec)::bigint * INTERVAL '1 second') + INTERVAL '1 month',
+    INTERVAL '1 month'
+) AS m;
+
+-- name: ArchiveSentEvents :execrows
+WITH batch AS (
+    SELECT outbox.id
+    FROM outbox
+    WHERE outbox.status = 'sent'
+      AND outbox.sent_at < NOW() - (sqlc.arg(retention_sec)::bigint * INTERVAL '1 second')
+    ORDER BY outbox.sent_at
+    LIMIT sqlc.arg(row_limit)
+    FOR UPDATE SKIP LOCKED
+), deleted AS (
+    DELETE FROM outbox o
+    USING batch b
+    WHERE o.id = b.id
+    RETURNING o.id, o.topic, o.key, o.payload, o.attempts, o.created_at, o.sent_at, o.trace_context, o.request_id, o.event_id
+)
+INSERT INTO outbox_archive (id, topic, key, payload, attempts, created_at, sent_at, trace_context, request_id, event_id)
+SELECT d.id, d.topic, d.key, d.payload, d.attempts, d.created_at, d.sent_at, d.trace_context, d.request_id, d.event_id
+FROM deleted d;
+
+-- name: DeleteSentEvents :execrows
+WITH batch AS (
+    SELECT outbox.id
+    FROM outbox
+    WHERE outbox.status = 'sent'
+      AND outbox.sent_at < NOW() - (sqlc.arg(retention_sec)::bigint * INTERVAL '1 second')
+    ORDER BY outbox.sent_at
+    LIMIT sqlc.arg(row_limit)
+    FOR UPDATE SKIP LOCKED
+)
+DELETE FROM outbox o
+USING batch b
+WHERE o.id = b.id;
+
+-- name: GetOldestUndeliveredAge :one
+SELECT COALESCE(EXTRACT(EPOCH FROM NOW() - MIN(created_at)), 0)::float8 AS age_sec
+FROM outbox
+WHERE status <> 'sent';
+
+-- name: GetOldestPendingAge :one
+SELECT COALESCE(EXTRACT(EPOCH FROM NOW() - MIN(created_at)), 0)::float8 AS age_sec
+FROM outbox
+WHERE status IN ('new', 'pending');
@@ -0,0 +1,20 @@
+-- name: CreatePaymentIntent :one
+INSERT INTO payment_intents (order_id, provider, amount, currency)
+VALUES (@order_id, @provider, @amount, @currency)
+RETURNING order_id, provider, provider_payment_id, status, attempts, decline_reason, created_at, updated_at, amount, currency;
+
+-- name: GetPaymentIntent :one
+SELECT order_id, provider, provider_payment_id, status, attempts, decline_reason, created_at, updated_at, amount, currency
+FROM payment_intents
+WHERE order_id = @order_id;
+
+-- name: UpdatePaymentIntent :exec
+UPDATE payment_intents
+SET status = @status,
+    provider_payment_id = COALESCE(sqlc.narg(provider_payment_id), provider_payment_id),
+    decline_reason = sqlc.narg(decline_reason),
+    amount = COALESCE(sqlc.narg(amount), amount),
+    currency = COALESCE(sqlc.narg(currency), currency),
+    attempts = attempts + CASE WHEN @status = 'declined'::payment_status THEN 1 ELSE 0 END,
+    updated_at = NOW()
+WHERE order_id = @order_id;
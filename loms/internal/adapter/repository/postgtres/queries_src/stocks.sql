@@ -1,12 +1,21 @@
 -- name: GetStockBySku :one
-SELECT sku, total_count, reserved
+SELECT sku, SUM(total_count)::bigint AS total_count, SUM(reserved)::bigint AS reserved
 FROM stocks
-WHERE sku = $1;
+WHERE sku = $1
+GROUP BY sku;
+
+-- name: GetWarehouseStocksBySku :many
+SELECT warehouse_id, sku, total_count, reserved
+FROM stocks
+WHERE sku = $1
+ORDER BY warehouse_id;
 
 -- name: GetStocksBySkuForUpdate :many
-SELECT sku, total_count, reserved 
+SELECT warehouse_id, sku, total_count, reserved 
 FROM stocks 
-WHERE sku = ANY(sqlc.arg(sku)::bigint[]) FOR UPDATE;
+WHERE sku = ANY(sqlc.arg(sku)::bigint[])
+ORDER BY sku, warehouse_id
+FOR UPDATE;
 
 -- name: UpdateStocks :exec
 UPDATE stocks s
@@ -16,8 +25,46 @@ SET
     updated_at = now()
 FROM (
     SELECT 
+        unnest(sqlc.arg(warehouse_id)::bigint[]) AS warehouse_id,
         unnest(sqlc.arg(sku)::bigint[]) AS sku,
         unnest(sqlc.arg(total_count)::bigint[]) AS total_count,
         unnest(sqlc.arg(reserved)::bigint[]) AS reserved
 ) AS u
-WHERE s.sku = u.sku;
\ No newline at end of file
+WHERE s.warehouse_id = u.warehouse_id AND s.sku = u.sku;
+
+-- name: CreateStock :execrows
+INSERT INTO stocks (warehouse_id, sku, total_count, reserved)
+VALUES ($1, $2, $3, 0)
+ON CONFLICT (warehouse_id, sku) DO NOTHING;
+
+-- name: CreateStockMovements :exec
+INSERT INTO stock_movements (
+    warehouse_id, sku, kind, order_id, reason,
+    total_delta, reserved_delta, total_count, reserved
+)
+SELECT
+    u.warehouse_id, u.sku, u.kind, NULLIF(u.order_id, 0), NULLIF(u.reason, ''),
+    u.total_delta, u.reserved_delta, u.total_count, u.reserved
+FROM (
+    SELECT
+        unnest(sqlc.arg(warehouse_ids)::bigint[]) AS warehouse_id,
+        unnest(sqlc.arg(skus)::bigint[]) AS sku,
+        unnest(sqlc.arg(kinds)::text[]) AS kind,
+        unnest(sqlc.arg(order_ids)::bigint[]) AS order_id,
+        unnest(sqlc.arg(reasons)::text[]) AS reason,
+        unnest(sqlc.arg(total_deltas)::bigint[]) AS total_delta,
+        unnest(sqlc.arg(reserved_deltas)::bigint[]) AS reserved_delta,
+        unnest(sqlc.arg(total_counts)::bigint[]) AS total_count,
+        unnest(sqlc.arg(reserved)::bigint[]) AS reserved
+) AS u;
+
+-- name: ListStockMovementsBySku :many
+SELECT
+    id, warehouse_id, sku, kind, order_id, reason,
+    total_delta, reserved_delta, total_count, reserved, created_at
+FROM stock_movements
+WHERE sku = @sku
+  AND (@warehouse_id::bigint = 0 OR warehouse_id = @warehouse_id::bigint)
+  AND (@cursor::bigint = 0 OR id < @cursor::bigint)
+ORDER BY id DESC
+LIMIT @row_limit;
@@ -18,8 +18,8 @@ import (
 const batchSize = 1000
 
 type postgresPools interface {
-	GetWriteReplica() *pgxpool.Pool
-	GetReadReplica() *pgxpool.Pool
+	GetWriteReplica(ctx context.Context) *pgxpool.Pool
+	GetReadReplica(ctx context.Context) *pgxpool.Pool
 }
 
 type Repository struct {
@@ -38,7 +38,7 @@ func (r *Repository) getMasterQuerier(ctx context.Context) *sqlc.Queries {
 		return sqlc.New(tx)
 	}
 
-	return sqlc.New(r.connPools.GetWriteReplica())
+	return sqlc.New(r.connPools.GetWriteReplica(ctx))
 }
 
 func (r *Repository) getReplicaQuerier(ctx context.Context) *sqlc.Queries {
@@ -47,7 +47,7 @@ func (r *Repository) getReplicaQuerier(ctx context.Context) *sqlc.Queries {
 		return sqlc.New(tx)
 	}
 
-	return sqlc.New(r.connPools.GetReadReplica())
+	return sqlc.New(r.connPools.GetReadReplica(ctx))
 }
 
 func (r *Repository) GetStockBySku(ctx context.Context, sku domain.Sku) (stock domain.Stock, err error) {
@@ -81,7 +81,47 @@ func (r *Repository) GetStockBySku(ctx context.Context, sku domain.Sku) (stock d
 	}, nil
 }
 
-func (r *Repository) GetStocksBySkuForUpdate(ctx context.Context, items []domain.Item) (result map[domain.Sku]domain.Stock, err error) {
+func (r *Repository) GetWarehouseStocksBySku(ctx context.Context, sku domain.Sku) (result []domain.WarehouseStock, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "stockRepository.GetWarehouseStocksBySku")
+	defer func(now time.Time) {
+		status := string(metrics.DBQueryStatusOK)
+		if err != nil {
+			status = string(metrics.DBQueryStatusError)
+		}
+
+		metrics.IncDBQueryCounter(string(metrics.Select), status)
+		metrics.DBQueryDurationHistogram(string(metrics.Select), status, time.Since(now).Seconds())
+
+		span.Finish()
+	}(time.Now())
+
+	querier := r.getReplicaQuerier(ctx)
+
+	stocks, err := querier.GetWarehouseStocksBySku(ctx, int64(sku))
+	if err != nil {
+		return nil, fmt.Errorf("querier.GetWarehouseStocksBySku: %w", err)
+	}
+
+	if len(stocks) == 0 {
+		return nil, domain.ErrStockNotFound
+	}
+
+	result = make([]domain.WarehouseStock, len(stocks))
+	for idx, value := range stocks {
+		result[idx] = domain.WarehouseStock{
+			WarehouseID: value.WarehouseID,
+			Sku:         domain.Sku(value.Sku),
+			TotalCount:  value.TotalCount,
+			Reserved:    value.Reserved,
+		}
+	}
+
+	return result, nil
+}
+
+func (r *Repository) GetStocksBySkuForUpdate(
+	ctx context.Context,
+	items []domain.Item) (result map[domain.Sku][]domain.WarehouseStock, err error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "stockRepository.GetStocksBySkuForUpdate")
 
 	defer func(now time.Time) {
@@ -116,18 +156,21 @@ func (r *Repository) GetStocksBySkuForUpdate(ctx context.Context, items []domain
 		return nil, domain.ErrStockNotFound
 	}
 
-	result = make(map[domain.Sku]domain.Stock, len(stocks))
+	result = make(map[domain.Sku][]domain.WarehouseStock, len(items))
 	for _, value := range stocks {
-		result[domain.Sku(value.Sku)] = domain.Stock{
-			TotalCount: value.TotalCount,
-			Reserved:   value.Reserved,
-		}
+		sku := domain.Sku(value.Sku)
+		result[sku] = append(result[sku], domain.WarehouseStock{
+			WarehouseID: value.WarehouseID,
+			Sku:         sku,
+			TotalCount:  value.TotalCount,
+			Reserved:    value.Reserved,
+		})
 	}
 
 	return result, nil
 }
 
-func (r *Repository) UpdateStocks(ctx context.Context, stocks map[domain.Sku]domain.Stock) (err error) {
+func (r *Repository) UpdateStocks(ctx context.Context, stocks []domain.WarehouseStock) (err error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "stockRepository.UpdateStocks")
 	defer func(now time.Time) {
 		status := string(metrics.DBQueryStatusOK)
@@ -147,40 +190,30 @@ func (r *Repository) UpdateStocks(ctx context.Context, stocks map[domain.Sku]dom
 		return nil
 	}
 
-	type pair struct {
-		Sku   int64
-		Stock domain.Stock
-	}
-
-	pairs := make([]pair, 0, len(stocks))
-	for sku, stock := range stocks {
-		pairs = append(pairs, pair{
-			Sku:   int64(sku),
-			Stock: stock,
-		})
-	}
-
-	for start := 0; start < len(pairs); start += batchSize {
+	for start := 0; start < len(stocks); start += batchSize {
 		end := start + batchSize
-		if end > len(pairs) {
-			end = len(pairs)
+		if end > len(stocks) {
+			end = len(stocks)
 		}
-		batch := pairs[start:end]
+		batch := stocks[start:end]
 
+		warehouseIDs := make([]int64, 0, len(batch))
 		skus := make([]int64, 0, len(batch))
 		totalCounts := make([]int64, 0, len(batch))
 		reservedCounts := make([]int64, 0, len(batch))
 
-		for _, p := range batch {
-			skus = append(skus, p.Sku)
-			totalCounts = append(totalCounts, p.Stock.TotalCount)
-			reservedCounts = append(reservedCounts, p.Stock.Reserved)
+		for _, stock := range batch {
+			warehouseIDs = append(warehouseIDs, stock.WarehouseID)
+			skus = append(skus, int64(stock.Sku))
+			totalCounts = append(totalCounts, stock.TotalCount)
+			reservedCounts = append(reservedCounts, stock.Reserved)
 		}
 
 		err := querier.UpdateStocks(ctx, &sqlc.UpdateStocksParams{
-			Sku:        skus,
-			TotalCount: totalCounts,
-			Reserved:   reservedCounts,
+			WarehouseID: warehouseIDs,
+			Sku:         skus,
+			TotalCount:  totalCounts,
+			Reserved:    reservedCounts,
 		})
 		if err != nil {
 			return fmt.Errorf("querier.UpdateStocks failed: %w", err)
@@ -189,3 +222,149 @@ func (r *Repository) UpdateStocks(ctx context.Context, stocks map[domain.Sku]dom
 
 	return nil
 }
+
+func (r *Repository) CreateStock(ctx context.Context, stock domain.WarehouseStock) (err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "stockRepository.CreateStock")
+	defer func(now time.Time) {
+		status := string(metrics.DBQueryStatusOK)
+		if err != nil {
+			status = string(metrics.DBQueryStatusError)
+		}
+
+		metrics.IncDBQueryCounter(string(metrics.Create), status)
+		metrics.DBQueryDurationHistogram(string(metrics.Create), status, time.Since(now).Seconds())
+
+		span.Finish()
+	}(time.Now())
+
+	querier := r.getMasterQuerier(ctx)
+
+	rows, err := querier.CreateStock(ctx, &sqlc.CreateStockParams{
+		WarehouseID: stock.WarehouseID,
+		Sku:         int64(stock.Sku),
+		TotalCount:  stock.TotalCount,
+	})
+	if err != nil {
+		return fmt.Errorf("querier.CreateStock: %w", err)
+	}
+
+	if rows == 0 {
+		return domain.ErrStockAlreadyExists
+	}
+
+	return nil
+}
+
+func (r *Repository) CreateStockMovements(ctx context.Context, movements []domain.StockMovement) (err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "stockRepository.CreateStockMovements")
+	defer func(now time.Time) {
+		status := string(metrics.DBQueryStatusOK)
+		if err != nil {
+			status = string(metrics.DBQueryStatusError)
+		}
+
+		metrics.IncDBQueryCounter(string(metrics.Create), status)
+		metrics.DBQueryDurationHistogram(string(metrics.Create), status, time.Since(now).Seconds())
+
+		span.Finish()
+	}(time.Now())
+
+	querier := r.getMasterQuerier(ctx)
+
+	if len(movements) == 0 {
+		return nil
+	}
+
+	for start := 0; start < len(movements); start += batchSize {
+		end := start + batchSize
+		if end > len(movements) {
+			end = len(movements)
+		}
+
+		batch := movements[start:end]
+
+		params := &sqlc.CreateStockMovementsParams{
+			WarehouseIds:   make([]int64, len(batch)),
+			Skus:           make([]int64, len(batch)),
+			Kinds:          make([]string, len(batch)),
+			OrderIds:       make([]int64, len(batch)),
+			Reasons:        make([]string, len(batch)),
+			TotalDeltas:    make([]int64, len(batch)),
+			ReservedDeltas: make([]int64, len(batch)),
+			TotalCounts:    make([]int64, len(batch)),
+			Reserved:       make([]int64, len(batch)),
+		}
+
+		for i, movement := range batch {
+			params.WarehouseIds[i] = movement.WarehouseID
+			params.Skus[i] = int64(movement.Sku)
+			params.Kinds[i] = string(movement.Kind)
+			params.OrderIds[i] = movement.OrderID
+			params.Reasons[i] = movement.Reason
+			params.TotalDeltas[i] = movement.TotalDelta
+			params.ReservedDeltas[i] = movement.ReservedDelta
+			params.TotalCounts[i] = movement.TotalCount
+			params.Reserved[i] = movement.Reserved
+		}
+
+		if err := querier.CreateStockMovements(ctx, params); err != nil {
+			return fmt.Errorf("querier.CreateStockMovements: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (r *Repository) ListStockMovements(
+	ctx context.Context,
+	filter domain.StockHistoryFilter) (movements []domain.StockMovement, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "stockRepository.ListStockMovements")
+	defer func(now time.Time) {
+		status := string(metrics.DBQueryStatusOK)
+		if err != nil {
+			status = string(metrics.DBQueryStatusError)
+		}
+
+		metrics.IncDBQueryCounter(string(metrics.Select), status)
+		metrics.DBQueryDurationHistogram(string(metrics.Select), status, time.Since(now).Seconds())
+
+		span.Finish()
+	}(time.Now())
+
+	querier := r.getReplicaQuerier(ctx)
+
+	rows, err := querier.ListStockMovementsBySku(ctx, &sqlc.ListStockMovementsBySkuParams{
+		Sku:         int64(filter.Sku),
+		WarehouseID: filter.WarehouseID,
+		Cursor:      filter.Cursor,
+		RowLimit:    filter.Limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("querier.ListStockMovementsBySku: %w", err)
+	}
+
+	movements = make([]domain.StockMovement, len(rows))
+	for idx, row := range rows {
+		movements[idx] = domain.StockMovement{
+			ID:            row.ID,
+			WarehouseID:   row.WarehouseID,
+			Sku:           domain.Sku(row.Sku),
+			Kind:          domain.StockMovementKind(row.Kind),
+			TotalDelta:    row.TotalDelta,
+			ReservedDelta: row.ReservedDelta,
+			TotalCount:    row.TotalCount,
+			Reserved:      row.Reserved,
+			CreatedAt:     row.CreatedAt.Time,
+		}
+
+		if row.OrderID != nil {
+			movements[idx].OrderID = *row.OrderID
+		}
+
+		if row.Reason != nil {
+			movements[idx].Reason = *row.Reason
+		}
+	}
+
+	return movements, nil
+}
@@ -2,17 +2,39 @@ package api
 
 import (
 	"context"
+	"fmt"
 	desc "route256/loms/internal/pb/loms/v1"
+	"slices"
+	"strings"
 
 	"github.com/opentracing/opentracing-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func (hdl *Implementation) Check(ctx context.Context, _ *desc.HealthCheckRequest) (*desc.HealthCheckResponse, error) {
 	span, _ := opentracing.StartSpanFromContext(ctx, "api.Check")
 	defer span.Finish()
 
+	ready, results := hdl.healthChecker.Ready()
+	if !ready {
+		failed := make([]string, 0, len(results))
+		for name, err := range results {
+			if err != nil {
+				failed = append(failed, fmt.Sprintf("%s: %v", name, err))
+			}
+		}
+
+		if len(failed) == 0 {
+			return nil, status.Error(codes.Unavailable, "not ready: health checks pending")
+		}
+
+		slices.Sort(failed)
+
+		return nil, status.Error(codes.Unavailable, "not ready: "+strings.Join(failed, "; "))
+	}
+
 	return &desc.HealthCheckResponse{
 		Message: "OK",
 	}, nil
-
 }
@@ -29,6 +29,8 @@ func (hdl *Implementation) OrderCreate(
 	if err != nil {
 		if errors.Is(err, domain.ErrNotEnoughStock) || errors.Is(err, domain.ErrStockNotFound) {
 			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		} else if errors.Is(err, domain.ErrOrderCurrency) || errors.Is(err, domain.ErrInvalidOrderItems) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
 		}
 
 		return nil, status.Error(codes.Internal, domain.ErrInternalServerError.Error())
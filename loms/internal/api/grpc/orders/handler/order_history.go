@@ -0,0 +1,41 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"route256/loms/internal/domain"
+	desc "route256/loms/internal/pb/loms/v1"
+
+	"github.com/opentracing/opentracing-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (hdl *Implementation) OrderHistory(
+	ctx context.Context, req *desc.OrderHistoryRequest) (
+	*desc.OrderHistoryResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.OrderHistory")
+	defer span.Finish()
+
+	history, err := hdl.orderService.OrderHistory(ctx, req.GetOrderId())
+	if err != nil {
+		if errors.Is(err, domain.ErrOrderNotFound) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+
+		return nil, status.Error(codes.Internal, domain.ErrInternalServerError.Error())
+	}
+
+	changes := make([]*desc.OrderStatusChange, len(history))
+	for idx, change := range history {
+		changes[idx] = &desc.OrderStatusChange{
+			Status:    string(change.Status),
+			ChangedAt: timestamppb.New(change.ChangedAt),
+		}
+	}
+
+	return &desc.OrderHistoryResponse{
+		History: changes,
+	}, nil
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/opentracing/opentracing-go"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func (hdl *Implementation) OrderInfo(
@@ -32,9 +33,19 @@ func (hdl *Implementation) OrderInfo(
 		return nil, status.Error(codes.Internal, domain.ErrInternalServerError.Error())
 	}
 
+	mapAllocations, err := utils.AllocationsDomainToMap(order.Allocations)
+	if err != nil {
+		return nil, status.Error(codes.Internal, domain.ErrInternalServerError.Error())
+	}
+
 	return &desc.OrderInfoResponse{
-		Status: string(order.Status),
-		UserId: order.UserID,
-		Items:  mapItems,
+		Status:      string(order.Status),
+		UserId:      order.UserID,
+		Items:       mapItems,
+		Allocations: mapAllocations,
+		CreatedAt:   timestamppb.New(order.CreatedAt),
+		UpdatedAt:   timestamppb.New(order.UpdatedAt),
+		TotalPrice:  order.TotalPrice,
+		Currency:    order.Currency,
 	}, nil
 }
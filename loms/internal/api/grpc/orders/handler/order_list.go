@@ -0,0 +1,70 @@
+package api
+
+import (
+	"context"
+	"route256/loms/internal/api/grpc/orders/handler/utils"
+	"route256/loms/internal/domain"
+	desc "route256/loms/internal/pb/loms/v1"
+
+	"github.com/opentracing/opentracing-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (hdl *Implementation) OrderList(
+	ctx context.Context, req *desc.OrderListRequest) (
+	*desc.OrderListResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.OrderList")
+	defer span.Finish()
+
+	orders, nextCursor, err := hdl.orderService.OrderList(ctx, mapOrderListRequestToDomain(req))
+	if err != nil {
+		return nil, status.Error(codes.Internal, domain.ErrInternalServerError.Error())
+	}
+
+	summaries := make([]*desc.OrderSummary, len(orders))
+	for idx, order := range orders {
+		mapItems, err := utils.ItemsDomainToMap(order.Items)
+		if err != nil {
+			return nil, status.Error(codes.Internal, domain.ErrInternalServerError.Error())
+		}
+
+		summaries[idx] = &desc.OrderSummary{
+			OrderId:   order.ID,
+			Status:    string(order.Status),
+			CreatedAt: timestamppb.New(order.CreatedAt),
+			Items:     mapItems,
+		}
+	}
+
+	return &desc.OrderListResponse{
+		Orders:     summaries,
+		NextCursor: nextCursor,
+	}, nil
+}
+
+func mapOrderListRequestToDomain(req *desc.OrderListRequest) domain.OrderListFilter {
+	filter := domain.OrderListFilter{
+		UserID:   req.GetUserId(),
+		Statuses: make([]domain.OrderStatus, len(req.GetStatuses())),
+		Cursor:   req.GetCursor(),
+		Limit:    int32(req.GetLimit()), // #nosec G115
+	}
+
+	for idx, value := range req.GetStatuses() {
+		filter.Statuses[idx] = domain.OrderStatus(value)
+	}
+
+	if req.GetCreatedFrom() != nil {
+		createdFrom := req.GetCreatedFrom().AsTime()
+		filter.CreatedFrom = &createdFrom
+	}
+
+	if req.GetCreatedTo() != nil {
+		createdTo := req.GetCreatedTo().AsTime()
+		filter.CreatedTo = &createdTo
+	}
+
+	return filter
+}
@@ -20,12 +20,21 @@ func (hdl *Implementation) OrderPay(
 	if err != nil {
 		if errors.Is(err, domain.ErrOrderNotFound) {
 			return nil, status.Error(codes.NotFound, err.Error())
-		} else if errors.Is(err, domain.ErrPayStatusOrder) {
+		} else if errors.Is(err, domain.ErrPaymentPending) {
+			return &desc.OrderPayResponse{
+				PaymentStatus: string(domain.PaymentStatusPending),
+			}, nil
+		} else if errors.Is(err, domain.ErrPayStatusOrder) || errors.Is(err, domain.ErrPaymentDeclined) ||
+			errors.Is(err, domain.ErrPaymentAmountMismatch) {
 			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		} else if errors.Is(err, domain.ErrPaymentUnavailable) {
+			return nil, status.Error(codes.Unavailable, domain.ErrPaymentUnavailable.Error())
 		}
 
 		return nil, status.Error(codes.Internal, domain.ErrInternalServerError.Error())
 	}
 
-	return &desc.OrderPayResponse{}, nil
+	return &desc.OrderPayResponse{
+		PaymentStatus: string(domain.PaymentStatusCaptured),
+	}, nil
 }
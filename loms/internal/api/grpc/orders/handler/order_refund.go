@@ -0,0 +1,32 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"route256/loms/internal/domain"
+	desc "route256/loms/internal/pb/loms/v1"
+
+	"github.com/opentracing/opentracing-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (hdl *Implementation) OrderRefund(ctx context.Context, req *desc.OrderRefundRequest) (*desc.OrderRefundResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.OrderRefund")
+	defer span.Finish()
+
+	err := hdl.orderService.OrderRefund(ctx, req.GetOrderId(), req.GetRestock())
+	if err != nil {
+		if errors.Is(err, domain.ErrOrderNotFound) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		} else if errors.Is(err, domain.ErrRefundStatusOrder) || errors.Is(err, domain.ErrPaymentAmountMismatch) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		} else if errors.Is(err, domain.ErrPaymentUnavailable) {
+			return nil, status.Error(codes.Unavailable, domain.ErrPaymentUnavailable.Error())
+		}
+
+		return nil, status.Error(codes.Internal, domain.ErrInternalServerError.Error())
+	}
+
+	return &desc.OrderRefundResponse{}, nil
+}
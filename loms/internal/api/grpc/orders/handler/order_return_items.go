@@ -0,0 +1,41 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"route256/loms/internal/api/grpc/orders/handler/utils"
+	"route256/loms/internal/domain"
+	desc "route256/loms/internal/pb/loms/v1"
+
+	"github.com/opentracing/opentracing-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (hdl *Implementation) OrderReturnItems(
+	ctx context.Context, req *desc.OrderReturnItemsRequest) (
+	*desc.OrderReturnItemsResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.OrderReturnItems")
+	defer span.Finish()
+
+	items := utils.MapItemsToDomain(req.GetItems())
+
+	if err := validateUniqueSkus(items); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	err := hdl.orderService.OrderReturnItems(ctx, req.GetOrderId(), items)
+	if err != nil {
+		if errors.Is(err, domain.ErrOrderNotFound) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		} else if errors.Is(err, domain.ErrInvalidOrderItems) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		} else if errors.Is(err, domain.ErrReturnItemsStatus) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+
+		return nil, status.Error(codes.Internal, domain.ErrInternalServerError.Error())
+	}
+
+	return &desc.OrderReturnItemsResponse{}, nil
+}
@@ -2,8 +2,10 @@ package api
 
 import (
 	"context"
+	"route256/loms/internal/business/service/order/watch"
 	"route256/loms/internal/domain"
 	desc "route256/loms/internal/pb/loms/v1"
+	"time"
 )
 
 type orderService interface {
@@ -11,23 +13,42 @@ type orderService interface {
 	OrderInfo(ctx context.Context, orderID int64) (domain.Order, error)
 	OrderPay(ctx context.Context, orderID int64) error
 	OrderCancel(ctx context.Context, orderID int64) error
+	OrderRefund(ctx context.Context, orderID int64, restock bool) error
+	OrderCancelItems(ctx context.Context, orderID int64, items []domain.Item) error
+	OrderReturnItems(ctx context.Context, orderID int64, items []domain.Item) error
+	OrderList(ctx context.Context, filter domain.OrderListFilter) ([]domain.Order, int64, error)
+	OrderHistory(ctx context.Context, orderID int64) ([]domain.OrderStatusChange, error)
+	WatchOrder(ctx context.Context, orderID int64) (domain.OrderStatusUpdate, *watch.Subscription, error)
 }
 
 type stockService interface {
-	StocksInfo(ctx context.Context, sku domain.Sku) (int64, error)
+	StocksInfo(ctx context.Context, sku domain.Sku) (int64, []domain.WarehouseStock, error)
+}
+
+type healthChecker interface {
+	Ready() (bool, map[string]error)
 }
 
 type Implementation struct {
 	desc.UnimplementedOrdersServer
 	desc.UnimplementedStocksServer
 	desc.UnimplementedHealthServer
-	orderService orderService
-	stockService stockService
+	orderService   orderService
+	stockService   stockService
+	healthChecker  healthChecker
+	watchHeartbeat time.Duration
 }
 
-func NewImplementation(orderService orderService, stockService stockService) *Implementation {
+func NewImplementation(
+	orderService orderService,
+	stockService stockService,
+	healthChecker healthChecker,
+	watchHeartbeat time.Duration,
+) *Implementation {
 	return &Implementation{
-		orderService: orderService,
-		stockService: stockService,
+		orderService:   orderService,
+		stockService:   stockService,
+		healthChecker:  healthChecker,
+		watchHeartbeat: watchHeartbeat,
 	}
 }
@@ -18,7 +18,7 @@ func (hdl *Implementation) StocksInfo(
 	span, ctx := opentracing.StartSpanFromContext(ctx, "api.StocksInfo")
 	defer span.Finish()
 
-	count, err := hdl.stockService.StocksInfo(ctx, domain.Sku(req.GetSku()))
+	count, stocks, err := hdl.stockService.StocksInfo(ctx, domain.Sku(req.GetSku()))
 	if err != nil {
 		if errors.Is(err, domain.ErrStockNotFound) {
 			return nil, status.Error(codes.NotFound, err.Error())
@@ -32,7 +32,34 @@ func (hdl *Implementation) StocksInfo(
 		return nil, status.Error(codes.Internal, domain.ErrInternalServerError.Error())
 	}
 
-	return &desc.StocksInfoResponse{
+	resp := &desc.StocksInfoResponse{
 		Count: convCount,
-	}, nil
+	}
+
+	if req.GetWithWarehouses() {
+		resp.Warehouses, err = mapWarehouseStocks(stocks)
+		if err != nil {
+			return nil, status.Error(codes.Internal, domain.ErrInternalServerError.Error())
+		}
+	}
+
+	return resp, nil
+}
+
+func mapWarehouseStocks(stocks []domain.WarehouseStock) ([]*desc.WarehouseStockInfo, error) {
+	result := make([]*desc.WarehouseStockInfo, 0, len(stocks))
+
+	for _, stock := range stocks {
+		count, err := converter.SafeInt64ToUint32(max(stock.TotalCount-stock.Reserved, 0))
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, &desc.WarehouseStockInfo{
+			WarehouseId: stock.WarehouseID,
+			Count:       count,
+		})
+	}
+
+	return result, nil
 }
@@ -11,8 +11,10 @@ func MapItemsToDomain(items []*desc.Item) []domain.Item {
 
 	for idx, value := range items {
 		result[idx] = domain.Item{
-			Sku:   domain.Sku(value.Sku),
-			Count: int64(value.Count),
+			Sku:      domain.Sku(value.Sku),
+			Count:    int64(value.Count),
+			Price:    int64(value.Price),
+			Currency: value.Currency,
 		}
 	}
 
@@ -28,9 +30,35 @@ func ItemsDomainToMap(items []domain.Item) ([]*desc.Item, error) {
 			return nil, err
 		}
 
+		checkedPrice, err := converter.SafeInt64ToUint32(value.Price)
+		if err != nil {
+			return nil, err
+		}
+
 		result[idx] = &desc.Item{
-			Sku:   int64(value.Sku),
-			Count: checkedVal,
+			Sku:      int64(value.Sku),
+			Count:    checkedVal,
+			Price:    checkedPrice,
+			Currency: value.Currency,
+		}
+	}
+
+	return result, nil
+}
+
+func AllocationsDomainToMap(allocations []domain.Allocation) ([]*desc.ItemAllocation, error) {
+	result := make([]*desc.ItemAllocation, len(allocations))
+
+	for idx, value := range allocations {
+		checkedVal, err := converter.SafeInt64ToUint32(value.Count)
+		if err != nil {
+			return nil, err
+		}
+
+		result[idx] = &desc.ItemAllocation{
+			Sku:         int64(value.Sku),
+			WarehouseId: value.WarehouseID,
+			Count:       checkedVal,
 		}
 	}
 
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"errors"
+	"route256/loms/internal/business/service/order/watch"
+	"route256/loms/internal/domain"
+	desc "route256/loms/internal/pb/loms/v1"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (hdl *Implementation) WatchOrder(req *desc.WatchOrderRequest, stream desc.Orders_WatchOrderServer) error {
+	span, ctx := opentracing.StartSpanFromContext(stream.Context(), "api.WatchOrder")
+	defer span.Finish()
+
+	current, sub, err := hdl.orderService.WatchOrder(ctx, req.GetOrderId())
+	if err != nil {
+		if errors.Is(err, domain.ErrOrderNotFound) {
+			return status.Error(codes.NotFound, err.Error())
+		}
+
+		return status.Error(codes.Internal, domain.ErrInternalServerError.Error())
+	}
+	defer sub.Close()
+
+	if err := stream.Send(watchOrderResponse(current)); err != nil {
+		return err
+	}
+
+	last := current.Status
+
+	heartbeat := time.NewTicker(hdl.watchHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-sub.Done():
+			return watchError(sub.Err())
+		case <-heartbeat.C:
+			if err := stream.Send(&desc.WatchOrderResponse{
+				OrderId:   current.OrderID,
+				Status:    string(last),
+				Heartbeat: true,
+			}); err != nil {
+				return err
+			}
+		case update := <-sub.Updates():
+			if update.Status == last {
+				continue
+			}
+
+			last = update.Status
+
+			if err := stream.Send(watchOrderResponse(update)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func watchOrderResponse(update domain.OrderStatusUpdate) *desc.WatchOrderResponse {
+	return &desc.WatchOrderResponse{
+		OrderId:   update.OrderID,
+		Status:    string(update.Status),
+		ChangedAt: timestamppb.New(update.ChangedAt),
+	}
+}
+
+func watchError(err error) error {
+	switch {
+	case errors.Is(err, watch.ErrSlowConsumer):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, watch.ErrClosed):
+		return status.Error(codes.Unavailable, err.Error())
+	default:
+		return nil
+	}
+}
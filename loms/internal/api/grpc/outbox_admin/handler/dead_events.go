@@ -0,0 +1,45 @@
+package api
+
+import (
+	"context"
+	"route256/loms/internal/domain"
+	desc "route256/loms/internal/pb/loms/v1"
+
+	"github.com/opentracing/opentracing-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (hdl *Implementation) DeadEvents(
+	ctx context.Context, req *desc.DeadEventsRequest) (
+	*desc.DeadEventsResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.DeadEvents")
+	defer span.Finish()
+
+	events, nextCursor, err := hdl.outboxAdminService.DeadEvents(ctx, domain.DeadEventsFilter{
+		Cursor: req.GetCursor(),
+		Limit:  int32(req.GetLimit()), // #nosec G115
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, domain.ErrInternalServerError.Error())
+	}
+
+	result := make([]*desc.OutboxEvent, len(events))
+	for idx, event := range events {
+		result[idx] = &desc.OutboxEvent{
+			Id:            event.ID,
+			Topic:         event.Topic,
+			Key:           event.Key,
+			Payload:       event.Payload,
+			Attempts:      event.Attempts,
+			CreatedAt:     timestamppb.New(event.CreatedAt),
+			NextAttemptAt: timestamppb.New(event.NextAttemptAt),
+		}
+	}
+
+	return &desc.DeadEventsResponse{
+		Events:     result,
+		NextCursor: nextCursor,
+	}, nil
+}
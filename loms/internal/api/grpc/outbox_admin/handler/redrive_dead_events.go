@@ -0,0 +1,27 @@
+package api
+
+import (
+	"context"
+	"route256/loms/internal/domain"
+	desc "route256/loms/internal/pb/loms/v1"
+
+	"github.com/opentracing/opentracing-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (hdl *Implementation) RedriveDeadEvents(
+	ctx context.Context, req *desc.RedriveDeadEventsRequest) (
+	*desc.RedriveDeadEventsResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.RedriveDeadEvents")
+	defer span.Finish()
+
+	redrivenIDs, err := hdl.outboxAdminService.RedriveDeadEvents(ctx, req.GetIds())
+	if err != nil {
+		return nil, status.Error(codes.Internal, domain.ErrInternalServerError.Error())
+	}
+
+	return &desc.RedriveDeadEventsResponse{
+		RedrivenIds: redrivenIDs,
+	}, nil
+}
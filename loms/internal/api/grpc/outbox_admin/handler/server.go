@@ -0,0 +1,23 @@
+package api
+
+import (
+	"context"
+	"route256/loms/internal/domain"
+	desc "route256/loms/internal/pb/loms/v1"
+)
+
+type outboxAdminService interface {
+	DeadEvents(ctx context.Context, filter domain.DeadEventsFilter) ([]domain.Event, int64, error)
+	RedriveDeadEvents(ctx context.Context, ids []int64) ([]int64, error)
+}
+
+type Implementation struct {
+	desc.UnimplementedOutboxAdminServer
+	outboxAdminService outboxAdminService
+}
+
+func NewImplementation(outboxAdminService outboxAdminService) *Implementation {
+	return &Implementation{
+		outboxAdminService: outboxAdminService,
+	}
+}
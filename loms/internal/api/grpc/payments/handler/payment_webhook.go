@@ -0,0 +1,40 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"route256/loms/internal/domain"
+	desc "route256/loms/internal/pb/loms/v1"
+
+	"github.com/opentracing/opentracing-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (hdl *Implementation) PaymentWebhook(
+	ctx context.Context, req *desc.PaymentWebhookRequest) (
+	*desc.PaymentWebhookResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.PaymentWebhook")
+	defer span.Finish()
+
+	err := hdl.paymentService.ConfirmPayment(ctx, req.GetOrderId(), domain.PaymentResult{
+		ProviderPaymentID: req.GetProviderPaymentId(),
+		Status:            domain.PaymentStatus(req.GetStatus()),
+		Reason:            req.GetReason(),
+		Amount:            req.GetAmount(),
+		Currency:          req.GetCurrency(),
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrOrderNotFound) || errors.Is(err, domain.ErrPaymentNotFound) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		} else if errors.Is(err, domain.ErrPayStatusOrder) || errors.Is(err, domain.ErrPaymentAmountMismatch) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		} else if errors.Is(err, domain.ErrPaymentUnavailable) {
+			return nil, status.Error(codes.Unavailable, domain.ErrPaymentUnavailable.Error())
+		}
+
+		return nil, status.Error(codes.Internal, domain.ErrInternalServerError.Error())
+	}
+
+	return &desc.PaymentWebhookResponse{}, nil
+}
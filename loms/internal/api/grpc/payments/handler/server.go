@@ -0,0 +1,22 @@
+package api
+
+import (
+	"context"
+	"route256/loms/internal/domain"
+	desc "route256/loms/internal/pb/loms/v1"
+)
+
+type paymentService interface {
+	ConfirmPayment(ctx context.Context, orderID int64, result domain.PaymentResult) error
+}
+
+type Implementation struct {
+	desc.UnimplementedPaymentsServer
+	paymentService paymentService
+}
+
+func NewImplementation(paymentService paymentService) *Implementation {
+	return &Implementation{
+		paymentService: paymentService,
+	}
+}
@@ -0,0 +1,31 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"route256/loms/internal/domain"
+	desc "route256/loms/internal/pb/loms/v1"
+
+	"github.com/opentracing/opentracing-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (hdl *Implementation) Adjust(ctx context.Context, req *desc.AdjustRequest) (*desc.StockAdminResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.Adjust")
+	defer span.Finish()
+
+	stock, err := hdl.stockAdminService.Adjust(ctx,
+		warehouseIDOrDefault(req.GetWarehouseId()), domain.Sku(req.GetSku()), req.GetTotalCount(), req.GetReason())
+	if err != nil {
+		if errors.Is(err, domain.ErrStockNotFound) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		} else if errors.Is(err, domain.ErrInvalidStockCount) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+
+		return nil, status.Error(codes.Internal, domain.ErrInternalServerError.Error())
+	}
+
+	return mapStockToResponse(stock), nil
+}
@@ -0,0 +1,29 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"route256/loms/internal/domain"
+	desc "route256/loms/internal/pb/loms/v1"
+
+	"github.com/opentracing/opentracing-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (hdl *Implementation) CreateSku(ctx context.Context, req *desc.CreateSkuRequest) (*desc.CreateSkuResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.CreateSku")
+	defer span.Finish()
+
+	err := hdl.stockAdminService.CreateSku(ctx,
+		warehouseIDOrDefault(req.GetWarehouseId()), domain.Sku(req.GetSku()), req.GetTotalCount())
+	if err != nil {
+		if errors.Is(err, domain.ErrStockAlreadyExists) {
+			return nil, status.Error(codes.AlreadyExists, err.Error())
+		}
+
+		return nil, status.Error(codes.Internal, domain.ErrInternalServerError.Error())
+	}
+
+	return &desc.CreateSkuResponse{}, nil
+}
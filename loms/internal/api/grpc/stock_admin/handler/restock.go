@@ -0,0 +1,29 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"route256/loms/internal/domain"
+	desc "route256/loms/internal/pb/loms/v1"
+
+	"github.com/opentracing/opentracing-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (hdl *Implementation) Restock(ctx context.Context, req *desc.RestockRequest) (*desc.StockAdminResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.Restock")
+	defer span.Finish()
+
+	stock, err := hdl.stockAdminService.Restock(ctx,
+		warehouseIDOrDefault(req.GetWarehouseId()), domain.Sku(req.GetSku()), req.GetCount())
+	if err != nil {
+		if errors.Is(err, domain.ErrStockNotFound) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+
+		return nil, status.Error(codes.Internal, domain.ErrInternalServerError.Error())
+	}
+
+	return mapStockToResponse(stock), nil
+}
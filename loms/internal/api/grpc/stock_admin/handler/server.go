@@ -0,0 +1,47 @@
+package api
+
+import (
+	"context"
+	"route256/loms/internal/domain"
+	desc "route256/loms/internal/pb/loms/v1"
+)
+
+type stockAdminService interface {
+	Restock(ctx context.Context, warehouseID int64, sku domain.Sku, count int64) (domain.WarehouseStock, error)
+	Adjust(
+		ctx context.Context,
+		warehouseID int64,
+		sku domain.Sku,
+		totalCount int64,
+		reason string) (domain.WarehouseStock, error)
+	CreateSku(ctx context.Context, warehouseID int64, sku domain.Sku, totalCount int64) error
+	StockHistory(ctx context.Context, filter domain.StockHistoryFilter) ([]domain.StockMovement, int64, error)
+}
+
+type Implementation struct {
+	desc.UnimplementedStockAdminServer
+	stockAdminService stockAdminService
+}
+
+func NewImplementation(stockAdminService stockAdminService) *Implementation {
+	return &Implementation{
+		stockAdminService: stockAdminService,
+	}
+}
+
+func mapStockToResponse(stock domain.WarehouseStock) *desc.StockAdminResponse {
+	return &desc.StockAdminResponse{
+		Sku:         int64(stock.Sku),
+		TotalCount:  stock.TotalCount,
+		Reserved:    stock.Reserved,
+		WarehouseId: stock.WarehouseID,
+	}
+}
+
+func warehouseIDOrDefault(warehouseID int64) int64 {
+	if warehouseID == 0 {
+		return domain.DefaultWarehouseID
+	}
+
+	return warehouseID
+}
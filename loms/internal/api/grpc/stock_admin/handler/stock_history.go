@@ -0,0 +1,51 @@
+package api
+
+import (
+	"context"
+	"route256/loms/internal/domain"
+	desc "route256/loms/internal/pb/loms/v1"
+
+	"github.com/opentracing/opentracing-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (hdl *Implementation) StockHistory(
+	ctx context.Context, req *desc.StockHistoryRequest) (
+	*desc.StockHistoryResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "api.StockHistory")
+	defer span.Finish()
+
+	movements, nextCursor, err := hdl.stockAdminService.StockHistory(ctx, domain.StockHistoryFilter{
+		Sku:         domain.Sku(req.GetSku()),
+		WarehouseID: req.GetWarehouseId(),
+		Cursor:      req.GetCursor(),
+		Limit:       int32(req.GetLimit()), // #nosec G115
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, domain.ErrInternalServerError.Error())
+	}
+
+	result := make([]*desc.StockMovement, len(movements))
+	for idx, movement := range movements {
+		result[idx] = &desc.StockMovement{
+			Id:            movement.ID,
+			WarehouseId:   movement.WarehouseID,
+			Sku:           int64(movement.Sku),
+			Kind:          string(movement.Kind),
+			OrderId:       movement.OrderID,
+			Reason:        movement.Reason,
+			TotalDelta:    movement.TotalDelta,
+			ReservedDelta: movement.ReservedDelta,
+			TotalCount:    movement.TotalCount,
+			Reserved:      movement.Reserved,
+			CreatedAt:     timestamppb.New(movement.CreatedAt),
+		}
+	}
+
+	return &desc.StockHistoryResponse{
+		Movements:  result,
+		NextCursor: nextCursor,
+	}, nil
+}
@@ -9,10 +9,16 @@ import (
 	"net/url"
 	"os"
 	"os/signal"
+	"route256/loms/internal/adapter/payment/fake"
+	"route256/loms/internal/business/service/order/watch"
 	"route256/loms/internal/infra/closer"
 	config "route256/loms/internal/infra/config"
+	daemon "route256/loms/internal/infra/daemon"
+	"route256/loms/internal/infra/leader"
 	logger "route256/loms/internal/infra/logger"
 	"route256/loms/internal/infra/metrics"
+	pgpool "route256/loms/internal/infra/postgres"
+	"route256/loms/internal/infra/sharding"
 	serve "route256/loms/internal/infra/swagger"
 	"route256/loms/internal/infra/tracing"
 	"route256/loms/internal/middleware"
@@ -27,6 +33,7 @@ import (
 	"go.uber.org/zap/zapcore"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 )
 
@@ -36,8 +43,9 @@ type App struct {
 	grpcAddress    string
 	swaggerAddress string
 
-	masterDSN  string
-	replicaDSN string
+	masterDSN   string
+	replicaDSNs []string
+	shardDSNs   []shardDSN
 
 	serviceProvider *serviceProvider
 	grpcServer      *grpc.Server
@@ -56,7 +64,8 @@ func NewApp(ctx context.Context, configPath string) (*App, error) {
 		httpAddress:    fmt.Sprintf("%v:%v", c.Service.Host, c.Service.HTTPPort),
 		swaggerAddress: fmt.Sprintf("%v:%v", c.Service.Host, c.Service.SwaggerPort),
 		masterDSN:      buildPGDSN(c.MasterDB),
-		replicaDSN:     buildPGDSN(c.ReplicDB),
+		replicaDSNs:    buildReplicaDSNs(c),
+		shardDSNs:      buildShardDSNs(c),
 	}
 
 	err = app.initDeps(ctx)
@@ -76,6 +85,18 @@ func (app *App) Run(ctx context.Context) error {
 	ctx, cancel := context.WithCancel(ctx)
 	wg := &sync.WaitGroup{}
 
+	leaderCtx, resign := context.WithCancel(context.WithoutCancel(ctx))
+	leaderWg := &sync.WaitGroup{}
+
+	// The elector and daemons are built before any goroutine starts: the provider
+	// getters are not synchronized and the daemons must share the running elector.
+	var elector *leader.Elector
+	if app.serviceProvider.LeaderElection() {
+		elector = app.serviceProvider.LeaderElector(leaderCtx)
+	}
+
+	jobs := app.shardJobs(ctx)
+
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
@@ -107,16 +128,123 @@ func (app *App) Run(ctx context.Context) error {
 	go func() {
 		defer wg.Done()
 
-		daemon := app.serviceProvider.Daemon(ctx)
-		daemon.Start(ctx)
-		<-ctx.Done()
+		app.serviceProvider.HealthChecker(ctx).Run(ctx, app.serviceProvider.HealthCheckPeriod())
 	}()
 
+	if elector != nil {
+		leaderWg.Add(1)
+		go func() {
+			defer leaderWg.Done()
+
+			elector.Run(leaderCtx)
+		}()
+	}
+
+	if !app.serviceProvider.InMemory() {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			app.serviceProvider.PostgresPools(ctx).RunHealthCheck(ctx, app.replicaCheckPeriod())
+		}()
+	}
+
+	if app.serviceProvider.Sharded() {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			app.serviceProvider.OrderShards(ctx).RunHealthCheck(ctx, app.replicaCheckPeriod())
+		}()
+	}
+
+	for shard, shardJobs := range jobs {
+		app.runShardDaemons(sharding.WithShard(ctx, shard), wg, shardJobs)
+	}
+
 	gracefulShutdown(ctx, cancel, wg)
 
+	resign()
+	leaderWg.Wait()
+
 	return nil
 }
 
+type shardJobs struct {
+	listener  *pgpool.Listener
+	relay     *daemon.Daemon
+	unpaid    *daemon.Daemon
+	retention *daemon.Daemon
+}
+
+func (app *App) shardJobs(ctx context.Context) []shardJobs {
+	var listeners []*pgpool.Listener
+	if !app.serviceProvider.InMemory() {
+		listeners = app.serviceProvider.OutboxListeners(ctx)
+	}
+
+	relays := app.serviceProvider.Daemons(ctx)
+	unpaid := app.serviceProvider.UnpaidOrderDaemons(ctx)
+
+	var retention []*daemon.Daemon
+	if app.config.Service.OutboxRetention > 0 {
+		retention = app.serviceProvider.OutboxRetentionDaemons(ctx)
+	}
+
+	jobs := make([]shardJobs, app.serviceProvider.ShardCount(ctx))
+	for shard := range jobs {
+		if listeners != nil {
+			jobs[shard].listener = listeners[shard]
+		}
+
+		jobs[shard].relay = relays[shard]
+		jobs[shard].unpaid = unpaid[shard]
+
+		if retention != nil {
+			jobs[shard].retention = retention[shard]
+		}
+	}
+
+	return jobs
+}
+
+func (app *App) runShardDaemons(ctx context.Context, wg *sync.WaitGroup, jobs shardJobs) {
+	if jobs.listener != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			jobs.listener.Run(ctx)
+		}()
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		jobs.relay.Start(ctx)
+		<-ctx.Done()
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		jobs.unpaid.Start(ctx)
+		<-ctx.Done()
+	}()
+
+	if jobs.retention != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			jobs.retention.Start(ctx)
+			<-ctx.Done()
+		}()
+	}
+}
+
 func (app *App) initDeps(ctx context.Context) error {
 	inits := []func(ctx context.Context) error{
 		app.initMetrics,
@@ -138,10 +266,35 @@ func (app *App) initDeps(ctx context.Context) error {
 }
 
 func (app *App) initServiceProvider(_ context.Context) error {
+	switch app.config.Service.Storage {
+	case storagePostgres, storageInMemory, "":
+	default:
+		return fmt.Errorf("unknown storage %q", app.config.Service.Storage)
+	}
+
+	switch watch.SlowConsumerPolicy(app.config.Watch.SlowConsumerPolicy) {
+	case watch.SlowConsumerDisconnect, watch.SlowConsumerDropOldest, "":
+	default:
+		return fmt.Errorf("unknown slow consumer policy %q", app.config.Watch.SlowConsumerPolicy)
+	}
+
+	switch app.config.Payment.Provider {
+	case fake.Name, "":
+	default:
+		return fmt.Errorf("unknown payment provider %q", app.config.Payment.Provider)
+	}
+
+	switch fake.Mode(app.config.Payment.FakeMode) {
+	case fake.ModeApprove, fake.ModeDecline, fake.ModeTimeout, fake.ModePending, "":
+	default:
+		return fmt.Errorf("unknown fake payment mode %q", app.config.Payment.FakeMode)
+	}
+
 	app.serviceProvider = newServiceProvider()
 	app.serviceProvider.config = *app.config
 	app.serviceProvider.masterDSN = app.masterDSN
-	app.serviceProvider.replicaDSN = app.replicaDSN
+	app.serviceProvider.replicaDSNs = app.replicaDSNs
+	app.serviceProvider.shardDSNs = app.shardDSNs
 	return nil
 }
 
@@ -192,13 +345,26 @@ func (app *App) initTracing(_ context.Context) error {
 }
 
 func (app *App) initGRPCServer(ctx context.Context) error {
+	interceptors := []grpc.UnaryServerInterceptor{
+		middleware.ServerTracingInterceptor,
+		middleware.MetricsInterceptor,
+		middleware.AdminAuth(app.config.Service.AdminToken),
+		middleware.WebhookAuth(app.config.Payment.WebhookSecret),
+		middleware.Validate,
+	}
+
+	if !app.serviceProvider.InMemory() {
+		interceptors = append(interceptors, middleware.ReadYourWrites(
+			app.serviceProvider.OrderShards(ctx),
+			app.config.ReadConsistency.WriteMethods,
+			app.config.ReadConsistency.ReadMethods,
+		))
+	}
+
 	app.grpcServer = grpc.NewServer(
 		grpc.Creds(insecure.NewCredentials()),
-		grpc.ChainUnaryInterceptor(
-			middleware.ServerTracingInterceptor,
-			middleware.MetricsInterceptor,
-			middleware.Validate,
-		),
+		grpc.ChainUnaryInterceptor(interceptors...),
+		grpc.ChainStreamInterceptor(middleware.ValidateStream),
 	)
 
 	reflection.Register(app.grpcServer)
@@ -206,6 +372,10 @@ func (app *App) initGRPCServer(ctx context.Context) error {
 	desc.RegisterOrdersServer(app.grpcServer, app.serviceProvider.AppHandler(ctx))
 	desc.RegisterStocksServer(app.grpcServer, app.serviceProvider.AppHandler(ctx))
 	desc.RegisterHealthServer(app.grpcServer, app.serviceProvider.AppHandler(ctx))
+	desc.RegisterStockAdminServer(app.grpcServer, app.serviceProvider.StockAdminHandler(ctx))
+	desc.RegisterOutboxAdminServer(app.grpcServer, app.serviceProvider.OutboxAdminHandler(ctx))
+	desc.RegisterPaymentsServer(app.grpcServer, app.serviceProvider.PaymentsHandler(ctx))
+	healthpb.RegisterHealthServer(app.grpcServer, app.serviceProvider.HealthChecker(ctx).Server())
 
 	return nil
 }
@@ -245,6 +415,7 @@ func (app *App) runGRPCServer(ctx context.Context) error {
 	select {
 	case <-ctx.Done():
 		logger.Infof(ctx, "GRPC server shutdown initiated")
+		app.serviceProvider.StatusBroadcaster().Close()
 		app.grpcServer.GracefulStop()
 		return nil
 	case err := <-errCh:
@@ -253,7 +424,10 @@ func (app *App) runGRPCServer(ctx context.Context) error {
 }
 
 func (app *App) runHTTPServer(ctx context.Context) error {
-	grpcMux := runtime.NewServeMux()
+	grpcMux := runtime.NewServeMux(
+		runtime.WithIncomingHeaderMatcher(middleware.GatewayHeaderMatcher),
+		runtime.WithMarshalerOption(middleware.EventStreamContentType, &middleware.EventStreamMarshaler{}),
+	)
 
 	if err := desc.RegisterOrdersHandlerFromEndpoint(ctx, grpcMux, app.grpcAddress, []grpc.DialOption{
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
@@ -267,6 +441,24 @@ func (app *App) runHTTPServer(ctx context.Context) error {
 		return fmt.Errorf("failed to register stocks gateway: %w", err)
 	}
 
+	if err := desc.RegisterStockAdminHandlerFromEndpoint(ctx, grpcMux, app.grpcAddress, []grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}); err != nil {
+		return fmt.Errorf("failed to register stock admin gateway: %w", err)
+	}
+
+	if err := desc.RegisterOutboxAdminHandlerFromEndpoint(ctx, grpcMux, app.grpcAddress, []grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}); err != nil {
+		return fmt.Errorf("failed to register outbox admin gateway: %w", err)
+	}
+
+	if err := desc.RegisterPaymentsHandlerFromEndpoint(ctx, grpcMux, app.grpcAddress, []grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}); err != nil {
+		return fmt.Errorf("failed to register payments gateway: %w", err)
+	}
+
 	if err := desc.RegisterHealthHandlerFromEndpoint(ctx, grpcMux, app.grpcAddress, []grpc.DialOption{
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
 	}); err != nil {
@@ -275,6 +467,8 @@ func (app *App) runHTTPServer(ctx context.Context) error {
 
 	httpMux := http.NewServeMux()
 	httpMux.Handle("/metrics", promhttp.Handler())
+	httpMux.Handle("/livez", app.serviceProvider.HealthChecker(ctx).LivenessHandler())
+	httpMux.Handle("/readyz", app.serviceProvider.HealthChecker(ctx).ReadinessHandler())
 	httpMux.Handle("/", middleware.HTTPMetrics(grpcMux))
 
 	httpMux.HandleFunc("/debug/pprof/", pprof.Index)
@@ -339,6 +533,44 @@ func buildPGDSN(cfg config.DBConfig) string {
 	)
 }
 
+func buildReplicaDSNs(c *config.Config) []string {
+	if len(c.Replicas) == 0 {
+		return []string{buildPGDSN(c.ReplicDB)}
+	}
+
+	dsns := make([]string, 0, len(c.Replicas))
+	for _, replica := range c.Replicas {
+		dsns = append(dsns, buildPGDSN(replica))
+	}
+
+	return dsns
+}
+
+func buildShardDSNs(c *config.Config) []shardDSN {
+	shards := make([]shardDSN, 0, len(c.Shards))
+	for _, shard := range c.Shards {
+		replicas := make([]string, 0, len(shard.Replicas))
+		for _, replica := range shard.Replicas {
+			replicas = append(replicas, buildPGDSN(replica))
+		}
+
+		shards = append(shards, shardDSN{
+			master:   buildPGDSN(shard.Master),
+			replicas: replicas,
+		})
+	}
+
+	return shards
+}
+
+func (app *App) replicaCheckPeriod() time.Duration {
+	if app.config.ReadConsistency.ReplicaCheckPeriodMs <= 0 {
+		return time.Second
+	}
+
+	return time.Duration(app.config.ReadConsistency.ReplicaCheckPeriodMs) * time.Millisecond
+}
+
 func gracefulShutdown(ctx context.Context, cancel context.CancelFunc, wg *sync.WaitGroup) {
 	sigterm := make(chan os.Signal, 1)
 	signal.Notify(sigterm, syscall.SIGINT, syscall.SIGTERM)
@@ -2,45 +2,179 @@ package app
 
 import (
 	"context"
+	"fmt"
+	logproducer "route256/loms/internal/adapter/kafka/log_producer"
 	syncproducer "route256/loms/internal/adapter/kafka/sync_producer"
+	"route256/loms/internal/adapter/payment/fake"
+	inmemoryorder "route256/loms/internal/adapter/repository/in_memory/order"
+	inmemoryoutbox "route256/loms/internal/adapter/repository/in_memory/outbox"
+	inmemorypayment "route256/loms/internal/adapter/repository/in_memory/payment"
+	inmemorystock "route256/loms/internal/adapter/repository/in_memory/stock"
+	inmemorystorage "route256/loms/internal/adapter/repository/in_memory/storage"
 	orderrepository "route256/loms/internal/adapter/repository/postgtres/order"
 	outboxrepository "route256/loms/internal/adapter/repository/postgtres/outbox"
+	paymentrepository "route256/loms/internal/adapter/repository/postgtres/payment"
 	stockrepository "route256/loms/internal/adapter/repository/postgtres/stock"
 	api "route256/loms/internal/api/grpc/orders/handler"
+	outboxadminapi "route256/loms/internal/api/grpc/outbox_admin/handler"
+	paymentsapi "route256/loms/internal/api/grpc/payments/handler"
+	stockadminapi "route256/loms/internal/api/grpc/stock_admin/handler"
 	orderevent "route256/loms/internal/business/cron/order_event"
+	outboxretention "route256/loms/internal/business/cron/outbox_retention"
+	unpaidorder "route256/loms/internal/business/cron/unpaid_order"
 	orderservice "route256/loms/internal/business/service/order"
+	"route256/loms/internal/business/service/order/watch"
+	outboxadminservice "route256/loms/internal/business/service/outbox_admin"
 	stockservice "route256/loms/internal/business/service/stock"
+	"route256/loms/internal/business/service/stock/allocation"
+	stockadminservice "route256/loms/internal/business/service/stock_admin"
+	"route256/loms/internal/domain"
 	"route256/loms/internal/infra/closer"
 	"route256/loms/internal/infra/config"
 	daemon "route256/loms/internal/infra/daemon"
+	"route256/loms/internal/infra/health"
+	"route256/loms/internal/infra/leader"
 	logger "route256/loms/internal/infra/logger"
 	pgpool "route256/loms/internal/infra/postgres"
+	"route256/loms/internal/infra/sharding"
 	txmanager "route256/loms/internal/infra/tx_manager"
+	desc "route256/loms/internal/pb/loms/v1"
 	"time"
 )
 
+const (
+	allocationStrategyPriority        = "priority"
+	allocationStrategyFewestShipments = "fewest_shipments"
+
+	singletonJobsLockID = 256_000_001
+
+	storagePostgres = "postgres"
+	storageInMemory = "in_memory"
+
+	checkPostgresMaster   = "postgres_master"
+	checkPostgresReplicas = "postgres_replicas"
+	checkKafka            = "kafka"
+	checkOutboxBacklog    = "outbox_backlog"
+)
+
+type orderRepository interface {
+	CreateOrder(ctx context.Context, userID int64) (int64, error)
+	CreateOrderItems(ctx context.Context, orderID int64, items []domain.Item) error
+	GetByOrderID(ctx context.Context, orderID int64) (domain.Order, error)
+	GetByOrderIDForUpdate(ctx context.Context, orderID int64) (domain.Order, error)
+	ListByUserID(ctx context.Context, filter domain.OrderListFilter) ([]domain.Order, error)
+	GetExpiredUnpaidOrderIDsForUpdate(
+		ctx context.Context,
+		paymentDeadline time.Duration,
+		excludedIDs []int64,
+		limit int32) ([]int64, error)
+	CreateOrderAllocations(ctx context.Context, orderID int64, allocations []domain.Allocation) error
+	GetAllocationsByOrderID(ctx context.Context, orderID int64) ([]domain.Allocation, error)
+	GetStatusHistory(ctx context.Context, orderID int64) ([]domain.OrderStatusChange, error)
+	DecreaseOrderAllocations(ctx context.Context, orderID int64, allocations []domain.Allocation) error
+	CancelOrderItems(ctx context.Context, orderID int64, items []domain.Item) error
+	ReturnOrderItems(ctx context.Context, orderID int64, items []domain.Item) error
+	SetStatus(ctx context.Context, orderID int64, status domain.OrderStatus) error
+	SetStatusAndCreateEvent(ctx context.Context, orderID int64, status domain.OrderStatus, event domain.Event) error
+}
+
+type stockRepository interface {
+	GetWarehouseStocksBySku(ctx context.Context, sku domain.Sku) ([]domain.WarehouseStock, error)
+	GetStocksBySkuForUpdate(ctx context.Context, items []domain.Item) (map[domain.Sku][]domain.WarehouseStock, error)
+	UpdateStocks(ctx context.Context, stocks []domain.WarehouseStock) error
+	CreateStock(ctx context.Context, stock domain.WarehouseStock) error
+	CreateStockMovements(ctx context.Context, movements []domain.StockMovement) error
+	ListStockMovements(ctx context.Context, filter domain.StockHistoryFilter) ([]domain.StockMovement, error)
+}
+
+type outboxRepository interface {
+	CreateEvent(ctx context.Context, events domain.Event) error
+	FetchNextMessages(ctx context.Context, limit int32) ([]domain.Event, error)
+	MarkAsSent(ctx context.Context, ids []int64) error
+	MarkAsError(ctx context.Context, ids []int64, policy domain.EventRetryPolicy) error
+	ListDeadEvents(ctx context.Context, filter domain.DeadEventsFilter) ([]domain.Event, error)
+	RedriveDeadEvents(ctx context.Context, ids []int64) ([]int64, error)
+	CreateArchivePartitions(ctx context.Context, retention time.Duration) error
+	ArchiveSentEvents(ctx context.Context, retention time.Duration, limit int32) (int64, error)
+	DeleteSentEvents(ctx context.Context, retention time.Duration, limit int32) (int64, error)
+	GetOldestUndeliveredAge(ctx context.Context) (time.Duration, error)
+	GetOldestPendingAge(ctx context.Context) (time.Duration, error)
+}
+
+type paymentRepository interface {
+	CreatePaymentIntent(ctx context.Context, intent domain.PaymentIntent) (domain.PaymentIntent, error)
+	GetPaymentIntent(ctx context.Context, orderID int64) (domain.PaymentIntent, error)
+	UpdatePaymentIntent(ctx context.Context, orderID int64, result domain.PaymentResult) error
+}
+
+type paymentGateway interface {
+	Name() string
+	Authorize(ctx context.Context, req domain.PaymentRequest) (domain.PaymentResult, error)
+	Capture(ctx context.Context, providerPaymentID string, amount int64, currency string) (domain.PaymentResult, error)
+	Refund(
+		ctx context.Context,
+		providerPaymentID string,
+		amount int64,
+		currency string,
+		idempotencyKey string) (domain.PaymentResult, error)
+}
+
+type txManager interface {
+	ReadCommitted(ctx context.Context, fnc txmanager.Handler) error
+}
+
+type eventProducer interface {
+	SendOrderEventsBatch(ctx context.Context, orderEvents []domain.Event) (successIDs []int64, errorIDs []int64, err error)
+	Ping(ctx context.Context) error
+}
+
 type serviceProvider struct {
 	config config.Config
 
-	masterDSN  string
-	replicaDSN string
-
-	orderRepository  *orderrepository.Repository
-	stockRepository  *stockrepository.Repository
-	outboxRepository *outboxrepository.Repository
-	connPools        *pgpool.Pools
+	masterDSN   string
+	replicaDSNs []string
+	shardDSNs   []shardDSN
+
+	orderRepository   orderRepository
+	stockRepository   stockRepository
+	outboxRepository  outboxRepository
+	paymentRepository paymentRepository
+	paymentGateway    paymentGateway
+	inMemoryStorage   *inmemorystorage.Storage
+	connPools         *pgpool.Pools
+	orderShards       *pgpool.Shards
+	outboxListeners   []*pgpool.Listener
+	leaderElector     *leader.Elector
 
 	txManagerMaster  *txmanager.TxManager
 	txManagerReplica *txmanager.TxManager
+	txManagerShards  *txmanager.TxManager
+	txManagerMemory  *inmemorystorage.TxManager
+
+	stockService             *stockservice.Service
+	stockAdminService        *stockadminservice.Service
+	outboxAdminService       *outboxadminservice.Service
+	eventCronProcessor       *orderevent.CronProcessor
+	unpaidOrderCronProcessor *unpaidorder.CronProcessor
+	outboxRetentionProcessor *outboxretention.CronProcessor
+	daemons                  []*daemon.Daemon
+	unpaidOrderDaemons       []*daemon.Daemon
+	outboxRetentionDaemons   []*daemon.Daemon
+	orderService             *orderservice.Service
+	statusBroadcaster        *watch.Broadcaster
+
+	appServer         *api.Implementation
+	stockAdminServer  *stockadminapi.Implementation
+	outboxAdminServer *outboxadminapi.Implementation
+	paymentsServer    *paymentsapi.Implementation
+
+	kafkaProducer eventProducer
+	healthChecker *health.Checker
+}
 
-	stockService       *stockservice.Service
-	eventCronProcessor *orderevent.CronProcessor
-	daemon             *daemon.Daemon
-	orderService       *orderservice.Service
-
-	appServer *api.Implementation
-
-	kafkaProducer *syncproducer.Producer
+type shardDSN struct {
+	master   string
+	replicas []string
 }
 
 func newServiceProvider() *serviceProvider {
@@ -52,18 +186,19 @@ func (srv *serviceProvider) PostgresPools(ctx context.Context) *pgpool.Pools {
 		return srv.connPools
 	}
 
-	pools, err := pgpool.New(ctx, srv.masterDSN, srv.replicaDSN)
+	pools, err := pgpool.New(
+		ctx,
+		srv.masterDSN,
+		srv.replicaDSNs,
+		pgpool.WithReplicaWait(time.Duration(srv.config.ReadConsistency.ReplicaWaitMs)*time.Millisecond),
+		pgpool.WithMaxReplicaLag(time.Duration(srv.config.ReadConsistency.ReplicaMaxLagMs)*time.Millisecond),
+	)
 	if err != nil {
 		logger.Fatalf(ctx, "pgpool.NewPools: failed to connect to db %v", err)
 	}
 
 	closer.Add(func() error {
-		pools.Master.Close()
-		return nil
-	})
-
-	closer.Add(func() error {
-		pools.Replica.Close()
+		pools.Close()
 		return nil
 	})
 
@@ -71,103 +206,431 @@ func (srv *serviceProvider) PostgresPools(ctx context.Context) *pgpool.Pools {
 	return pools
 }
 
-func (srv *serviceProvider) AppKafkaProducer(ctx context.Context) *syncproducer.Producer {
-	if srv.kafkaProducer == nil {
-		var err error
-		srv.kafkaProducer, err = syncproducer.New(ctx, srv.config)
+func (srv *serviceProvider) InMemory() bool {
+	return srv.config.Service.Storage == storageInMemory
+}
+
+func (srv *serviceProvider) InMemoryStorage(_ context.Context) *inmemorystorage.Storage {
+	if srv.inMemoryStorage == nil {
+		srv.inMemoryStorage = inmemorystorage.New()
+	}
 
+	return srv.inMemoryStorage
+}
+
+func (srv *serviceProvider) Sharded() bool {
+	return !srv.InMemory() && len(srv.shardDSNs) > 0
+}
+
+func (srv *serviceProvider) ShardCount(ctx context.Context) int {
+	if srv.InMemory() {
+		return 1
+	}
+
+	return srv.OrderShards(ctx).Count()
+}
+
+func (srv *serviceProvider) LeaderElection() bool {
+	return srv.config.Service.LeaderElection && !srv.InMemory()
+}
+
+func (srv *serviceProvider) OrderShards(ctx context.Context) *pgpool.Shards {
+	if srv.orderShards != nil {
+		return srv.orderShards
+	}
+
+	if !srv.Sharded() {
+		shards, err := pgpool.NewShards(ctx, txmanager.TxKey, srv.PostgresPools(ctx))
 		if err != nil {
-			logger.Fatalf(ctx, "syncproducer.New: failed to run producer %v", err)
+			logger.Fatalf(ctx, "pgpool.NewShards: %v", err)
 		}
 
-		closer.Add(func() error {
-			srv.kafkaProducer.Close(ctx)
-			return nil
-		})
+		srv.orderShards = shards
+		return shards
+	}
+
+	pools := make([]*pgpool.Pools, 0, len(srv.shardDSNs))
+	for idx, dsn := range srv.shardDSNs {
+		shardPools, err := pgpool.New(
+			ctx,
+			dsn.master,
+			dsn.replicas,
+			pgpool.WithReplicaWait(time.Duration(srv.config.ReadConsistency.ReplicaWaitMs)*time.Millisecond),
+			pgpool.WithMaxReplicaLag(time.Duration(srv.config.ReadConsistency.ReplicaMaxLagMs)*time.Millisecond),
+		)
+		if err != nil {
+			logger.Fatalf(ctx, "pgpool.New: failed to connect to shard %d: %v", idx, err)
+		}
+
+		pools = append(pools, shardPools)
+	}
+
+	shards, err := pgpool.NewShards(ctx, txmanager.ShardTxKey, pools...)
+	if err != nil {
+		logger.Fatalf(ctx, "pgpool.NewShards: %v", err)
+	}
+
+	closer.Add(func() error {
+		shards.Close()
+		return nil
+	})
+
+	srv.orderShards = shards
+	return shards
+}
+
+func (srv *serviceProvider) OutboxListeners(ctx context.Context) []*pgpool.Listener {
+	if srv.outboxListeners != nil {
+		return srv.outboxListeners
 	}
 
+	if !srv.Sharded() {
+		srv.outboxListeners = []*pgpool.Listener{pgpool.NewListener(srv.masterDSN, outboxrepository.NotifyChannel)}
+		return srv.outboxListeners
+	}
+
+	for _, dsn := range srv.shardDSNs {
+		srv.outboxListeners = append(srv.outboxListeners, pgpool.NewListener(dsn.master, outboxrepository.NotifyChannel))
+	}
+
+	return srv.outboxListeners
+}
+
+func (srv *serviceProvider) LeaderElector(_ context.Context) *leader.Elector {
+	if srv.leaderElector == nil {
+		srv.leaderElector = leader.New(
+			srv.masterDSN,
+			singletonJobsLockID,
+			time.Duration(srv.config.Service.LeaderCheckPeriod)*time.Second,
+		)
+	}
+
+	return srv.leaderElector
+}
+
+func (srv *serviceProvider) singletonDaemonOptions(ctx context.Context) []daemon.Option {
+	if !srv.LeaderElection() {
+		return nil
+	}
+
+	return []daemon.Option{daemon.WithLeader(srv.LeaderElector(ctx))}
+}
+
+func (srv *serviceProvider) AppKafkaProducer(ctx context.Context) eventProducer {
+	if srv.kafkaProducer != nil {
+		return srv.kafkaProducer
+	}
+
+	if srv.InMemory() {
+		srv.kafkaProducer = logproducer.New()
+		return srv.kafkaProducer
+	}
+
+	producer, err := syncproducer.New(ctx, srv.config)
+	if err != nil {
+		logger.Fatalf(ctx, "syncproducer.New: failed to run producer %v", err)
+	}
+
+	closer.Add(func() error {
+		producer.Close(ctx)
+		return nil
+	})
+
+	srv.kafkaProducer = producer
 	return srv.kafkaProducer
 }
 
-func (srv *serviceProvider) OutboxRepository(ctx context.Context) *outboxrepository.Repository {
+func (srv *serviceProvider) OutboxRepository(ctx context.Context) outboxRepository {
 	if srv.outboxRepository == nil {
-		srv.outboxRepository = outboxrepository.New(
-			srv.PostgresPools(ctx),
-		)
+		if srv.InMemory() {
+			srv.outboxRepository = inmemoryoutbox.New(srv.InMemoryStorage(ctx))
+		} else {
+			srv.outboxRepository = outboxrepository.New(
+				srv.OrderShards(ctx),
+			)
+		}
 	}
 
 	return srv.outboxRepository
 }
 
-func (srv *serviceProvider) OrderRepository(ctx context.Context) *orderrepository.Repository {
+func (srv *serviceProvider) OrderRepository(ctx context.Context) orderRepository {
 	if srv.orderRepository == nil {
-		srv.orderRepository = orderrepository.New(
-			srv.PostgresPools(ctx),
-			srv.OutboxRepository(ctx),
-		)
+		if srv.InMemory() {
+			srv.orderRepository = inmemoryorder.New(
+				srv.InMemoryStorage(ctx),
+				srv.OutboxRepository(ctx),
+			)
+		} else {
+			srv.orderRepository = orderrepository.New(
+				srv.OrderShards(ctx),
+				srv.OutboxRepository(ctx),
+			)
+		}
 	}
 
 	return srv.orderRepository
 }
 
-func (srv *serviceProvider) StockRepository(ctx context.Context) *stockrepository.Repository {
-	if srv.stockRepository == nil {
+func (srv *serviceProvider) PaymentRepository(ctx context.Context) paymentRepository {
+	if srv.paymentRepository == nil {
+		if srv.InMemory() {
+			srv.paymentRepository = inmemorypayment.New(srv.InMemoryStorage(ctx))
+		} else {
+			srv.paymentRepository = paymentrepository.New(srv.OrderShards(ctx))
+		}
+	}
+
+	return srv.paymentRepository
+}
+
+func (srv *serviceProvider) PaymentGateway() paymentGateway {
+	if srv.paymentGateway == nil {
+		mode := fake.Mode(srv.config.Payment.FakeMode)
+		if mode == "" {
+			mode = fake.ModeApprove
+		}
+
+		srv.paymentGateway = fake.New(mode, srv.paymentTimeout())
+	}
+
+	return srv.paymentGateway
+}
+
+func (srv *serviceProvider) paymentTimeout() time.Duration {
+	if srv.config.Payment.TimeoutMs <= 0 {
+		return 5 * time.Second
+	}
+
+	return time.Duration(srv.config.Payment.TimeoutMs) * time.Millisecond
+}
+
+func (srv *serviceProvider) StockRepository(ctx context.Context) stockRepository {
+	if srv.stockRepository != nil {
+		return srv.stockRepository
+	}
+
+	if !srv.InMemory() {
 		srv.stockRepository = stockrepository.New(
 			srv.PostgresPools(ctx),
 		)
+
+		return srv.stockRepository
 	}
 
+	repo, err := inmemorystock.New(ctx, srv.InMemoryStorage(ctx))
+	if err != nil {
+		logger.Fatalf(ctx, "inmemorystock.New: failed to load stocks %v", err)
+	}
+
+	srv.stockRepository = repo
 	return srv.stockRepository
 }
 
-func (srv *serviceProvider) TxManagerMaster(_ context.Context) *txmanager.TxManager {
+func (srv *serviceProvider) TxManagerMemory(ctx context.Context) *inmemorystorage.TxManager {
+	if srv.txManagerMemory == nil {
+		srv.txManagerMemory = inmemorystorage.NewTxManager(srv.InMemoryStorage(ctx))
+	}
+
+	return srv.txManagerMemory
+}
+
+func (srv *serviceProvider) TxManagerMaster(ctx context.Context) txManager {
+	if srv.InMemory() {
+		return srv.TxManagerMemory(ctx)
+	}
+
 	if srv.txManagerMaster == nil {
-		srv.txManagerMaster = txmanager.New(srv.connPools.Master)
+		srv.txManagerMaster = txmanager.New(
+			srv.connPools.Master,
+			txmanager.WithReplica(srv.connPools.ReadTransactor()),
+			txmanager.WithRetryPolicy(srv.TxRetryPolicy()),
+		)
 	}
 
 	return srv.txManagerMaster
 }
 
+func (srv *serviceProvider) TxManagerShards(ctx context.Context) txManager {
+	if srv.InMemory() {
+		return srv.TxManagerMemory(ctx)
+	}
+
+	if srv.txManagerShards == nil {
+		shards := srv.OrderShards(ctx)
+
+		srv.txManagerShards = txmanager.New(
+			shards,
+			txmanager.WithReplica(shards.ReadTransactor()),
+			txmanager.WithRetryPolicy(srv.TxRetryPolicy()),
+			txmanager.WithTxKey(shards.TxKey()),
+		)
+	}
+
+	return srv.txManagerShards
+}
+
+func (srv *serviceProvider) TxRetryPolicy() txmanager.RetryPolicy {
+	if srv.config.Service.TxMaxAttempts <= 0 {
+		return txmanager.DefaultRetryPolicy
+	}
+
+	return txmanager.RetryPolicy{
+		MaxAttempts: srv.config.Service.TxMaxAttempts,
+		BackoffBase: time.Duration(srv.config.Service.TxBackoffMs) * time.Millisecond,
+		BackoffMax:  time.Duration(srv.config.Service.TxBackoffMaxMs) * time.Millisecond,
+	}
+}
+
 func (srv *serviceProvider) TxManagerReplica(_ context.Context) *txmanager.TxManager {
 	if srv.txManagerReplica == nil {
-		srv.txManagerReplica = txmanager.New(srv.connPools.Replica)
+		srv.txManagerReplica = txmanager.New(srv.connPools.ReadTransactor())
 	}
 
 	return srv.txManagerReplica
 }
 
+func (srv *serviceProvider) AllocationStrategy(ctx context.Context) allocation.Strategy {
+	switch srv.config.Service.AllocationStrategy {
+	case allocationStrategyFewestShipments:
+		return allocation.NewFewestShipmentsStrategy()
+	case allocationStrategyPriority, "":
+		return allocation.NewPriorityStrategy(srv.config.Service.WarehousePriority)
+	default:
+		logger.Fatalf(ctx, "unknown allocation strategy %q", srv.config.Service.AllocationStrategy)
+		return nil
+	}
+}
+
 func (srv *serviceProvider) AppStockService(ctx context.Context) *stockservice.Service {
 	if srv.stockService == nil {
 		srv.stockService = stockservice.New(
 			srv.StockRepository(ctx),
+			srv.AllocationStrategy(ctx),
+			srv.TxManagerMaster(ctx),
 		)
 	}
 
 	return srv.stockService
 }
 
+func (srv *serviceProvider) AppStockAdminService(ctx context.Context) *stockadminservice.Service {
+	if srv.stockAdminService == nil {
+		srv.stockAdminService = stockadminservice.New(
+			srv.StockRepository(ctx),
+			srv.TxManagerMaster(ctx),
+		)
+	}
+
+	return srv.stockAdminService
+}
+
+func (srv *serviceProvider) AppOutboxAdminService(ctx context.Context) *outboxadminservice.Service {
+	if srv.outboxAdminService == nil {
+		srv.outboxAdminService = outboxadminservice.New(
+			srv.OutboxRepository(ctx),
+			srv.TxManagerShards(ctx),
+			srv.ShardCount(ctx),
+		)
+	}
+
+	return srv.outboxAdminService
+}
+
 func (srv *serviceProvider) EventCronProcessor(ctx context.Context) *orderevent.CronProcessor {
 	if srv.eventCronProcessor == nil {
 		srv.eventCronProcessor = orderevent.New(
-			srv.outboxRepository,
+			srv.OutboxRepository(ctx),
 			srv.AppKafkaProducer(ctx),
-			srv.txManagerMaster,
+			srv.TxManagerShards(ctx),
 			srv.config.Service.LimitOutboxMsg,
+			domain.EventRetryPolicy{
+				MaxAttempts: srv.config.Service.OutboxMaxAttempts,
+				BackoffBase: time.Duration(srv.config.Service.OutboxBackoff) * time.Second,
+				BackoffMax:  time.Duration(srv.config.Service.OutboxBackoffMax) * time.Second,
+			},
 		)
 	}
 
 	return srv.eventCronProcessor
 }
 
-func (srv *serviceProvider) Daemon(ctx context.Context) *daemon.Daemon {
-	if srv.daemon == nil {
-		srv.daemon = daemon.New(
+func (srv *serviceProvider) Daemons(ctx context.Context) []*daemon.Daemon {
+	if srv.daemons == nil && srv.InMemory() {
+		srv.daemons = []*daemon.Daemon{daemon.New(
 			srv.EventCronProcessor(ctx),
 			time.Duration(srv.config.Service.HandlePeriod)*time.Second,
+		)}
+	}
+
+	if srv.daemons == nil {
+		for _, listener := range srv.OutboxListeners(ctx) {
+			srv.daemons = append(srv.daemons, daemon.New(
+				srv.EventCronProcessor(ctx),
+				time.Duration(srv.config.Service.HandlePeriod)*time.Second,
+				append(
+					srv.singletonDaemonOptions(ctx),
+					daemon.WithWakeup(listener.Notifications()),
+				)...,
+			))
+		}
+	}
+
+	return srv.daemons
+}
+
+func (srv *serviceProvider) UnpaidOrderCronProcessor(ctx context.Context) *unpaidorder.CronProcessor {
+	if srv.unpaidOrderCronProcessor == nil {
+		srv.unpaidOrderCronProcessor = unpaidorder.New(
+			srv.AppOrderService(ctx),
+			time.Duration(srv.config.Service.PaymentDeadline)*time.Second,
+			srv.config.Service.LimitUnpaidOrders,
 		)
 	}
 
-	return srv.daemon
+	return srv.unpaidOrderCronProcessor
+}
+
+func (srv *serviceProvider) UnpaidOrderDaemons(ctx context.Context) []*daemon.Daemon {
+	if srv.unpaidOrderDaemons == nil {
+		for range srv.ShardCount(ctx) {
+			srv.unpaidOrderDaemons = append(srv.unpaidOrderDaemons, daemon.New(
+				srv.UnpaidOrderCronProcessor(ctx),
+				time.Duration(srv.config.Service.UnpaidCancelPeriod)*time.Second,
+				srv.singletonDaemonOptions(ctx)...,
+			))
+		}
+	}
+
+	return srv.unpaidOrderDaemons
+}
+
+func (srv *serviceProvider) OutboxRetentionProcessor(ctx context.Context) *outboxretention.CronProcessor {
+	if srv.outboxRetentionProcessor == nil {
+		srv.outboxRetentionProcessor = outboxretention.New(
+			srv.OutboxRepository(ctx),
+			time.Duration(srv.config.Service.OutboxRetention)*24*time.Hour,
+			srv.config.Service.OutboxArchive,
+			srv.config.Service.LimitOutboxClean,
+			srv.ShardCount(ctx),
+		)
+	}
+
+	return srv.outboxRetentionProcessor
+}
+
+func (srv *serviceProvider) OutboxRetentionDaemons(ctx context.Context) []*daemon.Daemon {
+	if srv.outboxRetentionDaemons == nil {
+		for range srv.ShardCount(ctx) {
+			srv.outboxRetentionDaemons = append(srv.outboxRetentionDaemons, daemon.New(
+				srv.OutboxRetentionProcessor(ctx),
+				time.Duration(srv.config.Service.OutboxCleanPeriod)*time.Second,
+				srv.singletonDaemonOptions(ctx)...,
+			))
+		}
+	}
+
+	return srv.outboxRetentionDaemons
 }
 
 func (srv *serviceProvider) AppOrderService(ctx context.Context) *orderservice.Service {
@@ -177,20 +640,161 @@ func (srv *serviceProvider) AppOrderService(ctx context.Context) *orderservice.S
 			srv.OrderRepository(ctx),
 			srv.OutboxRepository(ctx),
 			srv.AppStockService(ctx),
-			srv.TxManagerMaster(ctx),
+			srv.TxManagerShards(ctx),
+			srv.StatusBroadcaster(),
+			srv.PaymentRepository(ctx),
+			srv.PaymentGateway(),
+			srv.Sharded(),
 		)
 	}
 
 	return srv.orderService
 }
 
+func (srv *serviceProvider) StatusBroadcaster() *watch.Broadcaster {
+	if srv.statusBroadcaster == nil {
+		policy := watch.SlowConsumerPolicy(srv.config.Watch.SlowConsumerPolicy)
+		if policy == "" {
+			policy = watch.SlowConsumerDisconnect
+		}
+
+		srv.statusBroadcaster = watch.New(srv.config.Watch.BufferSize, policy)
+	}
+
+	return srv.statusBroadcaster
+}
+
+func (srv *serviceProvider) watchHeartbeat() time.Duration {
+	if srv.config.Watch.HeartbeatIntervalMs <= 0 {
+		return 15 * time.Second
+	}
+
+	return time.Duration(srv.config.Watch.HeartbeatIntervalMs) * time.Millisecond
+}
+
 func (srv *serviceProvider) AppHandler(ctx context.Context) *api.Implementation {
 	if srv.appServer == nil {
 		srv.appServer = api.NewImplementation(
 			srv.AppOrderService(ctx),
 			srv.AppStockService(ctx),
+			srv.HealthChecker(ctx),
+			srv.watchHeartbeat(),
 		)
 	}
 
 	return srv.appServer
 }
+
+func (srv *serviceProvider) StockAdminHandler(ctx context.Context) *stockadminapi.Implementation {
+	if srv.stockAdminServer == nil {
+		srv.stockAdminServer = stockadminapi.NewImplementation(
+			srv.AppStockAdminService(ctx),
+		)
+	}
+
+	return srv.stockAdminServer
+}
+
+func (srv *serviceProvider) OutboxAdminHandler(ctx context.Context) *outboxadminapi.Implementation {
+	if srv.outboxAdminServer == nil {
+		srv.outboxAdminServer = outboxadminapi.NewImplementation(
+			srv.AppOutboxAdminService(ctx),
+		)
+	}
+
+	return srv.outboxAdminServer
+}
+
+func (srv *serviceProvider) PaymentsHandler(ctx context.Context) *paymentsapi.Implementation {
+	if srv.paymentsServer == nil {
+		srv.paymentsServer = paymentsapi.NewImplementation(
+			srv.AppOrderService(ctx),
+		)
+	}
+
+	return srv.paymentsServer
+}
+
+func (srv *serviceProvider) HealthChecker(ctx context.Context) *health.Checker {
+	if srv.healthChecker != nil {
+		return srv.healthChecker
+	}
+
+	checker := health.New(srv.healthCheckTimeout())
+
+	var dbChecks []string
+	if !srv.InMemory() {
+		pools := srv.PostgresPools(ctx)
+		checker.Add(checkPostgresMaster, pools.Ping)
+		dbChecks = append(dbChecks, checkPostgresMaster)
+
+		// Reads fall back to the master, so replicas are reported but do not gate readiness.
+		checker.AddDetail(checkPostgresReplicas, pools.CheckReplicas)
+
+		if srv.Sharded() {
+			shards := srv.OrderShards(ctx)
+			for idx := range shards.Count() {
+				master := fmt.Sprintf("postgres_shard_%d_master", idx)
+				replicas := fmt.Sprintf("postgres_shard_%d_replicas", idx)
+
+				checker.Add(master, shards.Shard(idx).Ping)
+				checker.AddDetail(replicas, shards.Shard(idx).CheckReplicas)
+				dbChecks = append(dbChecks, master)
+			}
+		}
+
+		checker.Add(checkKafka, srv.AppKafkaProducer(ctx).Ping)
+	}
+
+	if srv.config.Health.OutboxBacklogMaxAgeSec > 0 {
+		checker.Add(checkOutboxBacklog, srv.outboxBacklogCheck(
+			ctx,
+			time.Duration(srv.config.Health.OutboxBacklogMaxAgeSec)*time.Second,
+		))
+	}
+
+	checker.Service(desc.Orders_ServiceDesc.ServiceName, dbChecks...)
+	checker.Service(desc.Stocks_ServiceDesc.ServiceName, dbChecks...)
+	checker.Service(desc.StockAdmin_ServiceDesc.ServiceName, dbChecks...)
+	checker.Service(desc.OutboxAdmin_ServiceDesc.ServiceName, dbChecks...)
+	checker.Service(desc.Payments_ServiceDesc.ServiceName, dbChecks...)
+
+	srv.healthChecker = checker
+	return checker
+}
+
+func (srv *serviceProvider) outboxBacklogCheck(ctx context.Context, maxAge time.Duration) health.CheckFunc {
+	repo := srv.OutboxRepository(ctx)
+	shardCount := srv.ShardCount(ctx)
+
+	return func(ctx context.Context) error {
+		for shard := range shardCount {
+			age, err := repo.GetOldestPendingAge(sharding.WithShard(ctx, shard))
+			if err != nil {
+				return fmt.Errorf("shard %d: %w", shard, err)
+			}
+
+			if age > maxAge {
+				return fmt.Errorf("shard %d: oldest pending event is %s old", shard, age.Truncate(time.Second))
+			}
+		}
+
+		return nil
+	}
+}
+
+func (srv *serviceProvider) HealthCheckPeriod() time.Duration {
+	if srv.config.Health.CheckPeriodMs <= 0 {
+		return 5 * time.Second
+	}
+
+	return time.Duration(srv.config.Health.CheckPeriodMs) * time.Millisecond
+}
+
+func (srv *serviceProvider) healthCheckTimeout() time.Duration {
+	if srv.config.Health.CheckTimeoutMs <= 0 {
+		return 2 * time.Second
+	}
+
+	return time.Duration(srv.config.Health.CheckTimeoutMs) * time.Millisecond
+}
@@ -4,6 +4,12 @@ import (
 	"context"
 	"route256/loms/internal/domain"
 	txmanager "route256/loms/internal/infra/tx_manager"
+	"time"
+)
+
+const (
+	defaultMaxAttempts = 10
+	defaultBackoffBase = time.Second
 )
 
 //go:generate rm -rf mock
@@ -16,7 +22,7 @@ type producerKafka interface {
 type eventRepository interface {
 	FetchNextMessages(ctx context.Context, limit int32) ([]domain.Event, error)
 	MarkAsSent(ctx context.Context, ids []int64) error
-	MarkAsError(ctx context.Context, orderIDs []int64) error
+	MarkAsError(ctx context.Context, orderIDs []int64, policy domain.EventRetryPolicy) error
 }
 
 type txManager interface {
@@ -28,6 +34,7 @@ type CronProcessor struct {
 	producerKafka   producerKafka
 	txManagerMaster txManager
 	limitMsg        int32
+	retryPolicy     domain.EventRetryPolicy
 }
 
 func New(
@@ -35,11 +42,25 @@ func New(
 	producerKafka producerKafka,
 	txManagerMaster txManager,
 	limitMsg int32,
+	retryPolicy domain.EventRetryPolicy,
 ) *CronProcessor {
+	if retryPolicy.MaxAttempts <= 0 {
+		retryPolicy.MaxAttempts = defaultMaxAttempts
+	}
+
+	if retryPolicy.BackoffBase <= 0 {
+		retryPolicy.BackoffBase = defaultBackoffBase
+	}
+
+	if retryPolicy.BackoffMax < retryPolicy.BackoffBase {
+		retryPolicy.BackoffMax = retryPolicy.BackoffBase
+	}
+
 	return &CronProcessor{
 		eventRepository: eventRepository,
 		producerKafka:   producerKafka,
 		txManagerMaster: txManagerMaster,
 		limitMsg:        limitMsg,
+		retryPolicy:     retryPolicy,
 	}
 }
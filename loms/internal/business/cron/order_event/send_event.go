@@ -3,7 +3,10 @@ package orderevent
 import (
 	"context"
 	"fmt"
+	"route256/loms/internal/domain"
 	"route256/loms/internal/infra/logger"
+	"route256/loms/internal/infra/metrics"
+	"time"
 
 	"github.com/opentracing/opentracing-go"
 )
@@ -12,13 +15,32 @@ func (c *CronProcessor) Do(ctx context.Context) error {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "orederEvent.Do")
 	defer span.Finish()
 
-	if err := c.txManagerMaster.ReadCommitted(ctx, func(ctx context.Context) error {
+	for ctx.Err() == nil {
+		fetched, err := c.sendBatch(ctx)
+		if err != nil {
+			logger.Errorf(ctx, "eventService.handlePendingEvents: %v", err)
+			return nil
+		}
+
+		if fetched < int(c.limitMsg) {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func (c *CronProcessor) sendBatch(ctx context.Context) (int, error) {
+	var fetched int
+
+	err := c.txManagerMaster.ReadCommitted(ctx, func(ctx context.Context) error {
 		events, err := c.eventRepository.FetchNextMessages(ctx, c.limitMsg)
 		if err != nil {
 			return fmt.Errorf("eventRepository.FetchNextMessage: %w", err)
 		}
 
-		if len(events) == 0 {
+		fetched = len(events)
+		if fetched == 0 {
 			return nil
 		}
 
@@ -31,18 +53,33 @@ func (c *CronProcessor) Do(ctx context.Context) error {
 			if err := c.eventRepository.MarkAsSent(ctx, successIDs); err != nil {
 				return fmt.Errorf("eventRepository.MarkAsSent: %w", err)
 			}
+
+			observeLatency(events, successIDs)
 		}
 
 		if len(errorIDs) > 0 {
-			if err := c.eventRepository.MarkAsError(ctx, errorIDs); err != nil {
+			if err := c.eventRepository.MarkAsError(ctx, errorIDs, c.retryPolicy); err != nil {
 				return fmt.Errorf("eventRepository.MarkAsError: %w", err)
 			}
 		}
 
 		return nil
-	}); err != nil {
-		logger.Errorf(ctx, "eventService.handlePendingEvents: %v", err)
+	})
+
+	return fetched, err
+}
+
+func observeLatency(events []domain.Event, successIDs []int64) {
+	sent := make(map[int64]struct{}, len(successIDs))
+	for _, id := range successIDs {
+		sent[id] = struct{}{}
 	}
 
-	return nil
+	for _, event := range events {
+		if _, ok := sent[event.ID]; !ok || event.CreatedAt.IsZero() {
+			continue
+		}
+
+		metrics.OutboxEventLatencyHistogram(event.Topic, time.Since(event.CreatedAt).Seconds())
+	}
 }
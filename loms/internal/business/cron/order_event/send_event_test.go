@@ -3,11 +3,15 @@ package orderevent_test
 import (
 	"context"
 	"fmt"
+	orderevent "route256/loms/internal/business/cron/order_event"
+	"route256/loms/internal/business/cron/order_event/mock"
 	"route256/loms/internal/domain"
 	txmanager "route256/loms/internal/infra/tx_manager"
 	"testing"
+	"time"
 
 	"github.com/gojuno/minimock/v3"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestHandlePendingEvents_SuccessAllSent(t *testing.T) {
@@ -76,11 +80,12 @@ func TestHandlePendingEvents_OneMessageFails(t *testing.T) {
 		return nil
 	})
 
-	f.eventRepository.MarkAsErrorMock.Set(func(_ context.Context, ids []int64) error {
+	f.eventRepository.MarkAsErrorMock.Set(func(_ context.Context, ids []int64, policy domain.EventRetryPolicy) error {
 		expected := []int64{1}
 		if !f.ElementsMatch(ids, expected) {
 			t.Errorf("MarkAsError got IDs %v, want %v", ids, expected)
 		}
+		f.Equal(testRetryPolicy, policy)
 		return nil
 	})
 
@@ -163,3 +168,117 @@ func TestHandlePendingEvents_SendOrderEventsBatchError(t *testing.T) {
 	err := f.executor.Do(ctx)
 	f.NoError(err)
 }
+
+func TestHandlePendingEvents_DefaultRetryPolicy(t *testing.T) {
+	t.Parallel()
+
+	events := []domain.Event{
+		{ID: 1, Key: "123", Payload: []byte("payload1")},
+	}
+
+	ctrl := minimock.NewController(t)
+	eventRepository := mock.NewEventRepositoryMock(ctrl)
+	producerKafka := mock.NewProducerKafkaMock(ctrl)
+	txManager := mock.NewTxManagerMock(ctrl)
+
+	executor := orderevent.New(eventRepository, producerKafka, txManager, 100, domain.EventRetryPolicy{})
+
+	txManager.ReadCommittedMock.Set(func(ctx context.Context, fn txmanager.Handler) error {
+		return fn(ctx)
+	})
+
+	eventRepository.FetchNextMessagesMock.
+		Expect(minimock.AnyContext, 100).
+		Return(events, nil)
+
+	producerKafka.SendOrderEventsBatchMock.Set(func(_ context.Context, _ []domain.Event) ([]int64, []int64, error) {
+		return nil, []int64{1}, nil
+	})
+
+	eventRepository.MarkAsErrorMock.
+		Expect(minimock.AnyContext, []int64{1}, domain.EventRetryPolicy{
+			MaxAttempts: 10,
+			BackoffBase: time.Second,
+			BackoffMax:  time.Second,
+		}).
+		Return(nil)
+
+	err := executor.Do(context.Background())
+	assert.NoError(t, err)
+}
+
+func TestHandlePendingEvents_MarkAsErrorError(t *testing.T) {
+	t.Parallel()
+
+	events := []domain.Event{
+		{ID: 1, Key: "123", Payload: []byte("payload1")},
+	}
+
+	f := setUp(t)
+	ctx := context.Background()
+
+	f.txManager.ReadCommittedMock.Set(func(ctx context.Context, fn txmanager.Handler) error {
+		return fn(ctx)
+	})
+
+	f.eventRepository.FetchNextMessagesMock.
+		Expect(minimock.AnyContext, 100).
+		Return(events, nil)
+
+	f.producerKafka.SendOrderEventsBatchMock.Set(func(_ context.Context, _ []domain.Event) ([]int64, []int64, error) {
+		return nil, []int64{1}, nil
+	})
+
+	f.eventRepository.MarkAsErrorMock.
+		Expect(minimock.AnyContext, []int64{1}, testRetryPolicy).
+		Return(fmt.Errorf("mark error error"))
+
+	err := f.executor.Do(ctx)
+	f.NoError(err)
+}
+
+func TestHandlePendingEvents_DrainsFullBatches(t *testing.T) {
+	t.Parallel()
+
+	fullBatch := make([]domain.Event, 100)
+	fullIDs := make([]int64, len(fullBatch))
+	for idx := range fullBatch {
+		fullBatch[idx] = domain.Event{
+			ID:        int64(idx + 1),
+			Topic:     "loms.order-events",
+			Key:       "1",
+			CreatedAt: time.Now().Add(-time.Second),
+		}
+		fullIDs[idx] = int64(idx + 1)
+	}
+
+	batches := [][]domain.Event{fullBatch, fullBatch[:1]}
+	call := 0
+
+	f := setUp(t)
+	ctx := context.Background()
+
+	f.txManager.ReadCommittedMock.Set(func(ctx context.Context, fn txmanager.Handler) error {
+		return fn(ctx)
+	})
+
+	f.eventRepository.FetchNextMessagesMock.Set(func(_ context.Context, limit int32) ([]domain.Event, error) {
+		f.Equal(int32(100), limit)
+
+		events := batches[call]
+		call++
+
+		return events, nil
+	})
+
+	f.producerKafka.SendOrderEventsBatchMock.Set(func(_ context.Context, events []domain.Event) ([]int64, []int64, error) {
+		return fullIDs[:len(events)], nil, nil
+	})
+
+	f.eventRepository.MarkAsSentMock.Return(nil)
+
+	err := f.executor.Do(ctx)
+	f.NoError(err)
+	f.Equal(len(batches), call)
+	f.Equal(uint64(2), f.eventRepository.MarkAsSentAfterCounter())
+}
@@ -1,10 +1,14 @@
 package orderevent_test
 
 import (
+	"context"
 	orderevent "route256/loms/internal/business/cron/order_event"
 	"route256/loms/internal/business/cron/order_event/mock"
+	"route256/loms/internal/domain"
 	"route256/loms/internal/infra/logger"
+	"route256/loms/internal/infra/metrics"
 	"testing"
+	"time"
 
 	"github.com/gojuno/minimock/v3"
 	"github.com/stretchr/testify/assert"
@@ -12,6 +16,12 @@ import (
 	"go.uber.org/zap/zapcore"
 )
 
+var testRetryPolicy = domain.EventRetryPolicy{
+	MaxAttempts: 5,
+	BackoffBase: time.Second,
+	BackoffMax:  time.Minute,
+}
+
 type fixture struct {
 	*assert.Assertions
 	eventRepository *mock.EventRepositoryMock
@@ -26,11 +36,14 @@ func setUp(t *testing.T) *fixture {
 	err := logger.Init(zapcore.DebugLevel)
 	require.NoError(t, err)
 
+	err = metrics.Init(context.Background())
+	require.NoError(t, err)
+
 	eventRepository := mock.NewEventRepositoryMock(ctrl)
 	producerMock := mock.NewProducerKafkaMock(ctrl)
 	txManagerMock := mock.NewTxManagerMock(ctrl)
 
-	executor := orderevent.New(eventRepository, producerMock, txManagerMock, 100)
+	executor := orderevent.New(eventRepository, producerMock, txManagerMock, 100, testRetryPolicy)
 
 	return &fixture{
 		Assertions:      assert.New(t),
@@ -0,0 +1,40 @@
+package outboxretention
+
+import (
+	"context"
+	"time"
+)
+
+//go:generate rm -rf mock
+//go:generate mkdir -p mock
+//go:generate minimock -i * -o ./mock -s "_mock.go" -g
+type eventRepository interface {
+	CreateArchivePartitions(ctx context.Context, retention time.Duration) error
+	ArchiveSentEvents(ctx context.Context, retention time.Duration, limit int32) (int64, error)
+	DeleteSentEvents(ctx context.Context, retention time.Duration, limit int32) (int64, error)
+	GetOldestUndeliveredAge(ctx context.Context) (time.Duration, error)
+}
+
+type CronProcessor struct {
+	eventRepository eventRepository
+	retention       time.Duration
+	archive         bool
+	limitMsg        int32
+	shardCount      int
+}
+
+func New(
+	eventRepository eventRepository,
+	retention time.Duration,
+	archive bool,
+	limitMsg int32,
+	shardCount int,
+) *CronProcessor {
+	return &CronProcessor{
+		eventRepository: eventRepository,
+		retention:       retention,
+		archive:         archive,
+		limitMsg:        limitMsg,
+		shardCount:      max(shardCount, 1),
+	}
+}
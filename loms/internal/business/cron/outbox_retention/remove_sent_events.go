@@ -0,0 +1,69 @@
+package outboxretention
+
+import (
+	"context"
+	"route256/loms/internal/infra/logger"
+	"route256/loms/internal/infra/metrics"
+	"route256/loms/internal/infra/sharding"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+func (c *CronProcessor) Do(ctx context.Context) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "outboxRetention.Do")
+	defer span.Finish()
+
+	age, err := c.eventRepository.GetOldestUndeliveredAge(ctx)
+	if err != nil {
+		logger.Errorf(ctx, "eventRepository.GetOldestUndeliveredAge: %v", err)
+	} else {
+		metrics.SetOutboxOldestUndeliveredSeconds(sharding.ShardLabel(ctx, c.shardCount), age.Seconds())
+	}
+
+	if c.archive {
+		if err := c.eventRepository.CreateArchivePartitions(ctx, c.retention); err != nil {
+			logger.Errorf(ctx, "eventRepository.CreateArchivePartitions: %v", err)
+			return nil
+		}
+	}
+
+	for ctx.Err() == nil {
+		removed, err := c.removeBatch(ctx)
+		if err != nil {
+			logger.Errorf(ctx, "outboxRetention.removeBatch: %v", err)
+			return nil
+		}
+
+		if removed > 0 {
+			logger.Infof(ctx, "removed %v sent outbox events", removed)
+		}
+
+		if removed < int64(c.limitMsg) {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func (c *CronProcessor) removeBatch(ctx context.Context) (int64, error) {
+	if c.archive {
+		archived, err := c.eventRepository.ArchiveSentEvents(ctx, c.retention, c.limitMsg)
+		if err != nil {
+			return 0, err
+		}
+
+		metrics.AddOutboxRemovedCounter(metrics.OutboxRemovedArchived, archived)
+
+		return archived, nil
+	}
+
+	deleted, err := c.eventRepository.DeleteSentEvents(ctx, c.retention, c.limitMsg)
+	if err != nil {
+		return 0, err
+	}
+
+	metrics.AddOutboxRemovedCounter(metrics.OutboxRemovedDeleted, deleted)
+
+	return deleted, nil
+}
@@ -0,0 +1,122 @@
+package outboxretention_test
+
+import (
+	"context"
+	testhelpers "route256/loms/internal/tool"
+	"testing"
+	"time"
+
+	"github.com/gojuno/minimock/v3"
+)
+
+func TestRemoveSentEvents_DeletesFullBatches(t *testing.T) {
+	t.Parallel()
+
+	f := setUp(t, false)
+	ctx := context.Background()
+
+	batches := []int64{2, 2, 1}
+	call := 0
+
+	f.eventRepository.GetOldestUndeliveredAgeMock.
+		Expect(minimock.AnyContext).
+		Return(time.Minute, nil)
+
+	f.eventRepository.DeleteSentEventsMock.Set(func(_ context.Context, retention time.Duration, limit int32) (int64, error) {
+		f.Equal(testRetention, retention)
+		f.Equal(testLimitMsg, limit)
+
+		deleted := batches[call]
+		call++
+
+		return deleted, nil
+	})
+
+	err := f.executor.Do(ctx)
+	f.NoError(err)
+	f.Equal(len(batches), call)
+}
+
+func TestRemoveSentEvents_ArchivesFullBatches(t *testing.T) {
+	t.Parallel()
+
+	f := setUp(t, true)
+	ctx := context.Background()
+
+	batches := []int64{2, 0}
+	call := 0
+
+	f.eventRepository.GetOldestUndeliveredAgeMock.
+		Expect(minimock.AnyContext).
+		Return(0, nil)
+
+	f.eventRepository.CreateArchivePartitionsMock.
+		Expect(minimock.AnyContext, testRetention).
+		Return(nil)
+
+	f.eventRepository.ArchiveSentEventsMock.Set(func(_ context.Context, retention time.Duration, limit int32) (int64, error) {
+		f.Equal(testRetention, retention)
+		f.Equal(testLimitMsg, limit)
+
+		archived := batches[call]
+		call++
+
+		return archived, nil
+	})
+
+	err := f.executor.Do(ctx)
+	f.NoError(err)
+	f.Equal(len(batches), call)
+}
+
+func TestRemoveSentEvents_StopsOnCreateArchivePartitionsError(t *testing.T) {
+	t.Parallel()
+
+	f := setUp(t, true)
+	ctx := context.Background()
+
+	f.eventRepository.GetOldestUndeliveredAgeMock.
+		Expect(minimock.AnyContext).
+		Return(0, nil)
+
+	f.eventRepository.CreateArchivePartitionsMock.
+		Expect(minimock.AnyContext, testRetention).
+		Return(testhelpers.ErrForTest)
+
+	err := f.executor.Do(ctx)
+	f.NoError(err)
+	f.Equal(uint64(0), f.eventRepository.ArchiveSentEventsAfterCounter())
+}
+
+func TestRemoveSentEvents_StopsOnError(t *testing.T) {
+	t.Parallel()
+
+	f := setUp(t, false)
+	ctx := context.Background()
+
+	f.eventRepository.GetOldestUndeliveredAgeMock.
+		Expect(minimock.AnyContext).
+		Return(0, testhelpers.ErrForTest)
+
+	f.eventRepository.DeleteSentEventsMock.
+		Return(0, testhelpers.ErrForTest)
+
+	err := f.executor.Do(ctx)
+	f.NoError(err)
+	f.Equal(uint64(1), f.eventRepository.DeleteSentEventsAfterCounter())
+}
+
+func TestRemoveSentEvents_StopsOnCancelledContext(t *testing.T) {
+	t.Parallel()
+
+	f := setUp(t, false)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	f.eventRepository.GetOldestUndeliveredAgeMock.
+		Return(0, context.Canceled)
+
+	err := f.executor.Do(ctx)
+	f.NoError(err)
+	f.Equal(uint64(0), f.eventRepository.DeleteSentEventsAfterCounter())
+}
@@ -0,0 +1,47 @@
+package outboxretention_test
+
+import (
+	"context"
+	outboxretention "route256/loms/internal/business/cron/outbox_retention"
+	"route256/loms/internal/business/cron/outbox_retention/mock"
+	"route256/loms/internal/infra/logger"
+	"route256/loms/internal/infra/metrics"
+	"testing"
+	"time"
+
+	"github.com/gojuno/minimock/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	testRetention = 7 * 24 * time.Hour
+	testLimitMsg  = int32(2)
+)
+
+type fixture struct {
+	*assert.Assertions
+	eventRepository *mock.EventRepositoryMock
+	executor        *outboxretention.CronProcessor
+}
+
+func setUp(t *testing.T, archive bool) *fixture {
+	ctrl := minimock.NewController(t)
+
+	err := logger.Init(zapcore.DebugLevel)
+	require.NoError(t, err)
+
+	err = metrics.Init(context.Background())
+	require.NoError(t, err)
+
+	eventRepository := mock.NewEventRepositoryMock(ctrl)
+
+	executor := outboxretention.New(eventRepository, testRetention, archive, testLimitMsg, 1)
+
+	return &fixture{
+		Assertions:      assert.New(t),
+		eventRepository: eventRepository,
+		executor:        executor,
+	}
+}
@@ -0,0 +1,31 @@
+package unpaidorder
+
+import (
+	"context"
+	"route256/loms/internal/infra/logger"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+func (c *CronProcessor) Do(ctx context.Context) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "unpaidOrder.Do")
+	defer span.Finish()
+
+	for ctx.Err() == nil {
+		cancelled, err := c.orderService.CancelUnpaidOrders(ctx, c.paymentDeadline, c.limitOrders)
+		if err != nil {
+			logger.Errorf(ctx, "orderService.CancelUnpaidOrders: %v", err)
+			return nil
+		}
+
+		if cancelled > 0 {
+			logger.Infof(ctx, "cancelled %v unpaid orders", cancelled)
+		}
+
+		if cancelled < int(c.limitOrders) {
+			return nil
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package unpaidorder_test
+
+import (
+	"context"
+	testhelpers "route256/loms/internal/tool"
+	"testing"
+	"time"
+)
+
+func TestCancelOrders_DrainsFullBatches(t *testing.T) {
+	t.Parallel()
+
+	f := setUp(t)
+	ctx := context.Background()
+
+	batches := []int{2, 2, 1}
+	call := 0
+
+	f.orderService.CancelUnpaidOrdersMock.Set(func(_ context.Context, deadline time.Duration, limit int32) (int, error) {
+		f.Equal(testPaymentDeadline, deadline)
+		f.Equal(testLimitOrders, limit)
+
+		cancelled := batches[call]
+		call++
+
+		return cancelled, nil
+	})
+
+	err := f.executor.Do(ctx)
+	f.NoError(err)
+	f.Equal(len(batches), call)
+}
+
+func TestCancelOrders_StopsOnError(t *testing.T) {
+	t.Parallel()
+
+	f := setUp(t)
+	ctx := context.Background()
+
+	f.orderService.CancelUnpaidOrdersMock.
+		Return(0, testhelpers.ErrForTest)
+
+	err := f.executor.Do(ctx)
+	f.NoError(err)
+	f.Equal(uint64(1), f.orderService.CancelUnpaidOrdersAfterCounter())
+}
+
+func TestCancelOrders_StopsOnCancelledContext(t *testing.T) {
+	t.Parallel()
+
+	f := setUp(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := f.executor.Do(ctx)
+	f.NoError(err)
+	f.Equal(uint64(0), f.orderService.CancelUnpaidOrdersAfterCounter())
+}
@@ -0,0 +1,31 @@
+package unpaidorder
+
+import (
+	"context"
+	"time"
+)
+
+//go:generate rm -rf mock
+//go:generate mkdir -p mock
+//go:generate minimock -i * -o ./mock -s "_mock.go" -g
+type orderService interface {
+	CancelUnpaidOrders(ctx context.Context, paymentDeadline time.Duration, limit int32) (int, error)
+}
+
+type CronProcessor struct {
+	orderService    orderService
+	paymentDeadline time.Duration
+	limitOrders     int32
+}
+
+func New(
+	orderService orderService,
+	paymentDeadline time.Duration,
+	limitOrders int32,
+) *CronProcessor {
+	return &CronProcessor{
+		orderService:    orderService,
+		paymentDeadline: paymentDeadline,
+		limitOrders:     limitOrders,
+	}
+}
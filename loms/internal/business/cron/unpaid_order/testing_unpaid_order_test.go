@@ -0,0 +1,42 @@
+package unpaidorder_test
+
+import (
+	unpaidorder "route256/loms/internal/business/cron/unpaid_order"
+	"route256/loms/internal/business/cron/unpaid_order/mock"
+	"route256/loms/internal/infra/logger"
+	"testing"
+	"time"
+
+	"github.com/gojuno/minimock/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	testPaymentDeadline = 15 * time.Minute
+	testLimitOrders     = int32(2)
+)
+
+type fixture struct {
+	*assert.Assertions
+	orderService *mock.OrderServiceMock
+	executor     *unpaidorder.CronProcessor
+}
+
+func setUp(t *testing.T) *fixture {
+	ctrl := minimock.NewController(t)
+
+	err := logger.Init(zapcore.DebugLevel)
+	require.NoError(t, err)
+
+	orderService := mock.NewOrderServiceMock(ctrl)
+
+	executor := unpaidorder.New(orderService, testPaymentDeadline, testLimitOrders)
+
+	return &fixture{
+		Assertions:   assert.New(t),
+		orderService: orderService,
+		executor:     executor,
+	}
+}
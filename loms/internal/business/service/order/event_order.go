@@ -2,64 +2,131 @@ package order
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"route256/loms/internal/domain"
+	"route256/loms/internal/infra/tracing"
+	eventsv1 "route256/loms/internal/pb/events/v1"
 	"strconv"
-	"time"
 
+	"github.com/google/uuid"
 	"github.com/opentracing/opentracing-go"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
-type orderEvent struct {
-	OrderID int64     `json:"order_id"`
-	Status  string    `json:"status"`
-	Moment  time.Time `json:"moment"`
+const (
+	orderEventItemsCancelled = eventsv1.OrderEventType_ORDER_EVENT_TYPE_ITEMS_CANCELLED
+	orderEventItemsReturned  = eventsv1.OrderEventType_ORDER_EVENT_TYPE_ITEMS_RETURNED
+)
+
+func (s *Service) prepareOrderEvent(
+	ctx context.Context,
+	orderID int64,
+	order domain.Order,
+	status domain.OrderStatus) (domain.Event, error) {
+	return s.prepareEvent(ctx, orderID, order, status, eventsv1.OrderEventType_ORDER_EVENT_TYPE_STATUS_CHANGED, order.Items)
 }
 
-func (s *Service) prepareOrderEvent(orderID int64, status domain.OrderStatus) (domain.Event, error) {
-	payload := orderEvent{
-		OrderID: orderID,
-		Status:  string(status),
-		Moment:  time.Now(),
+func (s *Service) prepareEvent(
+	ctx context.Context,
+	orderID int64,
+	order domain.Order,
+	status domain.OrderStatus,
+	eventType eventsv1.OrderEventType,
+	items []domain.Item) (domain.Event, error) {
+	payload := &eventsv1.OrderEvent{
+		EventId:        uuid.NewString(),
+		Type:           eventType,
+		Version:        eventsv1.OrderEventVersion_ORDER_EVENT_VERSION_1,
+		OrderId:        orderID,
+		UserId:         order.UserID,
+		Status:         string(status),
+		PreviousStatus: string(order.Status),
+		Items:          make([]*eventsv1.OrderEventItem, len(items)),
+		Moment:         timestamppb.Now(),
+	}
+
+	for idx, item := range items {
+		payload.Items[idx] = &eventsv1.OrderEventItem{
+			Sku:   int64(item.Sku),
+			Count: item.Count,
+		}
 	}
 
-	data, err := json.Marshal(payload)
+	data, err := proto.Marshal(payload)
 	if err != nil {
-		return domain.Event{}, fmt.Errorf("json.Marshal: failed to marshal payload: %w", err)
+		return domain.Event{}, fmt.Errorf("proto.Marshal: failed to marshal payload: %w", err)
 	}
 
 	event := domain.Event{
-		Topic:   s.orderTopic,
-		Key:     strconv.FormatInt(orderID, 10),
-		Payload: data,
-		Status:  domain.EventStatusNew,
+		Topic:        s.orderTopic,
+		Key:          strconv.FormatInt(orderID, 10),
+		Payload:      data,
+		Status:       domain.EventStatusNew,
+		TraceContext: tracing.Inject(ctx),
+		RequestID:    tracing.RequestIDFromContext(ctx),
+		EventID:      payload.EventId,
 	}
 
 	return event, nil
 }
 
-func (s *Service) createEvent(ctx context.Context, orderID int64, status domain.OrderStatus) error {
+func (s *Service) createEvent(ctx context.Context, orderID int64, order domain.Order, status domain.OrderStatus) error {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "orderService.createEvent")
 	defer span.Finish()
 
-	event, err := s.prepareOrderEvent(orderID, status)
+	event, err := s.prepareOrderEvent(ctx, orderID, order, status)
 	if err != nil {
 		return fmt.Errorf("prepareOrderEvent: %w", err)
 	}
 
 	if repoErr := s.eventRepository.CreateEvent(ctx, event); repoErr != nil {
-		return fmt.Errorf("eventRepository.CreateEventt: %w", err)
+		return fmt.Errorf("eventRepository.CreateEvent: %w", repoErr)
+	}
+
+	s.recordStatus(ctx, orderID, status)
+
+	return nil
+}
+
+func (s *Service) createItemsEvent(
+	ctx context.Context,
+	orderID int64,
+	order domain.Order,
+	eventType eventsv1.OrderEventType,
+	items []domain.Item) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "orderService.createItemsEvent")
+	defer span.Finish()
+
+	event, err := s.prepareEvent(ctx, orderID, order, order.Status, eventType, items)
+	if err != nil {
+		return fmt.Errorf("prepareEvent: %w", err)
+	}
+
+	if repoErr := s.eventRepository.CreateEvent(ctx, event); repoErr != nil {
+		return fmt.Errorf("eventRepository.CreateEvent: %w", repoErr)
 	}
 
 	return nil
 }
 
-func (s *Service) setStatusAndCreateEvent(ctx context.Context, orderID int64, status domain.OrderStatus) error {
+func (s *Service) changeStatus(ctx context.Context, orderID int64, order domain.Order, status domain.OrderStatus) error {
+	if err := s.setStatusAndCreateEvent(ctx, orderID, order, status); err != nil {
+		return fmt.Errorf("setStatusAndCreateEvent: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) setStatusAndCreateEvent(
+	ctx context.Context,
+	orderID int64,
+	order domain.Order,
+	status domain.OrderStatus) error {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "orderService.setStatusAndCreateEvent")
 	defer span.Finish()
 
-	event, err := s.prepareOrderEvent(orderID, status)
+	event, err := s.prepareOrderEvent(ctx, orderID, order, status)
 	if err != nil {
 		return fmt.Errorf("prepareOrderEvent: %w", err)
 	}
@@ -68,5 +135,7 @@ func (s *Service) setStatusAndCreateEvent(ctx context.Context, orderID int64, st
 		return fmt.Errorf("orderRepository.SetStatusAndCreateEvent: %w", repoErr)
 	}
 
+	s.recordStatus(ctx, orderID, status)
+
 	return nil
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"route256/loms/internal/domain"
+	"route256/loms/internal/infra/sharding"
 
 	"github.com/opentracing/opentracing-go"
 )
@@ -12,32 +13,40 @@ func (s *Service) OrderCancel(ctx context.Context, orderID int64) error {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "orderService.OrderCancel")
 	defer span.Finish()
 
-	err := s.txManagerMaster.ReadCommitted(ctx, func(ctx context.Context) error {
-		order, err := s.orderRepository.GetByOrderIDForUpdate(ctx, orderID)
-		if err != nil {
-			return fmt.Errorf("orderRepository.GetByOrderIDForUpdate: %w", err)
-		}
+	ctx = sharding.WithID(ctx, orderID)
 
-		switch order.Status {
-		case domain.OrderStatusCancelled:
-			return nil
-		case domain.OrderStatusFailed, domain.OrderStatusPayed:
-			return domain.ErrCancelOrder
-		}
+	err := s.readCommitted(ctx, func(ctx context.Context) error {
+		return s.cancelOrder(ctx, orderID)
+	})
 
-		if err = s.stockService.ReserveCancel(ctx, order.Items); err != nil {
-			return fmt.Errorf("stockService.ReserveRemove: %w", err)
-		}
+	if err != nil {
+		return fmt.Errorf("txManager.ReadCommitted: %w", err)
+	}
 
-		if err = s.setStatusAndCreateEvent(ctx, orderID, domain.OrderStatusCancelled); err != nil {
-			return fmt.Errorf("setStatusAndCreateEvent: %w", err)
-		}
+	return nil
+}
 
-		return nil
-	})
+func (s *Service) cancelOrder(ctx context.Context, orderID int64) error {
+	order, err := s.orderRepository.GetByOrderIDForUpdate(ctx, orderID)
+	if err != nil {
+		return fmt.Errorf("orderRepository.GetByOrderIDForUpdate: %w", err)
+	}
 
+	if err = s.orderStates.Fire(ctx, orderID, order, domain.OrderActionCancel, s.cancelReservation); err != nil {
+		return fmt.Errorf("orderStates.Fire: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) cancelReservation(ctx context.Context, orderID int64, _ domain.Order, _ domain.OrderStatus) error {
+	allocations, err := s.orderRepository.GetAllocationsByOrderID(ctx, orderID)
 	if err != nil {
-		return fmt.Errorf("txManager.ReadCommitted: %w", err)
+		return fmt.Errorf("orderRepository.GetAllocationsByOrderID: %w", err)
+	}
+
+	if err = s.changeStock(ctx, domain.StockMovementReserveCancel, orderID, allocations, s.stockService.ReserveCancel); err != nil {
+		return fmt.Errorf("stockService.ReserveCancel: %w", err)
 	}
 
 	return nil
@@ -0,0 +1,71 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"route256/loms/internal/domain"
+	"route256/loms/internal/infra/sharding"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+func (s *Service) OrderCancelItems(ctx context.Context, orderID int64, items []domain.Item) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "orderService.OrderCancelItems")
+	defer span.Finish()
+
+	ctx = sharding.WithID(ctx, orderID)
+	items = domain.MergeItems(items)
+
+	err := s.readCommitted(ctx, func(ctx context.Context) error {
+		order, err := s.orderRepository.GetByOrderIDForUpdate(ctx, orderID)
+		if err != nil {
+			return fmt.Errorf("orderRepository.GetByOrderIDForUpdate: %w", err)
+		}
+
+		if err = s.orderStates.Validate(order.Status, domain.OrderActionCancelItems); err != nil {
+			return err
+		}
+
+		remaining, err := subtractItems(order.Items, items)
+		if err != nil {
+			return err
+		}
+
+		released, err := s.releaseAllocations(ctx, orderID, items)
+		if err != nil {
+			return err
+		}
+
+		if err = s.changeStock(ctx, domain.StockMovementReserveCancel, orderID, released, s.stockService.ReserveCancel); err != nil {
+			return fmt.Errorf("stockService.ReserveCancel: %w", err)
+		}
+
+		if err = s.orderRepository.CancelOrderItems(ctx, orderID, items); err != nil {
+			return fmt.Errorf("orderRepository.CancelOrderItems: %w", err)
+		}
+
+		if err = s.orderRepository.DecreaseOrderAllocations(ctx, orderID, released); err != nil {
+			return fmt.Errorf("orderRepository.DecreaseOrderAllocations: %w", err)
+		}
+
+		if len(remaining) == 0 {
+			if err = s.orderStates.Fire(ctx, orderID, order, domain.OrderActionCancel); err != nil {
+				return fmt.Errorf("orderStates.Fire: %w", err)
+			}
+
+			return nil
+		}
+
+		if err = s.createItemsEvent(ctx, orderID, order, orderEventItemsCancelled, items); err != nil {
+			return fmt.Errorf("createItemsEvent: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("txManager.ReadCommitted: %w", err)
+	}
+
+	return nil
+}
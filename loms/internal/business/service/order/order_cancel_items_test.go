@@ -0,0 +1,318 @@
+package order_test
+
+import (
+	"context"
+	"fmt"
+	"route256/loms/internal/domain"
+	txmanager "route256/loms/internal/infra/tx_manager"
+	eventsv1 "route256/loms/internal/pb/events/v1"
+	testhelpers "route256/loms/internal/tool"
+	"testing"
+
+	"github.com/gojuno/minimock/v3"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestOrderCancelItems(t *testing.T) {
+	t.Parallel()
+
+	testOrderID := int64(12345)
+	testOrder := domain.Order{
+		UserID: 1,
+		Status: domain.OrderStatusAwaitingPayment,
+		Items: []domain.Item{
+			{Sku: 1001, Count: 2},
+			{Sku: 1002, Count: 1},
+		},
+	}
+
+	testAllocations := []domain.Allocation{
+		{Sku: 1001, WarehouseID: 1, Count: 1},
+		{Sku: 1001, WarehouseID: 2, Count: 1},
+		{Sku: 1002, WarehouseID: 1, Count: 1},
+	}
+
+	partialItems := []domain.Item{{Sku: 1001, Count: 1}}
+	partialReleased := []domain.Allocation{{Sku: 1001, WarehouseID: 2, Count: 1}}
+	allItems := []domain.Item{
+		{Sku: 1001, Count: 2},
+		{Sku: 1002, Count: 1},
+	}
+	allReleased := []domain.Allocation{
+		{Sku: 1001, WarehouseID: 2, Count: 1},
+		{Sku: 1001, WarehouseID: 1, Count: 1},
+		{Sku: 1002, WarehouseID: 1, Count: 1},
+	}
+
+	type mocks struct {
+		mockGetByOrderIDForUpdate    testhelpers.NeedCallWithErr
+		mockGetAllocationsByOrderID  testhelpers.NeedCallWithErr
+		mockReserveCancel            testhelpers.NeedCallWithErr
+		mockCancelOrderItems         testhelpers.NeedCallWithErr
+		mockDecreaseOrderAllocations testhelpers.NeedCallWithErr
+		mockCreateEvent              testhelpers.NeedCallWithErr
+		mockSetStatusAndCreateEvent  testhelpers.NeedCallWithErr
+	}
+
+	testCases := []struct {
+		name        string
+		order       domain.Order
+		items       []domain.Item
+		merged      []domain.Item
+		released    []domain.Allocation
+		mocks       mocks
+		expectedErr error
+	}{
+		{
+			name:     "success: orderservice.OrderCancelItems partial cancel",
+			order:    testOrder,
+			items:    partialItems,
+			released: partialReleased,
+			mocks: mocks{
+				mockGetByOrderIDForUpdate:    testhelpers.NewNeedCallWithErr(nil),
+				mockGetAllocationsByOrderID:  testhelpers.NewNeedCallWithErr(nil),
+				mockReserveCancel:            testhelpers.NewNeedCallWithErr(nil),
+				mockCancelOrderItems:         testhelpers.NewNeedCallWithErr(nil),
+				mockDecreaseOrderAllocations: testhelpers.NewNeedCallWithErr(nil),
+				mockCreateEvent:              testhelpers.NewNeedCallWithErr(nil),
+			},
+			expectedErr: nil,
+		},
+		{
+			name:     "success: orderservice.OrderCancelItems all items cancels order",
+			order:    testOrder,
+			items:    allItems,
+			released: allReleased,
+			mocks: mocks{
+				mockGetByOrderIDForUpdate:    testhelpers.NewNeedCallWithErr(nil),
+				mockGetAllocationsByOrderID:  testhelpers.NewNeedCallWithErr(nil),
+				mockReserveCancel:            testhelpers.NewNeedCallWithErr(nil),
+				mockCancelOrderItems:         testhelpers.NewNeedCallWithErr(nil),
+				mockDecreaseOrderAllocations: testhelpers.NewNeedCallWithErr(nil),
+				mockSetStatusAndCreateEvent:  testhelpers.NewNeedCallWithErr(nil),
+			},
+			expectedErr: nil,
+		},
+		{
+			name:     "success: orderservice.OrderCancelItems duplicate sku is merged",
+			order:    testOrder,
+			items:    []domain.Item{{Sku: 1001, Count: 1}, {Sku: 1001, Count: 1}},
+			merged:   []domain.Item{{Sku: 1001, Count: 2}},
+			released: []domain.Allocation{{Sku: 1001, WarehouseID: 2, Count: 1}, {Sku: 1001, WarehouseID: 1, Count: 1}},
+			mocks: mocks{
+				mockGetByOrderIDForUpdate:    testhelpers.NewNeedCallWithErr(nil),
+				mockGetAllocationsByOrderID:  testhelpers.NewNeedCallWithErr(nil),
+				mockReserveCancel:            testhelpers.NewNeedCallWithErr(nil),
+				mockCancelOrderItems:         testhelpers.NewNeedCallWithErr(nil),
+				mockDecreaseOrderAllocations: testhelpers.NewNeedCallWithErr(nil),
+				mockCreateEvent:              testhelpers.NewNeedCallWithErr(nil),
+			},
+		},
+		{
+			name:  "fail: duplicate sku exceeds count in order",
+			order: testOrder,
+			items: []domain.Item{{Sku: 1002, Count: 1}, {Sku: 1002, Count: 1}},
+			mocks: mocks{
+				mockGetByOrderIDForUpdate: testhelpers.NewNeedCallWithErr(nil),
+			},
+			expectedErr: domain.ErrInvalidOrderItems,
+		},
+		{
+			name:     "fail: orderservice.OrderCancelItems GetByOrderIDForUpdate error",
+			order:    domain.Order{},
+			items:    partialItems,
+			released: partialReleased,
+			mocks: mocks{
+				mockGetByOrderIDForUpdate: testhelpers.NewNeedCallWithErr(testhelpers.ErrForTest),
+			},
+			expectedErr: testhelpers.ErrForTest,
+		},
+		{
+			name: "fail: order is not awaiting payment",
+			order: domain.Order{
+				UserID: 1,
+				Status: domain.OrderStatusPayed,
+				Items:  testOrder.Items,
+			},
+			items:    partialItems,
+			released: partialReleased,
+			mocks: mocks{
+				mockGetByOrderIDForUpdate: testhelpers.NewNeedCallWithErr(nil),
+			},
+			expectedErr: domain.ErrCancelItemsStatus,
+		},
+		{
+			name:  "fail: sku is not in order",
+			order: testOrder,
+			items: []domain.Item{{Sku: 9999, Count: 1}},
+			mocks: mocks{
+				mockGetByOrderIDForUpdate: testhelpers.NewNeedCallWithErr(nil),
+			},
+			expectedErr: domain.ErrInvalidOrderItems,
+		},
+		{
+			name:  "fail: count exceeds count in order",
+			order: testOrder,
+			items: []domain.Item{{Sku: 1002, Count: 2}},
+			mocks: mocks{
+				mockGetByOrderIDForUpdate: testhelpers.NewNeedCallWithErr(nil),
+			},
+			expectedErr: domain.ErrInvalidOrderItems,
+		},
+		{
+			name:     "fail: orderservice.OrderCancelItems ReserveCancel error",
+			order:    testOrder,
+			items:    partialItems,
+			released: partialReleased,
+			mocks: mocks{
+				mockGetByOrderIDForUpdate:   testhelpers.NewNeedCallWithErr(nil),
+				mockGetAllocationsByOrderID: testhelpers.NewNeedCallWithErr(nil),
+				mockReserveCancel:           testhelpers.NewNeedCallWithErr(testhelpers.ErrForTest),
+			},
+			expectedErr: testhelpers.ErrForTest,
+		},
+		{
+			name:     "fail: orderservice.OrderCancelItems CancelOrderItems error",
+			order:    testOrder,
+			items:    partialItems,
+			released: partialReleased,
+			mocks: mocks{
+				mockGetByOrderIDForUpdate:   testhelpers.NewNeedCallWithErr(nil),
+				mockGetAllocationsByOrderID: testhelpers.NewNeedCallWithErr(nil),
+				mockReserveCancel:           testhelpers.NewNeedCallWithErr(nil),
+				mockCancelOrderItems:        testhelpers.NewNeedCallWithErr(testhelpers.ErrForTest),
+			},
+			expectedErr: testhelpers.ErrForTest,
+		},
+		{
+			name:     "fail: orderservice.OrderCancelItems CreateEvent error",
+			order:    testOrder,
+			items:    partialItems,
+			released: partialReleased,
+			mocks: mocks{
+				mockGetByOrderIDForUpdate:    testhelpers.NewNeedCallWithErr(nil),
+				mockGetAllocationsByOrderID:  testhelpers.NewNeedCallWithErr(nil),
+				mockReserveCancel:            testhelpers.NewNeedCallWithErr(nil),
+				mockCancelOrderItems:         testhelpers.NewNeedCallWithErr(nil),
+				mockDecreaseOrderAllocations: testhelpers.NewNeedCallWithErr(nil),
+				mockCreateEvent:              testhelpers.NewNeedCallWithErr(testhelpers.ErrForTest),
+			},
+			expectedErr: testhelpers.ErrForTest,
+		},
+		{
+			name:  "fail: orderservice.OrderCancelItems GetAllocationsByOrderID error",
+			order: testOrder,
+			items: partialItems,
+			mocks: mocks{
+				mockGetByOrderIDForUpdate:   testhelpers.NewNeedCallWithErr(nil),
+				mockGetAllocationsByOrderID: testhelpers.NewNeedCallWithErr(testhelpers.ErrForTest),
+			},
+			expectedErr: testhelpers.ErrForTest,
+		},
+		{
+			name:     "fail: orderservice.OrderCancelItems DecreaseOrderAllocations error",
+			order:    testOrder,
+			items:    partialItems,
+			released: partialReleased,
+			mocks: mocks{
+				mockGetByOrderIDForUpdate:    testhelpers.NewNeedCallWithErr(nil),
+				mockGetAllocationsByOrderID:  testhelpers.NewNeedCallWithErr(nil),
+				mockReserveCancel:            testhelpers.NewNeedCallWithErr(nil),
+				mockCancelOrderItems:         testhelpers.NewNeedCallWithErr(nil),
+				mockDecreaseOrderAllocations: testhelpers.NewNeedCallWithErr(testhelpers.ErrForTest),
+			},
+			expectedErr: testhelpers.ErrForTest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			f := setUp(t)
+
+			ctx := context.Background()
+
+			f.txManager.ReadCommittedMock.Set(func(ctx context.Context, fn txmanager.Handler) error {
+				return fn(ctx)
+			})
+
+			if tc.mocks.mockGetByOrderIDForUpdate.NeedCall {
+				f.orderRepository.GetByOrderIDForUpdateMock.
+					Expect(minimock.AnyContext, testOrderID).
+					Return(tc.order, tc.mocks.mockGetByOrderIDForUpdate.Err)
+			}
+
+			if tc.mocks.mockGetAllocationsByOrderID.NeedCall {
+				f.orderRepository.GetAllocationsByOrderIDMock.
+					Expect(minimock.AnyContext, testOrderID).
+					Return(testAllocations, tc.mocks.mockGetAllocationsByOrderID.Err)
+			}
+
+			if tc.mocks.mockReserveCancel.NeedCall {
+				f.stockService.ReserveCancelMock.
+					Expect(minimock.AnyContext, testOrderID, tc.released).
+					Return(tc.mocks.mockReserveCancel.Err)
+			}
+
+			if tc.mocks.mockCancelOrderItems.NeedCall {
+				cancelled := tc.items
+				if tc.merged != nil {
+					cancelled = tc.merged
+				}
+
+				f.orderRepository.CancelOrderItemsMock.
+					Expect(minimock.AnyContext, testOrderID, cancelled).
+					Return(tc.mocks.mockCancelOrderItems.Err)
+			}
+
+			if tc.mocks.mockDecreaseOrderAllocations.NeedCall {
+				f.orderRepository.DecreaseOrderAllocationsMock.
+					Expect(minimock.AnyContext, testOrderID, tc.released).
+					Return(tc.mocks.mockDecreaseOrderAllocations.Err)
+			}
+
+			if tc.mocks.mockCreateEvent.NeedCall {
+				f.eventRepository.CreateEventMock.Set(func(_ context.Context, event domain.Event) error {
+					var payload eventsv1.OrderEvent
+					if err := proto.Unmarshal(event.Payload, &payload); err != nil {
+						return err
+					}
+					if payload.GetType() != eventsv1.OrderEventType_ORDER_EVENT_TYPE_ITEMS_CANCELLED {
+						return fmt.Errorf("unexpected event: got %v", payload.GetType())
+					}
+					if payload.GetOrderId() != testOrderID {
+						return fmt.Errorf("unexpected orderID: got %d", payload.GetOrderId())
+					}
+					if event.EventID == "" || event.EventID != payload.GetEventId() {
+						return fmt.Errorf("event id %q differs from payload event id %q", event.EventID, payload.GetEventId())
+					}
+
+					return tc.mocks.mockCreateEvent.Err
+				})
+			}
+
+			if tc.mocks.mockSetStatusAndCreateEvent.NeedCall {
+				f.orderRepository.SetStatusAndCreateEventMock.Set(func(_ context.Context, orderID int64, status domain.OrderStatus, _ domain.Event) error {
+					if orderID != testOrderID {
+						return fmt.Errorf("unexpected orderID: got %d", orderID)
+					}
+					if status != domain.OrderStatusCancelled {
+						return fmt.Errorf("unexpected status: got %v", status)
+					}
+
+					return tc.mocks.mockSetStatusAndCreateEvent.Err
+				})
+			}
+
+			err := f.executor.OrderCancelItems(ctx, testOrderID, tc.items)
+
+			if tc.expectedErr != nil {
+				f.Error(err)
+				f.ErrorContains(err, tc.expectedErr.Error())
+			} else {
+				f.NoError(err)
+			}
+		})
+	}
+}
@@ -19,6 +19,10 @@ func TestOrderCancel(t *testing.T) {
 		{Sku: 1001, Count: 2},
 		{Sku: 1002, Count: 1},
 	}
+	testAllocations := []domain.Allocation{
+		{Sku: 1001, WarehouseID: 1, Count: 2},
+		{Sku: 1002, WarehouseID: 1, Count: 1},
+	}
 
 	testOrder := domain.Order{
 		UserID: 1,
@@ -28,6 +32,7 @@ func TestOrderCancel(t *testing.T) {
 
 	type mocks struct {
 		mockGetByOrderIDForUpdate   testhelpers.NeedCallWithErr
+		mockGetAllocationsByOrderID testhelpers.NeedCallWithErr
 		mockReserveCancel           testhelpers.NeedCallWithErr
 		mockSetStatusAndCreateEvent testhelpers.NeedCallWithErr
 		mockReadCommitted           testhelpers.NeedCallWithErr
@@ -45,6 +50,7 @@ func TestOrderCancel(t *testing.T) {
 			order: testOrder,
 			mocks: mocks{
 				mockGetByOrderIDForUpdate:   testhelpers.NewNeedCallWithErr(nil),
+				mockGetAllocationsByOrderID: testhelpers.NewNeedCallWithErr(nil),
 				mockReserveCancel:           testhelpers.NewNeedCallWithErr(nil),
 				mockSetStatusAndCreateEvent: testhelpers.NewNeedCallWithErr(nil),
 				mockReadCommitted:           testhelpers.NewNeedCallWithErr(nil),
@@ -64,6 +70,18 @@ func TestOrderCancel(t *testing.T) {
 			},
 			expectedErr: domain.ErrCancelOrder,
 		},
+		{
+			name: "fail: refunded order is an illegal transition",
+			order: domain.Order{
+				UserID: 1,
+				Status: domain.OrderStatusRefunded,
+				Items:  testItems,
+			},
+			mocks: mocks{
+				mockGetByOrderIDForUpdate: testhelpers.NewNeedCallWithErr(nil),
+			},
+			expectedErr: domain.ErrOrderTransition,
+		},
 		{
 			name:  "fail: orderservice.OrderCancel GetByOrderID error",
 			order: domain.Order{},
@@ -72,13 +90,24 @@ func TestOrderCancel(t *testing.T) {
 			},
 			expectedErr: testhelpers.ErrForTest,
 		},
+		{
+			name:  "fail: orderservice.OrderCancel GetAllocationsByOrderID error",
+			order: testOrder,
+			mocks: mocks{
+				mockGetByOrderIDForUpdate:   testhelpers.NewNeedCallWithErr(nil),
+				mockGetAllocationsByOrderID: testhelpers.NewNeedCallWithErr(testhelpers.ErrForTest),
+				mockReadCommitted:           testhelpers.NewNeedCallWithErr(nil),
+			},
+			expectedErr: testhelpers.ErrForTest,
+		},
 		{
 			name:  "fail: orderservice.OrderCancel ReserveCancel error",
 			order: testOrder,
 			mocks: mocks{
-				mockGetByOrderIDForUpdate: testhelpers.NewNeedCallWithErr(nil),
-				mockReserveCancel:         testhelpers.NewNeedCallWithErr(testhelpers.ErrForTest),
-				mockReadCommitted:         testhelpers.NewNeedCallWithErr(nil),
+				mockGetByOrderIDForUpdate:   testhelpers.NewNeedCallWithErr(nil),
+				mockGetAllocationsByOrderID: testhelpers.NewNeedCallWithErr(nil),
+				mockReserveCancel:           testhelpers.NewNeedCallWithErr(testhelpers.ErrForTest),
+				mockReadCommitted:           testhelpers.NewNeedCallWithErr(nil),
 			},
 			expectedErr: testhelpers.ErrForTest,
 		},
@@ -87,6 +116,7 @@ func TestOrderCancel(t *testing.T) {
 			order: testOrder,
 			mocks: mocks{
 				mockGetByOrderIDForUpdate:   testhelpers.NewNeedCallWithErr(nil),
+				mockGetAllocationsByOrderID: testhelpers.NewNeedCallWithErr(nil),
 				mockReserveCancel:           testhelpers.NewNeedCallWithErr(nil),
 				mockSetStatusAndCreateEvent: testhelpers.NewNeedCallWithErr(testhelpers.ErrForTest),
 				mockReadCommitted:           testhelpers.NewNeedCallWithErr(nil),
@@ -125,9 +155,15 @@ func TestOrderCancel(t *testing.T) {
 					Return(tc.order, tc.mocks.mockGetByOrderIDForUpdate.Err)
 			}
 
+			if tc.mocks.mockGetAllocationsByOrderID.NeedCall {
+				f.orderRepository.GetAllocationsByOrderIDMock.
+					Expect(minimock.AnyContext, testOrderID).
+					Return(testAllocations, tc.mocks.mockGetAllocationsByOrderID.Err)
+			}
+
 			if tc.mocks.mockReserveCancel.NeedCall {
 				f.stockService.ReserveCancelMock.
-					Expect(minimock.AnyContext, tc.order.Items).
+					Expect(minimock.AnyContext, testOrderID, testAllocations).
 					Return(tc.mocks.mockReserveCancel.Err)
 			}
 
@@ -147,8 +183,7 @@ func TestOrderCancel(t *testing.T) {
 			err := f.executor.OrderCancel(ctx, testOrderID)
 
 			if tc.expectedErr != nil {
-				f.Error(err)
-				f.ErrorContains(err, tc.expectedErr.Error())
+				f.ErrorIs(err, tc.expectedErr)
 			} else {
 				f.NoError(err)
 			}
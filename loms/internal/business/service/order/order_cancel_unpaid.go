@@ -0,0 +1,60 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"route256/loms/internal/infra/logger"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+// Each expired order is claimed and cancelled in its own transaction, so replicas
+// skip the locked row. Orders that fail to cancel are excluded for the rest of the run.
+func (s *Service) CancelUnpaidOrders(ctx context.Context, paymentDeadline time.Duration, limit int32) (int, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "orderService.CancelUnpaidOrders")
+	defer span.Finish()
+
+	var (
+		cancelled int
+		failed    []int64
+	)
+
+	for cancelled < int(limit) {
+		var (
+			orderID int64
+			claimed bool
+		)
+
+		err := s.readCommitted(ctx, func(ctx context.Context) error {
+			claimed = false
+
+			orderIDs, err := s.orderRepository.GetExpiredUnpaidOrderIDsForUpdate(ctx, paymentDeadline, failed, 1)
+			if err != nil {
+				return fmt.Errorf("orderRepository.GetExpiredUnpaidOrderIDsForUpdate: %w", err)
+			}
+
+			if len(orderIDs) == 0 {
+				return nil
+			}
+
+			orderID, claimed = orderIDs[0], true
+
+			return s.cancelOrder(ctx, orderID)
+		})
+
+		switch {
+		case err != nil && !claimed:
+			return cancelled, err
+		case err != nil:
+			logger.Errorf(ctx, "cancel unpaid order %v: %v", orderID, err)
+			failed = append(failed, orderID)
+		case !claimed:
+			return cancelled, nil
+		default:
+			cancelled++
+		}
+	}
+
+	return cancelled, nil
+}
@@ -0,0 +1,152 @@
+package order_test
+
+import (
+	"context"
+	"route256/loms/internal/domain"
+	txmanager "route256/loms/internal/infra/tx_manager"
+	testhelpers "route256/loms/internal/tool"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCancelUnpaidOrders(t *testing.T) {
+	t.Parallel()
+
+	testDeadline := 15 * time.Minute
+	testLimit := int32(10)
+	testItems := []domain.Item{
+		{Sku: 1001, Count: 2},
+	}
+	testAllocations := []domain.Allocation{
+		{Sku: 1001, WarehouseID: 1, Count: 2},
+	}
+
+	testCases := []struct {
+		name              string
+		limit             int32
+		orderIDs          []int64
+		getExpiredErr     error
+		reserveCancelErrs map[int64]error
+		expectedErr       error
+		expectedCancelled []int64
+	}{
+		{
+			name:              "success: orderservice.CancelUnpaidOrders cancels expired orders",
+			orderIDs:          []int64{1, 2},
+			expectedCancelled: []int64{1, 2},
+		},
+		{
+			name: "success: orderservice.CancelUnpaidOrders no expired orders",
+		},
+		{
+			name:              "success: orderservice.CancelUnpaidOrders skips failed order and cancels the rest",
+			orderIDs:          []int64{1, 2, 3},
+			reserveCancelErrs: map[int64]error{1: testhelpers.ErrForTest},
+			expectedCancelled: []int64{2, 3},
+		},
+		{
+			name:              "success: orderservice.CancelUnpaidOrders stops at the limit",
+			limit:             2,
+			orderIDs:          []int64{1, 2, 3},
+			expectedCancelled: []int64{1, 2},
+		},
+		{
+			name:              "success: orderservice.CancelUnpaidOrders every order fails",
+			orderIDs:          []int64{1},
+			reserveCancelErrs: map[int64]error{1: testhelpers.ErrForTest},
+		},
+		{
+			name:          "fail: orderservice.CancelUnpaidOrders GetExpiredUnpaidOrderIDsForUpdate error",
+			getExpiredErr: testhelpers.ErrForTest,
+			expectedErr:   testhelpers.ErrForTest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			f := setUp(t)
+			ctx := context.Background()
+
+			limit := testLimit
+			if tc.limit > 0 {
+				limit = tc.limit
+			}
+
+			var (
+				mu        sync.Mutex
+				cancelled []int64
+			)
+
+			f.txManager.ReadCommittedMock.Set(func(ctx context.Context, fn txmanager.Handler) error {
+				return fn(ctx)
+			})
+			f.orderRepository.GetExpiredUnpaidOrderIDsForUpdateMock.Set(
+				func(_ context.Context, deadline time.Duration, excludedIDs []int64, rowLimit int32) ([]int64, error) {
+					f.Equal(testDeadline, deadline)
+					f.Equal(int32(1), rowLimit)
+
+					if tc.getExpiredErr != nil {
+						return nil, tc.getExpiredErr
+					}
+
+					mu.Lock()
+					defer mu.Unlock()
+
+					for _, orderID := range tc.orderIDs {
+						if !slices.Contains(excludedIDs, orderID) && !slices.Contains(cancelled, orderID) {
+							return []int64{orderID}, nil
+						}
+					}
+
+					return nil, nil
+				})
+
+			if len(tc.orderIDs) > 0 {
+				f.orderRepository.GetByOrderIDForUpdateMock.Set(func(_ context.Context, _ int64) (domain.Order, error) {
+					return domain.Order{
+						UserID: 1,
+						Status: domain.OrderStatusAwaitingPayment,
+						Items:  testItems,
+					}, nil
+				})
+				f.orderRepository.GetAllocationsByOrderIDMock.Return(testAllocations, nil)
+				f.stockService.ReserveCancelMock.Set(
+					func(_ context.Context, orderID int64, allocations []domain.Allocation) error {
+						f.Equal(testAllocations, allocations)
+						return tc.reserveCancelErrs[orderID]
+					})
+			}
+
+			if len(tc.expectedCancelled) > 0 {
+				f.orderRepository.SetStatusAndCreateEventMock.Set(
+					func(_ context.Context, orderID int64, status domain.OrderStatus, _ domain.Event) error {
+						f.Equal(domain.OrderStatusCancelled, status)
+
+						mu.Lock()
+						defer mu.Unlock()
+						cancelled = append(cancelled, orderID)
+
+						return nil
+					})
+			}
+
+			count, err := f.executor.CancelUnpaidOrders(ctx, testDeadline, limit)
+
+			if tc.expectedErr != nil {
+				f.ErrorIs(err, tc.expectedErr)
+				f.Zero(count)
+				return
+			}
+
+			f.NoError(err)
+			f.Equal(len(tc.expectedCancelled), count)
+
+			slices.Sort(cancelled)
+			f.Equal(tc.expectedCancelled, cancelled)
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"route256/loms/internal/domain"
+	"route256/loms/internal/infra/sharding"
 
 	"github.com/opentracing/opentracing-go"
 )
@@ -13,9 +14,16 @@ func (s *Service) OrderCreate(ctx context.Context, order domain.Order) (int64, e
 	span, ctx := opentracing.StartSpanFromContext(ctx, "orderService.OrderCreate")
 	defer span.Finish()
 
+	ctx = sharding.WithUserID(ctx, order.UserID)
+
+	order, err := priceOrder(order)
+	if err != nil {
+		return 0, err
+	}
+
 	var orderID int64
 
-	if err := s.txManagerMaster.ReadCommitted(ctx, func(ctx context.Context) error {
+	if err = s.readCommitted(ctx, func(ctx context.Context) error {
 		var err error
 		orderID, err = s.orderRepository.CreateOrder(ctx, order.UserID)
 		if err != nil {
@@ -26,7 +34,7 @@ func (s *Service) OrderCreate(ctx context.Context, order domain.Order) (int64, e
 			return fmt.Errorf("orderRepository.CreateOrderItems: %w", err)
 		}
 
-		if err := s.createEvent(ctx, orderID, domain.OrderStatusNew); err != nil {
+		if err := s.createEvent(ctx, orderID, order, domain.OrderStatusNew); err != nil {
 			return fmt.Errorf("createEvent: %w", err)
 		}
 
@@ -35,20 +43,27 @@ func (s *Service) OrderCreate(ctx context.Context, order domain.Order) (int64, e
 		return 0, fmt.Errorf("OrderCreate failed: %w", err)
 	}
 
-	if err := s.txManagerMaster.ReadCommitted(ctx, func(ctx context.Context) error {
-		if err := s.stockService.Reserve(ctx, order.Items); err != nil {
+	order.Status = domain.OrderStatusNew
+
+	if err := s.readCommitted(ctx, func(ctx context.Context) error {
+		allocations, err := s.reserveStock(ctx, orderID, order.Items)
+		if err != nil {
 			return fmt.Errorf("stockService.Reserve: %w", err)
 		}
 
-		if err := s.setStatusAndCreateEvent(ctx, orderID, domain.OrderStatusAwaitingPayment); err != nil {
-			return fmt.Errorf("setStatusAndCreateEvent: %w", err)
+		if err := s.orderRepository.CreateOrderAllocations(ctx, orderID, allocations); err != nil {
+			return fmt.Errorf("orderRepository.CreateOrderAllocations: %w", err)
+		}
+
+		if err := s.orderStates.Fire(ctx, orderID, order, domain.OrderActionReserve); err != nil {
+			return fmt.Errorf("orderStates.Fire: %w", err)
 		}
 
 		return nil
 	}); err != nil {
 		if errors.Is(err, domain.ErrNotEnoughStock) || errors.Is(err, domain.ErrStockNotFound) {
-			if errStatus := s.setStatusAndCreateEvent(ctx, orderID, domain.OrderStatusFailed); errStatus != nil {
-				return 0, fmt.Errorf("setStatusAndCreateEvent: %w", errStatus)
+			if errStatus := s.orderStates.Fire(ctx, orderID, order, domain.OrderActionFail); errStatus != nil {
+				return 0, fmt.Errorf("orderStates.Fire: %w", errStatus)
 			}
 		}
 
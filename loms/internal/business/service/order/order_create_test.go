@@ -17,13 +17,15 @@ func TestOrderCreate(t *testing.T) {
 	t.Parallel()
 
 	testOrderID := int64(12345)
-	testItems := []domain.Item{{Sku: 1001, Count: 2}}
+	testItems := []domain.Item{{Sku: 1001, Count: 2, Price: 150, Currency: domain.DefaultCurrency}}
 	testOrder := domain.Order{UserID: 1, Items: testItems}
+	testAllocations := []domain.Allocation{{Sku: 1001, WarehouseID: 1, Count: 2}}
 
 	type mocks struct {
 		createOrder             testhelpers.NeedCallWithErr
 		createOrderItems        testhelpers.NeedCallWithErr
 		reserve                 testhelpers.NeedCallWithErr
+		createOrderAllocations  testhelpers.NeedCallWithErr
 		setStatusAndCreateEvent testhelpers.NeedCallWithErr
 		sentEvent               testhelpers.NeedCallWithErr
 	}
@@ -41,6 +43,7 @@ func TestOrderCreate(t *testing.T) {
 				createOrder:             testhelpers.NewNeedCallWithErr(nil),
 				createOrderItems:        testhelpers.NewNeedCallWithErr(nil),
 				reserve:                 testhelpers.NewNeedCallWithErr(nil),
+				createOrderAllocations:  testhelpers.NewNeedCallWithErr(nil),
 				setStatusAndCreateEvent: testhelpers.NewNeedCallWithErr(nil),
 				sentEvent:               testhelpers.NewNeedCallWithErr(nil),
 			},
@@ -96,6 +99,7 @@ func TestOrderCreate(t *testing.T) {
 				createOrder:             testhelpers.NewNeedCallWithErr(nil),
 				createOrderItems:        testhelpers.NewNeedCallWithErr(nil),
 				reserve:                 testhelpers.NewNeedCallWithErr(nil),
+				createOrderAllocations:  testhelpers.NewNeedCallWithErr(nil),
 				setStatusAndCreateEvent: testhelpers.NewNeedCallWithErr(testhelpers.ErrForTest),
 				sentEvent:               testhelpers.NewNeedCallWithErr(nil),
 			},
@@ -103,6 +107,19 @@ func TestOrderCreate(t *testing.T) {
 			expectedID:     0,
 			expectedStatus: domain.EventStatusNew,
 		},
+		{
+			name: "fail: CreateOrderAllocations returns error",
+			mocks: mocks{
+				createOrder:            testhelpers.NewNeedCallWithErr(nil),
+				createOrderItems:       testhelpers.NewNeedCallWithErr(nil),
+				reserve:                testhelpers.NewNeedCallWithErr(nil),
+				createOrderAllocations: testhelpers.NewNeedCallWithErr(testhelpers.ErrForTest),
+				sentEvent:              testhelpers.NewNeedCallWithErr(nil),
+			},
+			expectedErr:    "orderRepository.CreateOrderAllocations",
+			expectedID:     0,
+			expectedStatus: domain.EventStatusNew,
+		},
 		{
 			name: "fail: eventRepository.CreateEvent error",
 			mocks: mocks{
@@ -142,8 +159,14 @@ func TestOrderCreate(t *testing.T) {
 
 			if tc.mocks.reserve.NeedCall {
 				f.stockService.ReserveMock.
-					Expect(minimock.AnyContext, testOrder.Items).
-					Return(tc.mocks.reserve.Err)
+					Expect(minimock.AnyContext, testOrderID, testOrder.Items).
+					Return(testAllocations, tc.mocks.reserve.Err)
+			}
+
+			if tc.mocks.createOrderAllocations.NeedCall {
+				f.orderRepository.CreateOrderAllocationsMock.
+					Expect(minimock.AnyContext, testOrderID, testAllocations).
+					Return(tc.mocks.createOrderAllocations.Err)
 			}
 
 			if tc.mocks.setStatusAndCreateEvent.NeedCall {
@@ -184,3 +207,40 @@ func TestOrderCreate(t *testing.T) {
 		})
 	}
 }
+
+func TestOrderCreateInvalidPricing(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		items       []domain.Item
+		expectedErr error
+	}{
+		{
+			name: "fail: items in different currencies",
+			items: []domain.Item{
+				{Sku: 1001, Count: 2, Price: 150, Currency: "RUB"},
+				{Sku: 1002, Count: 1, Price: 300, Currency: "USD"},
+			},
+			expectedErr: domain.ErrOrderCurrency,
+		},
+		{
+			name:        "fail: negative item price",
+			items:       []domain.Item{{Sku: 1001, Count: 2, Price: -1}},
+			expectedErr: domain.ErrInvalidOrderItems,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			f := setUp(t)
+
+			id, err := f.executor.OrderCreate(context.Background(), domain.Order{UserID: 1, Items: tc.items})
+
+			f.ErrorIs(err, tc.expectedErr)
+			f.Zero(id)
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"route256/loms/internal/domain"
+	"route256/loms/internal/infra/sharding"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+func (s *Service) OrderHistory(ctx context.Context, orderID int64) ([]domain.OrderStatusChange, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "orderService.OrderHistory")
+	defer span.Finish()
+
+	ctx = sharding.WithID(ctx, orderID)
+
+	history, err := s.orderRepository.GetStatusHistory(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("orderRepository.GetStatusHistory: %w", err)
+	}
+
+	return history, nil
+}
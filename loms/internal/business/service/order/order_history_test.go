@@ -0,0 +1,89 @@
+package order_test
+
+import (
+	"context"
+	"route256/loms/internal/domain"
+	testhelpers "route256/loms/internal/tool"
+	"testing"
+	"time"
+
+	"github.com/gojuno/minimock/v3"
+)
+
+func TestOrderHistory(t *testing.T) {
+	t.Parallel()
+
+	testOrderID := int64(12345)
+	testCreatedAt := time.Date(2025, 7, 15, 12, 0, 0, 0, time.UTC)
+
+	testHistory := []domain.OrderStatusChange{
+		{Status: domain.OrderStatusNew, ChangedAt: testCreatedAt},
+		{Status: domain.OrderStatusAwaitingPayment, ChangedAt: testCreatedAt.Add(time.Second)},
+		{Status: domain.OrderStatusPayed, ChangedAt: testCreatedAt.Add(time.Minute)},
+	}
+
+	type mocks struct {
+		mockGetStatusHistory testhelpers.NeedCallWithErr
+	}
+
+	testCases := []struct {
+		name            string
+		mocks           mocks
+		expectedErr     error
+		expectedHistory []domain.OrderStatusChange
+	}{
+		{
+			name: "success: orderservice.OrderHistory",
+			mocks: mocks{
+				mockGetStatusHistory: testhelpers.NewNeedCallWithErr(nil),
+			},
+			expectedErr:     nil,
+			expectedHistory: testHistory,
+		},
+		{
+			name: "fail: orderservice.OrderHistory order not found",
+			mocks: mocks{
+				mockGetStatusHistory: testhelpers.NewNeedCallWithErr(domain.ErrOrderNotFound),
+			},
+			expectedErr: domain.ErrOrderNotFound,
+		},
+		{
+			name: "fail: orderservice.OrderHistory GetStatusHistory error",
+			mocks: mocks{
+				mockGetStatusHistory: testhelpers.NewNeedCallWithErr(testhelpers.ErrForTest),
+			},
+			expectedErr: testhelpers.ErrForTest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			f := setUp(t)
+
+			if tc.mocks.mockGetStatusHistory.NeedCall {
+				history := testHistory
+				if tc.mocks.mockGetStatusHistory.Err != nil {
+					history = nil
+				}
+
+				f.orderRepository.GetStatusHistoryMock.
+					Expect(minimock.AnyContext, testOrderID).
+					Return(history, tc.mocks.mockGetStatusHistory.Err)
+			}
+
+			history, err := f.executor.OrderHistory(ctx, testOrderID)
+
+			if tc.expectedErr != nil {
+				f.Error(err)
+				f.ErrorIs(err, tc.expectedErr)
+				f.Nil(history)
+			} else {
+				f.NoError(err)
+				f.Equal(tc.expectedHistory, history)
+			}
+		})
+	}
+}
@@ -0,0 +1,344 @@
+package order_test
+
+import (
+	"context"
+	"route256/loms/internal/adapter/payment/fake"
+	inmemoryorder "route256/loms/internal/adapter/repository/in_memory/order"
+	inmemoryoutbox "route256/loms/internal/adapter/repository/in_memory/outbox"
+	inmemorypayment "route256/loms/internal/adapter/repository/in_memory/payment"
+	inmemorystock "route256/loms/internal/adapter/repository/in_memory/stock"
+	"route256/loms/internal/adapter/repository/in_memory/storage"
+	orderservice "route256/loms/internal/business/service/order"
+	"route256/loms/internal/business/service/order/watch"
+	stockservice "route256/loms/internal/business/service/stock"
+	"route256/loms/internal/business/service/stock/allocation"
+	"route256/loms/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type inMemoryFixture struct {
+	*require.Assertions
+	stockRepository   *inmemorystock.Repository
+	outboxRepository  *inmemoryoutbox.Repository
+	paymentRepository *inmemorypayment.Repository
+
+	executor *orderservice.Service
+}
+
+func setUpInMemory(t *testing.T) *inMemoryFixture {
+	return setUpInMemoryWithPayment(t, fake.ModeApprove)
+}
+
+func setUpInMemoryWithPayment(t *testing.T, mode fake.Mode) *inMemoryFixture {
+	store := storage.New()
+	txManager := storage.NewTxManager(store)
+
+	stockRepository, err := inmemorystock.New(context.Background(), store)
+	require.NoError(t, err)
+
+	outboxRepository := inmemoryoutbox.New(store)
+	orderRepository := inmemoryorder.New(store, outboxRepository)
+	paymentRepository := inmemorypayment.New(store)
+	paymentGateway := fake.New(mode, 10*time.Millisecond)
+
+	stockService := stockservice.New(stockRepository, allocation.NewPriorityStrategy([]int64{domain.DefaultWarehouseID}), txManager)
+	statusBroadcaster := watch.New(testWatchBufferSize, watch.SlowConsumerDisconnect)
+
+	return &inMemoryFixture{
+		Assertions:        require.New(t),
+		stockRepository:   stockRepository,
+		outboxRepository:  outboxRepository,
+		paymentRepository: paymentRepository,
+		executor: orderservice.New("test-topic", orderRepository, outboxRepository, stockService, txManager,
+			statusBroadcaster, paymentRepository, paymentGateway, false),
+	}
+}
+
+func (f *inMemoryFixture) stock(ctx context.Context, sku domain.Sku) domain.Stock {
+	stock, err := f.stockRepository.GetStockBySku(ctx, sku)
+	f.NoError(err)
+
+	return stock
+}
+
+func TestInMemoryOrderCreateAndPay(t *testing.T) {
+	t.Parallel()
+
+	f := setUpInMemory(t)
+	ctx := context.Background()
+
+	testSku := domain.Sku(1076963)
+	before := f.stock(ctx, testSku)
+
+	orderID, err := f.executor.OrderCreate(ctx, domain.Order{
+		UserID: 1,
+		Items:  []domain.Item{{Sku: testSku, Count: 5}},
+	})
+	f.NoError(err)
+
+	order, err := f.executor.OrderInfo(ctx, orderID)
+	f.NoError(err)
+	f.Equal(domain.OrderStatusAwaitingPayment, order.Status)
+	f.Equal(before.Reserved+5, f.stock(ctx, testSku).Reserved)
+
+	f.NoError(f.executor.OrderPay(ctx, orderID))
+
+	order, err = f.executor.OrderInfo(ctx, orderID)
+	f.NoError(err)
+	f.Equal(domain.OrderStatusPayed, order.Status)
+
+	after := f.stock(ctx, testSku)
+	f.Equal(before.TotalCount-5, after.TotalCount)
+	f.Equal(before.Reserved, after.Reserved)
+
+	events, err := f.outboxRepository.FetchNextMessages(ctx, 10)
+	f.NoError(err)
+	f.Len(events, 3)
+}
+
+func TestInMemoryOrderCreateRollsBackReserve(t *testing.T) {
+	t.Parallel()
+
+	f := setUpInMemory(t)
+	ctx := context.Background()
+
+	availableSku := domain.Sku(1076963)
+	shortSku := domain.Sku(2956315)
+	before := f.stock(ctx, availableSku)
+
+	orderID, err := f.executor.OrderCreate(ctx, domain.Order{
+		UserID: 1,
+		Items: []domain.Item{
+			{Sku: availableSku, Count: 5},
+			{Sku: shortSku, Count: 1000},
+		},
+	})
+	f.ErrorIs(err, domain.ErrNotEnoughStock)
+	f.Zero(orderID)
+
+	f.Equal(before, f.stock(ctx, availableSku))
+
+	orders, _, err := f.executor.OrderList(ctx, domain.OrderListFilter{UserID: 1, Limit: 10})
+	f.NoError(err)
+	f.Len(orders, 1)
+	f.Equal(domain.OrderStatusFailed, orders[0].Status)
+}
+
+func TestInMemoryOrderWatch(t *testing.T) {
+	t.Parallel()
+
+	f := setUpInMemory(t)
+	ctx := context.Background()
+
+	orderID, err := f.executor.OrderCreate(ctx, domain.Order{
+		UserID: 1,
+		Items:  []domain.Item{{Sku: 1076963, Count: 1}},
+	})
+	f.NoError(err)
+
+	current, sub, err := f.executor.WatchOrder(ctx, orderID)
+	f.NoError(err)
+	defer sub.Close()
+
+	f.Equal(domain.OrderStatusAwaitingPayment, current.Status)
+
+	f.ErrorIs(f.executor.OrderReturnItems(ctx, orderID, []domain.Item{{Sku: 1076963, Count: 1}}), domain.ErrReturnItemsStatus)
+	f.NoError(f.executor.OrderPay(ctx, orderID))
+
+	select {
+	case update := <-sub.Updates():
+		f.Equal(orderID, update.OrderID)
+		f.Equal(domain.OrderStatusPayed, update.Status)
+	default:
+		f.Fail("expected status update after commit")
+	}
+
+	f.Empty(sub.Updates())
+}
+
+func TestInMemoryOrderPayDeclined(t *testing.T) {
+	t.Parallel()
+
+	f := setUpInMemoryWithPayment(t, fake.ModeDecline)
+	ctx := context.Background()
+
+	orderID, err := f.executor.OrderCreate(ctx, domain.Order{
+		UserID: 1,
+		Items:  []domain.Item{{Sku: 1076963, Count: 1}},
+	})
+	f.NoError(err)
+
+	f.ErrorIs(f.executor.OrderPay(ctx, orderID), domain.ErrPaymentDeclined)
+	f.ErrorIs(f.executor.OrderPay(ctx, orderID), domain.ErrPaymentDeclined)
+
+	order, err := f.executor.OrderInfo(ctx, orderID)
+	f.NoError(err)
+	f.Equal(domain.OrderStatusAwaitingPayment, order.Status)
+
+	intent, err := f.paymentRepository.GetPaymentIntent(ctx, orderID)
+	f.NoError(err)
+	f.Equal(domain.PaymentStatusDeclined, intent.Status)
+	f.Equal(int32(2), intent.Attempts)
+}
+
+func TestInMemoryOrderPayTimeout(t *testing.T) {
+	t.Parallel()
+
+	f := setUpInMemoryWithPayment(t, fake.ModeTimeout)
+	ctx := context.Background()
+
+	orderID, err := f.executor.OrderCreate(ctx, domain.Order{
+		UserID: 1,
+		Items:  []domain.Item{{Sku: 1076963, Count: 1}},
+	})
+	f.NoError(err)
+
+	f.ErrorIs(f.executor.OrderPay(ctx, orderID), domain.ErrPaymentUnavailable)
+
+	order, err := f.executor.OrderInfo(ctx, orderID)
+	f.NoError(err)
+	f.Equal(domain.OrderStatusAwaitingPayment, order.Status)
+}
+
+func TestInMemoryOrderPayConfirmedByWebhook(t *testing.T) {
+	t.Parallel()
+
+	f := setUpInMemoryWithPayment(t, fake.ModePending)
+	ctx := context.Background()
+
+	orderID, err := f.executor.OrderCreate(ctx, domain.Order{
+		UserID: 1,
+		Items:  []domain.Item{{Sku: 1076963, Count: 2, Price: 3379}},
+	})
+	f.NoError(err)
+
+	f.ErrorIs(f.executor.OrderPay(ctx, orderID), domain.ErrPaymentPending)
+
+	intent, err := f.paymentRepository.GetPaymentIntent(ctx, orderID)
+	f.NoError(err)
+	f.Equal(domain.PaymentStatusPending, intent.Status)
+	f.Equal(int64(6758), intent.Amount)
+	f.Equal(domain.DefaultCurrency, intent.Currency)
+
+	result := domain.PaymentResult{
+		ProviderPaymentID: intent.ProviderPaymentID,
+		Status:            domain.PaymentStatusCaptured,
+		Amount:            1,
+		Currency:          intent.Currency,
+	}
+	f.ErrorIs(f.executor.ConfirmPayment(ctx, orderID, result), domain.ErrPaymentAmountMismatch)
+
+	result.Amount = intent.Amount
+	f.NoError(f.executor.ConfirmPayment(ctx, orderID, result))
+	f.NoError(f.executor.ConfirmPayment(ctx, orderID, result))
+
+	order, err := f.executor.OrderInfo(ctx, orderID)
+	f.NoError(err)
+	f.Equal(domain.OrderStatusPayed, order.Status)
+	f.Equal(int64(6758), order.TotalPrice)
+	f.Equal(domain.DefaultCurrency, order.Currency)
+}
+
+func TestInMemoryOrderPaymentRefundedAfterCancel(t *testing.T) {
+	t.Parallel()
+
+	f := setUpInMemoryWithPayment(t, fake.ModePending)
+	ctx := context.Background()
+
+	orderID, err := f.executor.OrderCreate(ctx, domain.Order{
+		UserID: 1,
+		Items:  []domain.Item{{Sku: 1076963, Count: 1}},
+	})
+	f.NoError(err)
+
+	f.ErrorIs(f.executor.OrderPay(ctx, orderID), domain.ErrPaymentPending)
+	f.NoError(f.executor.OrderCancel(ctx, orderID))
+
+	intent, err := f.paymentRepository.GetPaymentIntent(ctx, orderID)
+	f.NoError(err)
+
+	err = f.executor.ConfirmPayment(ctx, orderID, domain.PaymentResult{
+		ProviderPaymentID: intent.ProviderPaymentID,
+		Status:            domain.PaymentStatusCaptured,
+		Amount:            intent.Amount,
+		Currency:          intent.Currency,
+	})
+	f.ErrorIs(err, domain.ErrPayStatusOrder)
+
+	intent, err = f.paymentRepository.GetPaymentIntent(ctx, orderID)
+	f.NoError(err)
+	f.Equal(domain.PaymentStatusRefunded, intent.Status)
+}
+
+func TestInMemoryOrderRefund(t *testing.T) {
+	t.Parallel()
+
+	f := setUpInMemory(t)
+	ctx := context.Background()
+
+	testSku := domain.Sku(1076963)
+	before := f.stock(ctx, testSku)
+
+	orderID, err := f.executor.OrderCreate(ctx, domain.Order{
+		UserID: 1,
+		Items:  []domain.Item{{Sku: testSku, Count: 3}},
+	})
+	f.NoError(err)
+
+	f.ErrorIs(f.executor.OrderRefund(ctx, orderID, true), domain.ErrRefundStatusOrder)
+	f.NoError(f.executor.OrderPay(ctx, orderID))
+	f.Equal(before.TotalCount-3, f.stock(ctx, testSku).TotalCount)
+
+	f.NoError(f.executor.OrderRefund(ctx, orderID, true))
+	f.NoError(f.executor.OrderRefund(ctx, orderID, true))
+	f.ErrorIs(f.executor.OrderCancel(ctx, orderID), domain.ErrCancelOrder)
+
+	order, err := f.executor.OrderInfo(ctx, orderID)
+	f.NoError(err)
+	f.Equal(domain.OrderStatusRefunded, order.Status)
+	f.Equal(before, f.stock(ctx, testSku))
+
+	intent, err := f.paymentRepository.GetPaymentIntent(ctx, orderID)
+	f.NoError(err)
+	f.Equal(domain.PaymentStatusRefunded, intent.Status)
+
+	history, err := f.executor.OrderHistory(ctx, orderID)
+	f.NoError(err)
+	f.Equal(domain.OrderStatusRefundRequested, history[len(history)-2].Status)
+	f.Equal(domain.OrderStatusRefunded, history[len(history)-1].Status)
+}
+
+func TestInMemoryOrderRefundAfterPartialReturn(t *testing.T) {
+	t.Parallel()
+
+	f := setUpInMemory(t)
+	ctx := context.Background()
+
+	testSku := domain.Sku(1076963)
+	before := f.stock(ctx, testSku)
+
+	orderID, err := f.executor.OrderCreate(ctx, domain.Order{
+		UserID: 1,
+		Items:  []domain.Item{{Sku: testSku, Count: 3, Price: 3379}},
+	})
+	f.NoError(err)
+
+	f.NoError(f.executor.OrderPay(ctx, orderID))
+	f.NoError(f.executor.OrderReturnItems(ctx, orderID, []domain.Item{{Sku: testSku, Count: 1}}))
+
+	order, err := f.executor.OrderInfo(ctx, orderID)
+	f.NoError(err)
+	f.Equal([]domain.Item{{Sku: testSku, Count: 2, Price: 3379, Currency: domain.DefaultCurrency}}, order.Items)
+	f.Equal(int64(10137), order.TotalPrice)
+
+	f.NoError(f.executor.OrderRefund(ctx, orderID, true))
+
+	intent, err := f.paymentRepository.GetPaymentIntent(ctx, orderID)
+	f.NoError(err)
+	f.Equal(domain.PaymentStatusRefunded, intent.Status)
+	f.Equal(int64(10137), intent.Amount)
+	f.Equal(before, f.stock(ctx, testSku))
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"route256/loms/internal/domain"
+	"route256/loms/internal/infra/sharding"
 	"sort"
 
 	"github.com/opentracing/opentracing-go"
@@ -13,11 +14,18 @@ func (s *Service) OrderInfo(ctx context.Context, orderID int64) (domain.Order, e
 	span, ctx := opentracing.StartSpanFromContext(ctx, "orderService.OrderInfo")
 	defer span.Finish()
 
+	ctx = sharding.WithID(ctx, orderID)
+
 	order, err := s.orderRepository.GetByOrderID(ctx, orderID)
 	if err != nil {
 		return domain.Order{}, fmt.Errorf("orderRepository.GetByOrderID: %w", err)
 	}
 
+	order.Allocations, err = s.orderRepository.GetAllocationsByOrderID(ctx, orderID)
+	if err != nil {
+		return domain.Order{}, fmt.Errorf("orderRepository.GetAllocationsByOrderID: %w", err)
+	}
+
 	sort.Slice(order.Items, func(i, j int) bool {
 		return order.Items[i].Sku < order.Items[j].Sku
 	})
@@ -32,8 +32,15 @@ func TestOrderInfo(t *testing.T) {
 		Items:  unsortedItems,
 	}
 
+	testAllocations := []domain.Allocation{
+		{Sku: 1001, WarehouseID: 1, Count: 2},
+		{Sku: 2000, WarehouseID: 2, Count: 5},
+		{Sku: 3002, WarehouseID: 1, Count: 1},
+	}
+
 	type mocks struct {
-		mockGetByOrderID testhelpers.NeedCallWithErr
+		mockGetByOrderID            testhelpers.NeedCallWithErr
+		mockGetAllocationsByOrderID testhelpers.NeedCallWithErr
 	}
 
 	testCases := []struct {
@@ -45,13 +52,15 @@ func TestOrderInfo(t *testing.T) {
 		{
 			name: "success: orderservice.OrderInfo returns sorted items",
 			mocks: mocks{
-				mockGetByOrderID: testhelpers.NewNeedCallWithErr(nil),
+				mockGetByOrderID:            testhelpers.NewNeedCallWithErr(nil),
+				mockGetAllocationsByOrderID: testhelpers.NewNeedCallWithErr(nil),
 			},
 			expectedErr: nil,
 			expectedOrder: domain.Order{
-				UserID: testOrder.UserID,
-				Status: testOrder.Status,
-				Items:  sortedItems,
+				UserID:      testOrder.UserID,
+				Status:      testOrder.Status,
+				Items:       sortedItems,
+				Allocations: testAllocations,
 			},
 		},
 		{
@@ -62,6 +71,15 @@ func TestOrderInfo(t *testing.T) {
 			expectedErr:   testhelpers.ErrForTest,
 			expectedOrder: domain.Order{},
 		},
+		{
+			name: "fail: orderservice.OrderInfo GetAllocationsByOrderID error",
+			mocks: mocks{
+				mockGetByOrderID:            testhelpers.NewNeedCallWithErr(nil),
+				mockGetAllocationsByOrderID: testhelpers.NewNeedCallWithErr(testhelpers.ErrForTest),
+			},
+			expectedErr:   testhelpers.ErrForTest,
+			expectedOrder: domain.Order{},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -77,6 +95,12 @@ func TestOrderInfo(t *testing.T) {
 					Return(testOrder, tc.mocks.mockGetByOrderID.Err)
 			}
 
+			if tc.mocks.mockGetAllocationsByOrderID.NeedCall {
+				f.orderRepository.GetAllocationsByOrderIDMock.
+					Expect(minimock.AnyContext, testOrderID).
+					Return(testAllocations, tc.mocks.mockGetAllocationsByOrderID.Err)
+			}
+
 			order, err := f.executor.OrderInfo(ctx, testOrderID)
 
 			if tc.expectedErr != nil {
@@ -88,6 +112,7 @@ func TestOrderInfo(t *testing.T) {
 				f.Equal(tc.expectedOrder.UserID, order.UserID)
 				f.Equal(tc.expectedOrder.Status, order.Status)
 				f.Equal(tc.expectedOrder.Items, order.Items)
+				f.Equal(tc.expectedOrder.Allocations, order.Allocations)
 			}
 		})
 	}
@@ -0,0 +1,108 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"route256/loms/internal/domain"
+)
+
+func subtractItems(orderItems []domain.Item, items []domain.Item) ([]domain.Item, error) {
+	remaining := make(map[domain.Sku]int64, len(orderItems))
+	for _, item := range orderItems {
+		remaining[item.Sku] += item.Count
+	}
+
+	for _, item := range items {
+		count, ok := remaining[item.Sku]
+		if !ok || item.Count <= 0 || item.Count > count {
+			return nil, fmt.Errorf("%w: sku %v", domain.ErrInvalidOrderItems, item.Sku)
+		}
+
+		remaining[item.Sku] = count - item.Count
+	}
+
+	result := make([]domain.Item, 0, len(orderItems))
+	for _, item := range orderItems {
+		if count := remaining[item.Sku]; count > 0 {
+			result = append(result, domain.Item{Sku: item.Sku, Count: count})
+			remaining[item.Sku] = 0
+		}
+	}
+
+	return result, nil
+}
+
+func priceOrder(order domain.Order) (domain.Order, error) {
+	items := make([]domain.Item, len(order.Items))
+	order.Currency = ""
+	order.TotalPrice = 0
+
+	for idx, item := range order.Items {
+		if item.Currency == "" {
+			item.Currency = domain.DefaultCurrency
+		}
+
+		if item.Price < 0 {
+			return domain.Order{}, fmt.Errorf("%w: sku %v", domain.ErrInvalidOrderItems, item.Sku)
+		}
+
+		if order.Currency != "" && item.Currency != order.Currency {
+			return domain.Order{}, fmt.Errorf("%w: sku %v", domain.ErrOrderCurrency, item.Sku)
+		}
+
+		order.Currency = item.Currency
+		order.TotalPrice += item.Price * item.Count
+		items[idx] = item
+	}
+
+	if order.Currency == "" {
+		order.Currency = domain.DefaultCurrency
+	}
+
+	order.Items = items
+
+	return order, nil
+}
+
+func (s *Service) releaseAllocations(
+	ctx context.Context,
+	orderID int64,
+	items []domain.Item) ([]domain.Allocation, error) {
+	allocations, err := s.orderRepository.GetAllocationsByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("orderRepository.GetAllocationsByOrderID: %w", err)
+	}
+
+	left := make([]int64, len(allocations))
+	for idx, allocation := range allocations {
+		left[idx] = allocation.Count
+	}
+
+	released := make([]domain.Allocation, 0, len(items))
+
+	for _, item := range items {
+		need := item.Count
+
+		for idx := len(allocations) - 1; idx >= 0 && need > 0; idx-- {
+			if allocations[idx].Sku != item.Sku || left[idx] == 0 {
+				continue
+			}
+
+			take := min(left[idx], need)
+			left[idx] -= take
+			need -= take
+
+			released = append(released, domain.Allocation{
+				Sku:         item.Sku,
+				WarehouseID: allocations[idx].WarehouseID,
+				Count:       take,
+			})
+		}
+
+		if need > 0 {
+			return nil, fmt.Errorf("%w: sku %v", domain.ErrInvalidOrderItems, item.Sku)
+		}
+	}
+
+	return released, nil
+}
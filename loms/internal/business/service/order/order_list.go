@@ -0,0 +1,46 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"route256/loms/internal/domain"
+	"route256/loms/internal/infra/sharding"
+	"sort"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+const defaultOrderListLimit = 20
+
+func (s *Service) OrderList(ctx context.Context, filter domain.OrderListFilter) ([]domain.Order, int64, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "orderService.OrderList")
+	defer span.Finish()
+
+	ctx = sharding.WithUserID(ctx, filter.UserID)
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultOrderListLimit
+	}
+
+	filter.Limit = limit + 1
+
+	orders, err := s.orderRepository.ListByUserID(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("orderRepository.ListByUserID: %w", err)
+	}
+
+	var nextCursor int64
+	if len(orders) > int(limit) {
+		orders = orders[:limit]
+		nextCursor = orders[limit-1].ID
+	}
+
+	for _, order := range orders {
+		sort.Slice(order.Items, func(i, j int) bool {
+			return order.Items[i].Sku < order.Items[j].Sku
+		})
+	}
+
+	return orders, nextCursor, nil
+}
@@ -0,0 +1,142 @@
+package order_test
+
+import (
+	"context"
+	"route256/loms/internal/domain"
+	testhelpers "route256/loms/internal/tool"
+	"testing"
+
+	"github.com/gojuno/minimock/v3"
+)
+
+func TestOrderList(t *testing.T) {
+	t.Parallel()
+
+	testUserID := int64(1)
+
+	testOrders := []domain.Order{
+		{
+			ID:     30,
+			UserID: testUserID,
+			Status: domain.OrderStatusPayed,
+			Items: []domain.Item{
+				{Sku: 3002, Count: 1},
+				{Sku: 1001, Count: 2},
+			},
+		},
+		{
+			ID:     20,
+			UserID: testUserID,
+			Status: domain.OrderStatusAwaitingPayment,
+			Items:  []domain.Item{{Sku: 2000, Count: 5}},
+		},
+		{
+			ID:     10,
+			UserID: testUserID,
+			Status: domain.OrderStatusCancelled,
+			Items:  []domain.Item{{Sku: 1001, Count: 1}},
+		},
+	}
+
+	type mocks struct {
+		mockListByUserID testhelpers.NeedCallWithErrAndResult[[]domain.Order]
+	}
+
+	testCases := []struct {
+		name               string
+		filter             domain.OrderListFilter
+		expectedRepoLimit  int32
+		mocks              mocks
+		expectedErr        error
+		expectedOrderIDs   []int64
+		expectedNextCursor int64
+	}{
+		{
+			name:              "success: orderservice.OrderList returns next cursor when more orders exist",
+			filter:            domain.OrderListFilter{UserID: testUserID, Limit: 2},
+			expectedRepoLimit: 3,
+			mocks: mocks{
+				mockListByUserID: testhelpers.NewNeedCallWithErrAndResult(testOrders, nil),
+			},
+			expectedOrderIDs:   []int64{30, 20},
+			expectedNextCursor: 20,
+		},
+		{
+			name:              "success: orderservice.OrderList last page has no next cursor",
+			filter:            domain.OrderListFilter{UserID: testUserID, Cursor: 20, Limit: 2},
+			expectedRepoLimit: 3,
+			mocks: mocks{
+				mockListByUserID: testhelpers.NewNeedCallWithErrAndResult(testOrders[2:], nil),
+			},
+			expectedOrderIDs:   []int64{10},
+			expectedNextCursor: 0,
+		},
+		{
+			name:              "success: orderservice.OrderList uses default limit",
+			filter:            domain.OrderListFilter{UserID: testUserID},
+			expectedRepoLimit: 21,
+			mocks: mocks{
+				mockListByUserID: testhelpers.NewNeedCallWithErrAndResult([]domain.Order(nil), nil),
+			},
+			expectedOrderIDs:   []int64{},
+			expectedNextCursor: 0,
+		},
+		{
+			name:              "fail: orderservice.OrderList ListByUserID error",
+			filter:            domain.OrderListFilter{UserID: testUserID, Limit: 2},
+			expectedRepoLimit: 3,
+			mocks: mocks{
+				mockListByUserID: testhelpers.NewNeedCallWithErrAndResult([]domain.Order(nil), testhelpers.ErrForTest),
+			},
+			expectedErr: testhelpers.ErrForTest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			f := setUp(t)
+
+			if tc.mocks.mockListByUserID.NeedCall {
+				expectedFilter := tc.filter
+				expectedFilter.Limit = tc.expectedRepoLimit
+
+				result := make([]domain.Order, len(tc.mocks.mockListByUserID.Result))
+				for idx, order := range tc.mocks.mockListByUserID.Result {
+					order.Items = append([]domain.Item(nil), order.Items...)
+					result[idx] = order
+				}
+
+				f.orderRepository.ListByUserIDMock.
+					Expect(minimock.AnyContext, expectedFilter).
+					Return(result, tc.mocks.mockListByUserID.Err)
+			}
+
+			orders, nextCursor, err := f.executor.OrderList(ctx, tc.filter)
+
+			if tc.expectedErr != nil {
+				f.Error(err)
+				f.ErrorContains(err, tc.expectedErr.Error())
+				f.Nil(orders)
+				return
+			}
+
+			f.NoError(err)
+			f.Equal(tc.expectedNextCursor, nextCursor)
+
+			orderIDs := make([]int64, 0, len(orders))
+			for _, order := range orders {
+				orderIDs = append(orderIDs, order.ID)
+			}
+			f.Equal(tc.expectedOrderIDs, orderIDs)
+
+			for _, order := range orders {
+				for i := 1; i < len(order.Items); i++ {
+					f.Less(order.Items[i-1].Sku, order.Items[i].Sku)
+				}
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"route256/loms/internal/domain"
+	"route256/loms/internal/infra/sharding"
 
 	"github.com/opentracing/opentracing-go"
 )
@@ -12,26 +13,31 @@ func (s *Service) OrderPay(ctx context.Context, orderID int64) error {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "orderService.OrderPay")
 	defer span.Finish()
 
-	err := s.txManagerMaster.ReadCommitted(ctx, func(ctx context.Context) error {
-		order, err := s.orderRepository.GetByOrderIDForUpdate(ctx, orderID)
+	ctx = sharding.WithID(ctx, orderID)
+
+	var (
+		order  domain.Order
+		intent domain.PaymentIntent
+	)
+
+	err := s.readCommitted(ctx, func(ctx context.Context) error {
+		var err error
+		order, err = s.orderRepository.GetByOrderIDForUpdate(ctx, orderID)
 		if err != nil {
 			return fmt.Errorf("orderRepository.GetByOrderIDForUpdate: %w", err)
 		}
 
-		if order.Status == domain.OrderStatusPayed {
+		if s.orderStates.Done(order.Status, domain.OrderActionPay) {
 			return nil
 		}
 
-		if order.Status != domain.OrderStatusAwaitingPayment {
-			return domain.ErrPayStatusOrder
-		}
-
-		if err = s.stockService.ReserveRemove(ctx, order.Items); err != nil {
-			return fmt.Errorf("stockService.ReserveRemove: %w", err)
+		if err = s.orderStates.Validate(order.Status, domain.OrderActionPay); err != nil {
+			return err
 		}
 
-		if err = s.setStatusAndCreateEvent(ctx, orderID, domain.OrderStatusPayed); err != nil {
-			return fmt.Errorf("setStatusAndCreateEvent: %w", err)
+		intent, err = s.paymentIntent(ctx, orderID, order)
+		if err != nil {
+			return fmt.Errorf("paymentIntent: %w", err)
 		}
 
 		return nil
@@ -41,5 +47,14 @@ func (s *Service) OrderPay(ctx context.Context, orderID int64) error {
 		return fmt.Errorf("txManager.ReadCommitted: %w", err)
 	}
 
-	return nil
+	if s.orderStates.Done(order.Status, domain.OrderActionPay) {
+		return nil
+	}
+
+	result, err := s.authorizePayment(ctx, orderID, order, intent)
+	if err != nil {
+		return err
+	}
+
+	return s.applyPaymentResult(ctx, orderID, result)
 }
@@ -19,20 +19,26 @@ func TestOrderPay(t *testing.T) {
 		{Sku: 1001, Count: 2},
 		{Sku: 1002, Count: 1},
 	}
+	testAllocations := []domain.Allocation{
+		{Sku: 1001, WarehouseID: 1, Count: 2},
+		{Sku: 1002, WarehouseID: 1, Count: 1},
+	}
 
 	type mocks struct {
 		mockGetByOrderIDForUpdate   testhelpers.NeedCallWithErr
+		mockPaymentIntent           testhelpers.NeedCallWithErr
+		mockAuthorize               testhelpers.NeedCallWithErrAndResult[domain.PaymentStatus]
+		mockUpdatePaymentIntent     testhelpers.NeedCallWithErr
+		mockGetAllocationsByOrderID testhelpers.NeedCallWithErr
 		mockReserveRemove           testhelpers.NeedCallWithErr
-		mockReadCommitted           testhelpers.NeedCallWithErr
 		mockSetStatusAndCreateEvent testhelpers.NeedCallWithErr
 	}
 
 	testCases := []struct {
-		name           string
-		order          domain.Order
-		mocks          mocks
-		expectedErr    error
-		expectedStatus domain.EventStatus
+		name        string
+		order       domain.Order
+		mocks       mocks
+		expectedErr error
 	}{
 		{
 			name: "success: orderservice.OrderPay",
@@ -43,12 +49,14 @@ func TestOrderPay(t *testing.T) {
 			},
 			mocks: mocks{
 				mockGetByOrderIDForUpdate:   testhelpers.NewNeedCallWithErr(nil),
+				mockPaymentIntent:           testhelpers.NewNeedCallWithErr(nil),
+				mockAuthorize:               testhelpers.NewNeedCallWithErrAndResult(domain.PaymentStatusCaptured, nil),
+				mockUpdatePaymentIntent:     testhelpers.NewNeedCallWithErr(nil),
+				mockGetAllocationsByOrderID: testhelpers.NewNeedCallWithErr(nil),
 				mockReserveRemove:           testhelpers.NewNeedCallWithErr(nil),
 				mockSetStatusAndCreateEvent: testhelpers.NewNeedCallWithErr(nil),
-				mockReadCommitted:           testhelpers.NewNeedCallWithErr(nil),
 			},
-			expectedErr:    nil,
-			expectedStatus: domain.EventStatusNew,
+			expectedErr: nil,
 		},
 		{
 			name: "success: orderservice.OrderPay already payed order returns nil",
@@ -83,7 +91,22 @@ func TestOrderPay(t *testing.T) {
 			expectedErr: testhelpers.ErrForTest,
 		},
 		{
-			name: "fail: orderservice.OrderPay ReserveRemove error",
+			name: "fail: orderservice.OrderPay payment declined",
+			order: domain.Order{
+				UserID: 1,
+				Status: domain.OrderStatusAwaitingPayment,
+				Items:  testItems,
+			},
+			mocks: mocks{
+				mockGetByOrderIDForUpdate: testhelpers.NewNeedCallWithErr(nil),
+				mockPaymentIntent:         testhelpers.NewNeedCallWithErr(nil),
+				mockAuthorize:             testhelpers.NewNeedCallWithErrAndResult(domain.PaymentStatusDeclined, nil),
+				mockUpdatePaymentIntent:   testhelpers.NewNeedCallWithErr(nil),
+			},
+			expectedErr: domain.ErrPaymentDeclined,
+		},
+		{
+			name: "fail: orderservice.OrderPay payment pending",
 			order: domain.Order{
 				UserID: 1,
 				Status: domain.OrderStatusAwaitingPayment,
@@ -91,8 +114,57 @@ func TestOrderPay(t *testing.T) {
 			},
 			mocks: mocks{
 				mockGetByOrderIDForUpdate: testhelpers.NewNeedCallWithErr(nil),
-				mockReserveRemove:         testhelpers.NewNeedCallWithErr(testhelpers.ErrForTest),
-				mockReadCommitted:         testhelpers.NewNeedCallWithErr(nil),
+				mockPaymentIntent:         testhelpers.NewNeedCallWithErr(nil),
+				mockAuthorize:             testhelpers.NewNeedCallWithErrAndResult(domain.PaymentStatusPending, nil),
+				mockUpdatePaymentIntent:   testhelpers.NewNeedCallWithErr(nil),
+			},
+			expectedErr: domain.ErrPaymentPending,
+		},
+		{
+			name: "fail: orderservice.OrderPay payment gateway error",
+			order: domain.Order{
+				UserID: 1,
+				Status: domain.OrderStatusAwaitingPayment,
+				Items:  testItems,
+			},
+			mocks: mocks{
+				mockGetByOrderIDForUpdate: testhelpers.NewNeedCallWithErr(nil),
+				mockPaymentIntent:         testhelpers.NewNeedCallWithErr(nil),
+				mockAuthorize: testhelpers.NewNeedCallWithErrAndResult(
+					domain.PaymentStatus(""), testhelpers.ErrForTest),
+			},
+			expectedErr: domain.ErrPaymentUnavailable,
+		},
+		{
+			name: "fail: orderservice.OrderPay GetAllocationsByOrderID error",
+			order: domain.Order{
+				UserID: 1,
+				Status: domain.OrderStatusAwaitingPayment,
+				Items:  testItems,
+			},
+			mocks: mocks{
+				mockGetByOrderIDForUpdate:   testhelpers.NewNeedCallWithErr(nil),
+				mockPaymentIntent:           testhelpers.NewNeedCallWithErr(nil),
+				mockAuthorize:               testhelpers.NewNeedCallWithErrAndResult(domain.PaymentStatusCaptured, nil),
+				mockUpdatePaymentIntent:     testhelpers.NewNeedCallWithErr(nil),
+				mockGetAllocationsByOrderID: testhelpers.NewNeedCallWithErr(testhelpers.ErrForTest),
+			},
+			expectedErr: testhelpers.ErrForTest,
+		},
+		{
+			name: "fail: orderservice.OrderPay ReserveRemove error",
+			order: domain.Order{
+				UserID: 1,
+				Status: domain.OrderStatusAwaitingPayment,
+				Items:  testItems,
+			},
+			mocks: mocks{
+				mockGetByOrderIDForUpdate:   testhelpers.NewNeedCallWithErr(nil),
+				mockPaymentIntent:           testhelpers.NewNeedCallWithErr(nil),
+				mockAuthorize:               testhelpers.NewNeedCallWithErrAndResult(domain.PaymentStatusCaptured, nil),
+				mockUpdatePaymentIntent:     testhelpers.NewNeedCallWithErr(nil),
+				mockGetAllocationsByOrderID: testhelpers.NewNeedCallWithErr(nil),
+				mockReserveRemove:           testhelpers.NewNeedCallWithErr(testhelpers.ErrForTest),
 			},
 			expectedErr: testhelpers.ErrForTest,
 		},
@@ -105,9 +177,12 @@ func TestOrderPay(t *testing.T) {
 			},
 			mocks: mocks{
 				mockGetByOrderIDForUpdate:   testhelpers.NewNeedCallWithErr(nil),
+				mockPaymentIntent:           testhelpers.NewNeedCallWithErr(nil),
+				mockAuthorize:               testhelpers.NewNeedCallWithErrAndResult(domain.PaymentStatusCaptured, nil),
+				mockUpdatePaymentIntent:     testhelpers.NewNeedCallWithErr(nil),
+				mockGetAllocationsByOrderID: testhelpers.NewNeedCallWithErr(nil),
 				mockReserveRemove:           testhelpers.NewNeedCallWithErr(nil),
 				mockSetStatusAndCreateEvent: testhelpers.NewNeedCallWithErr(testhelpers.ErrForTest),
-				mockReadCommitted:           testhelpers.NewNeedCallWithErr(nil),
 			},
 			expectedErr: testhelpers.ErrForTest,
 		},
@@ -130,9 +205,32 @@ func TestOrderPay(t *testing.T) {
 					Return(tc.order, tc.mocks.mockGetByOrderIDForUpdate.Err)
 			}
 
+			if tc.mocks.mockPaymentIntent.NeedCall {
+				f.expectNewPaymentIntent(testOrderID)
+			}
+
+			if tc.mocks.mockAuthorize.NeedCall {
+				f.expectAuthorize(testOrderID, tc.mocks.mockAuthorize.Result, tc.mocks.mockAuthorize.Err)
+			}
+
+			if tc.mocks.mockUpdatePaymentIntent.NeedCall {
+				f.paymentRepository.UpdatePaymentIntentMock.
+					Expect(minimock.AnyContext, testOrderID, domain.PaymentResult{
+						ProviderPaymentID: testProviderPaymentID,
+						Status:            tc.mocks.mockAuthorize.Result,
+					}).
+					Return(tc.mocks.mockUpdatePaymentIntent.Err)
+			}
+
+			if tc.mocks.mockGetAllocationsByOrderID.NeedCall {
+				f.orderRepository.GetAllocationsByOrderIDMock.
+					Expect(minimock.AnyContext, testOrderID).
+					Return(testAllocations, tc.mocks.mockGetAllocationsByOrderID.Err)
+			}
+
 			if tc.mocks.mockReserveRemove.NeedCall {
 				f.stockService.ReserveRemoveMock.
-					Expect(minimock.AnyContext, tc.order.Items).
+					Expect(minimock.AnyContext, testOrderID, testAllocations).
 					Return(tc.mocks.mockReserveRemove.Err)
 			}
 
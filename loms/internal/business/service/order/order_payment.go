@@ -0,0 +1,212 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"route256/loms/internal/domain"
+	"route256/loms/internal/infra/sharding"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+func (s *Service) ConfirmPayment(ctx context.Context, orderID int64, result domain.PaymentResult) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "orderService.ConfirmPayment")
+	defer span.Finish()
+
+	ctx = sharding.WithID(ctx, orderID)
+
+	intent, err := s.paymentRepository.GetPaymentIntent(ctx, orderID)
+	if err != nil {
+		return fmt.Errorf("paymentRepository.GetPaymentIntent: %w", err)
+	}
+
+	if intent.ProviderPaymentID != "" && intent.ProviderPaymentID != result.ProviderPaymentID {
+		return domain.ErrPaymentNotFound
+	}
+
+	if intent.Status == domain.PaymentStatusCaptured || intent.Status == domain.PaymentStatusRefunded {
+		return nil
+	}
+
+	if result.Status != domain.PaymentStatusDeclined &&
+		(result.Amount != intent.Amount || result.Currency != intent.Currency) {
+		return domain.ErrPaymentAmountMismatch
+	}
+
+	err = s.applyPaymentResult(ctx, orderID, result)
+	if errors.Is(err, domain.ErrPaymentDeclined) || errors.Is(err, domain.ErrPaymentPending) {
+		return nil
+	}
+
+	return err
+}
+
+func (s *Service) paymentIntent(ctx context.Context, orderID int64, order domain.Order) (domain.PaymentIntent, error) {
+	intent, err := s.paymentRepository.GetPaymentIntent(ctx, orderID)
+	if err == nil {
+		return intent, nil
+	}
+
+	if !errors.Is(err, domain.ErrPaymentNotFound) {
+		return domain.PaymentIntent{}, fmt.Errorf("paymentRepository.GetPaymentIntent: %w", err)
+	}
+
+	intent, err = s.paymentRepository.CreatePaymentIntent(ctx, domain.PaymentIntent{
+		OrderID:  orderID,
+		Provider: s.paymentGateway.Name(),
+		Amount:   order.TotalPrice,
+		Currency: order.Currency,
+	})
+	if err != nil {
+		return domain.PaymentIntent{}, fmt.Errorf("paymentRepository.CreatePaymentIntent: %w", err)
+	}
+
+	return intent, nil
+}
+
+func (s *Service) authorizePayment(
+	ctx context.Context,
+	orderID int64,
+	order domain.Order,
+	intent domain.PaymentIntent) (domain.PaymentResult, error) {
+	switch intent.Status {
+	case domain.PaymentStatusAuthorized, domain.PaymentStatusCaptured:
+		return domain.PaymentResult{
+			ProviderPaymentID: intent.ProviderPaymentID,
+			Status:            intent.Status,
+			Amount:            intent.Amount,
+			Currency:          intent.Currency,
+		}, nil
+	}
+
+	result, err := s.paymentGateway.Authorize(ctx, domain.PaymentRequest{
+		OrderID:        orderID,
+		UserID:         order.UserID,
+		IdempotencyKey: fmt.Sprintf("order-%d-%d", orderID, intent.Attempts),
+		Amount:         order.TotalPrice,
+		Currency:       order.Currency,
+	})
+	if err != nil {
+		return domain.PaymentResult{}, fmt.Errorf("%w: paymentGateway.Authorize: %v", domain.ErrPaymentUnavailable, err)
+	}
+
+	return result, nil
+}
+
+func (s *Service) applyPaymentResult(ctx context.Context, orderID int64, result domain.PaymentResult) error {
+	if result.Status == domain.PaymentStatusAuthorized {
+		if err := s.paymentRepository.UpdatePaymentIntent(ctx, orderID, result); err != nil {
+			return fmt.Errorf("paymentRepository.UpdatePaymentIntent: %w", err)
+		}
+
+		captured, err := s.paymentGateway.Capture(ctx, result.ProviderPaymentID, result.Amount, result.Currency)
+		if err != nil {
+			return fmt.Errorf("%w: paymentGateway.Capture: %v", domain.ErrPaymentUnavailable, err)
+		}
+
+		result = captured
+	}
+
+	switch result.Status {
+	case domain.PaymentStatusCaptured:
+		return s.completePayment(ctx, orderID, result)
+	case domain.PaymentStatusAuthorized, domain.PaymentStatusPending, domain.PaymentStatusDeclined:
+		if err := s.paymentRepository.UpdatePaymentIntent(ctx, orderID, result); err != nil {
+			return fmt.Errorf("paymentRepository.UpdatePaymentIntent: %w", err)
+		}
+
+		if result.Status == domain.PaymentStatusDeclined {
+			return fmt.Errorf("%w: %s", domain.ErrPaymentDeclined, result.Reason)
+		}
+
+		return domain.ErrPaymentPending
+	default:
+		return fmt.Errorf("unexpected payment status %q", result.Status)
+	}
+}
+
+func (s *Service) completePayment(ctx context.Context, orderID int64, result domain.PaymentResult) error {
+	var refundErr error
+
+	err := s.readCommitted(ctx, func(ctx context.Context) error {
+		order, err := s.orderRepository.GetByOrderIDForUpdate(ctx, orderID)
+		if err != nil {
+			return fmt.Errorf("orderRepository.GetByOrderIDForUpdate: %w", err)
+		}
+
+		if err = s.paymentRepository.UpdatePaymentIntent(ctx, orderID, result); err != nil {
+			return fmt.Errorf("paymentRepository.UpdatePaymentIntent: %w", err)
+		}
+
+		err = s.orderStates.Fire(ctx, orderID, order, domain.OrderActionPay,
+			paymentMatchesOrder(result), s.removeReservation)
+		if errors.Is(err, domain.ErrPayStatusOrder) || errors.Is(err, domain.ErrPaymentAmountMismatch) {
+			refundErr = err
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf("orderStates.Fire: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("txManager.ReadCommitted: %w", err)
+	}
+
+	if refundErr == nil {
+		return nil
+	}
+
+	if err = s.refundIntent(ctx, orderID, result); err != nil {
+		return err
+	}
+
+	return refundErr
+}
+
+func paymentMatchesOrder(result domain.PaymentResult) domain.OrderHook {
+	return func(_ context.Context, _ int64, order domain.Order, _ domain.OrderStatus) error {
+		if result.Amount != order.TotalPrice || result.Currency != order.Currency {
+			return domain.ErrPaymentAmountMismatch
+		}
+
+		return nil
+	}
+}
+
+func (s *Service) removeReservation(ctx context.Context, orderID int64, _ domain.Order, _ domain.OrderStatus) error {
+	allocations, err := s.orderRepository.GetAllocationsByOrderID(ctx, orderID)
+	if err != nil {
+		return fmt.Errorf("orderRepository.GetAllocationsByOrderID: %w", err)
+	}
+
+	if err = s.changeStock(ctx, domain.StockMovementReserveRemove, orderID, allocations, s.stockService.ReserveRemove); err != nil {
+		return fmt.Errorf("stockService.ReserveRemove: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) refundIntent(ctx context.Context, orderID int64, captured domain.PaymentResult) error {
+	refunded, err := s.paymentGateway.Refund(
+		ctx, captured.ProviderPaymentID, captured.Amount, captured.Currency, refundIdempotencyKey(orderID))
+	if err != nil {
+		return fmt.Errorf("%w: paymentGateway.Refund: %v", domain.ErrPaymentUnavailable, err)
+	}
+
+	if err = s.paymentRepository.UpdatePaymentIntent(ctx, orderID, refunded); err != nil {
+		return fmt.Errorf("paymentRepository.UpdatePaymentIntent: %w", err)
+	}
+
+	return nil
+}
+
+// refundIdempotencyKey is derived from the order payment intent, the provider
+// refunds a repeated request with the same key only once.
+func refundIdempotencyKey(orderID int64) string {
+	return fmt.Sprintf("order-%d-refund", orderID)
+}
@@ -0,0 +1,184 @@
+package order_test
+
+import (
+	"context"
+	"fmt"
+	"route256/loms/internal/domain"
+	txmanager "route256/loms/internal/infra/tx_manager"
+	testhelpers "route256/loms/internal/tool"
+	"testing"
+
+	"github.com/gojuno/minimock/v3"
+)
+
+func TestConfirmPayment(t *testing.T) {
+	t.Parallel()
+
+	testOrderID := int64(12345)
+	testAllocations := []domain.Allocation{
+		{Sku: 1001, WarehouseID: 1, Count: 2},
+	}
+
+	type mocks struct {
+		mockGetPaymentIntent      testhelpers.NeedCallWithErrAndResult[domain.PaymentIntent]
+		mockGetByOrderIDForUpdate testhelpers.NeedCallWithErrAndResult[domain.OrderStatus]
+		mockUpdatePaymentIntent   testhelpers.NeedCallWithErr
+		mockCompletePayment       testhelpers.NeedCallWithErr
+		mockRefund                testhelpers.NeedCallWithErr
+		mockUpdatePaymentIntents  []domain.PaymentStatus
+	}
+
+	pendingIntent := domain.PaymentIntent{
+		OrderID:           testOrderID,
+		Provider:          testPaymentProvider,
+		ProviderPaymentID: testProviderPaymentID,
+		Status:            domain.PaymentStatusPending,
+	}
+
+	testCases := []struct {
+		name        string
+		result      domain.PaymentResult
+		mocks       mocks
+		expectedErr error
+	}{
+		{
+			name:   "success: orderservice.ConfirmPayment captured payment pays order",
+			result: domain.PaymentResult{ProviderPaymentID: testProviderPaymentID, Status: domain.PaymentStatusCaptured},
+			mocks: mocks{
+				mockGetPaymentIntent:      testhelpers.NewNeedCallWithErrAndResult(pendingIntent, nil),
+				mockGetByOrderIDForUpdate: testhelpers.NewNeedCallWithErrAndResult(domain.OrderStatusAwaitingPayment, nil),
+				mockUpdatePaymentIntent:   testhelpers.NewNeedCallWithErr(nil),
+				mockCompletePayment:       testhelpers.NewNeedCallWithErr(nil),
+			},
+		},
+		{
+			name:   "success: orderservice.ConfirmPayment duplicate webhook is ignored",
+			result: domain.PaymentResult{ProviderPaymentID: testProviderPaymentID, Status: domain.PaymentStatusCaptured},
+			mocks: mocks{
+				mockGetPaymentIntent: testhelpers.NewNeedCallWithErrAndResult(domain.PaymentIntent{
+					OrderID:           testOrderID,
+					ProviderPaymentID: testProviderPaymentID,
+					Status:            domain.PaymentStatusCaptured,
+				}, nil),
+			},
+		},
+		{
+			name:   "success: orderservice.ConfirmPayment declined payment is recorded",
+			result: domain.PaymentResult{ProviderPaymentID: testProviderPaymentID, Status: domain.PaymentStatusDeclined},
+			mocks: mocks{
+				mockGetPaymentIntent:    testhelpers.NewNeedCallWithErrAndResult(pendingIntent, nil),
+				mockUpdatePaymentIntent: testhelpers.NewNeedCallWithErr(nil),
+			},
+		},
+		{
+			name:   "fail: orderservice.ConfirmPayment captured payment for cancelled order is refunded",
+			result: domain.PaymentResult{ProviderPaymentID: testProviderPaymentID, Status: domain.PaymentStatusCaptured},
+			mocks: mocks{
+				mockGetPaymentIntent:      testhelpers.NewNeedCallWithErrAndResult(pendingIntent, nil),
+				mockGetByOrderIDForUpdate: testhelpers.NewNeedCallWithErrAndResult(domain.OrderStatusCancelled, nil),
+				mockRefund:                testhelpers.NewNeedCallWithErr(nil),
+				mockUpdatePaymentIntents: []domain.PaymentStatus{
+					domain.PaymentStatusCaptured,
+					domain.PaymentStatusRefunded,
+				},
+			},
+			expectedErr: domain.ErrPayStatusOrder,
+		},
+		{
+			name: "fail: orderservice.ConfirmPayment captured amount differs from payment intent",
+			result: domain.PaymentResult{
+				ProviderPaymentID: testProviderPaymentID,
+				Status:            domain.PaymentStatusCaptured,
+				Amount:            100,
+				Currency:          domain.DefaultCurrency,
+			},
+			mocks: mocks{
+				mockGetPaymentIntent: testhelpers.NewNeedCallWithErrAndResult(pendingIntent, nil),
+			},
+			expectedErr: domain.ErrPaymentAmountMismatch,
+		},
+		{
+			name:   "fail: orderservice.ConfirmPayment unknown provider payment id",
+			result: domain.PaymentResult{ProviderPaymentID: "other", Status: domain.PaymentStatusCaptured},
+			mocks: mocks{
+				mockGetPaymentIntent: testhelpers.NewNeedCallWithErrAndResult(pendingIntent, nil),
+			},
+			expectedErr: domain.ErrPaymentNotFound,
+		},
+		{
+			name:   "fail: orderservice.ConfirmPayment GetPaymentIntent error",
+			result: domain.PaymentResult{ProviderPaymentID: testProviderPaymentID, Status: domain.PaymentStatusCaptured},
+			mocks: mocks{
+				mockGetPaymentIntent: testhelpers.NewNeedCallWithErrAndResult(domain.PaymentIntent{}, domain.ErrPaymentNotFound),
+			},
+			expectedErr: domain.ErrPaymentNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			f := setUp(t)
+			ctx := context.Background()
+
+			if tc.mocks.mockGetPaymentIntent.NeedCall {
+				f.paymentRepository.GetPaymentIntentMock.
+					Expect(minimock.AnyContext, testOrderID).
+					Return(tc.mocks.mockGetPaymentIntent.Result, tc.mocks.mockGetPaymentIntent.Err)
+			}
+
+			if tc.mocks.mockGetByOrderIDForUpdate.NeedCall {
+				f.txManager.ReadCommittedMock.Set(func(ctx context.Context, fn txmanager.Handler) error {
+					return fn(ctx)
+				})
+				f.orderRepository.GetByOrderIDForUpdateMock.
+					Expect(minimock.AnyContext, testOrderID).
+					Return(domain.Order{UserID: 1, Status: tc.mocks.mockGetByOrderIDForUpdate.Result},
+						tc.mocks.mockGetByOrderIDForUpdate.Err)
+			}
+
+			if tc.mocks.mockUpdatePaymentIntent.NeedCall {
+				f.paymentRepository.UpdatePaymentIntentMock.
+					Expect(minimock.AnyContext, testOrderID, tc.result).
+					Return(tc.mocks.mockUpdatePaymentIntent.Err)
+			}
+
+			for _, status := range tc.mocks.mockUpdatePaymentIntents {
+				f.paymentRepository.UpdatePaymentIntentMock.
+					When(minimock.AnyContext, testOrderID, domain.PaymentResult{
+						ProviderPaymentID: testProviderPaymentID,
+						Status:            status,
+					}).
+					Then(nil)
+			}
+
+			if tc.mocks.mockCompletePayment.NeedCall {
+				f.orderRepository.GetAllocationsByOrderIDMock.
+					Expect(minimock.AnyContext, testOrderID).
+					Return(testAllocations, nil)
+				f.stockService.ReserveRemoveMock.
+					Expect(minimock.AnyContext, testOrderID, testAllocations).
+					Return(nil)
+				f.orderRepository.SetStatusAndCreateEventMock.Return(tc.mocks.mockCompletePayment.Err)
+			}
+
+			if tc.mocks.mockRefund.NeedCall {
+				f.paymentGateway.RefundMock.
+					Expect(minimock.AnyContext, testProviderPaymentID, int64(0), "", fmt.Sprintf("order-%d-refund", testOrderID)).
+					Return(domain.PaymentResult{
+						ProviderPaymentID: testProviderPaymentID,
+						Status:            domain.PaymentStatusRefunded,
+					}, tc.mocks.mockRefund.Err)
+			}
+
+			err := f.executor.ConfirmPayment(ctx, testOrderID, tc.result)
+
+			if tc.expectedErr != nil {
+				f.ErrorIs(err, tc.expectedErr)
+			} else {
+				f.NoError(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"route256/loms/internal/domain"
+	"route256/loms/internal/infra/sharding"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+func (s *Service) OrderRefund(ctx context.Context, orderID int64, restock bool) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "orderService.OrderRefund")
+	defer span.Finish()
+
+	ctx = sharding.WithID(ctx, orderID)
+
+	var (
+		order    domain.Order
+		refunded bool
+	)
+
+	err := s.readCommitted(ctx, func(ctx context.Context) error {
+		var err error
+
+		order, err = s.orderRepository.GetByOrderIDForUpdate(ctx, orderID)
+		if err != nil {
+			return fmt.Errorf("orderRepository.GetByOrderIDForUpdate: %w", err)
+		}
+
+		if s.orderStates.Done(order.Status, domain.OrderActionRefund) {
+			refunded = true
+			return nil
+		}
+
+		if err = s.orderStates.Fire(ctx, orderID, order, domain.OrderActionRequestRefund); err != nil {
+			return fmt.Errorf("orderStates.Fire: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("txManager.ReadCommitted: %w", err)
+	}
+
+	if refunded {
+		return nil
+	}
+
+	// The provider is called outside of the order transaction: the requested refund
+	// status claims the order, and the idempotency key keeps concurrent or retried
+	// calls from refunding the payment twice.
+	if err = s.refundPayment(ctx, orderID, order); err != nil {
+		return fmt.Errorf("refundPayment: %w", err)
+	}
+
+	err = s.readCommitted(ctx, func(ctx context.Context) error {
+		order, err := s.orderRepository.GetByOrderIDForUpdate(ctx, orderID)
+		if err != nil {
+			return fmt.Errorf("orderRepository.GetByOrderIDForUpdate: %w", err)
+		}
+
+		if s.orderStates.Done(order.Status, domain.OrderActionRefund) {
+			return nil
+		}
+
+		return s.completeRefund(ctx, orderID, order, restock)
+	})
+
+	if err != nil {
+		return fmt.Errorf("txManager.ReadCommitted: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) refundPayment(ctx context.Context, orderID int64, order domain.Order) error {
+	intent, err := s.paymentRepository.GetPaymentIntent(ctx, orderID)
+	if errors.Is(err, domain.ErrPaymentNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("paymentRepository.GetPaymentIntent: %w", err)
+	}
+
+	if intent.Status != domain.PaymentStatusCaptured {
+		return nil
+	}
+
+	if intent.Amount != order.TotalPrice || intent.Currency != order.Currency {
+		return domain.ErrPaymentAmountMismatch
+	}
+
+	return s.refundIntent(ctx, orderID, domain.PaymentResult{
+		ProviderPaymentID: intent.ProviderPaymentID,
+		Amount:            order.TotalPrice,
+		Currency:          order.Currency,
+	})
+}
+
+func (s *Service) completeRefund(ctx context.Context, orderID int64, order domain.Order, restock bool) error {
+	var hooks []domain.OrderHook
+	if restock {
+		hooks = append(hooks, s.restockItems)
+	}
+
+	if err := s.orderStates.Fire(ctx, orderID, order, domain.OrderActionRefund, hooks...); err != nil {
+		return fmt.Errorf("orderStates.Fire: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) restockItems(ctx context.Context, orderID int64, order domain.Order, _ domain.OrderStatus) error {
+	items := make([]domain.Item, 0, len(order.Items))
+	for _, item := range order.Items {
+		if item.Count > 0 {
+			items = append(items, item)
+		}
+	}
+
+	if len(items) == 0 {
+		return nil
+	}
+
+	return s.returnItems(ctx, orderID, order, items)
+}
@@ -0,0 +1,236 @@
+package order_test
+
+import (
+	"context"
+	"fmt"
+	"route256/loms/internal/domain"
+	txmanager "route256/loms/internal/infra/tx_manager"
+	testhelpers "route256/loms/internal/tool"
+	"testing"
+
+	"github.com/gojuno/minimock/v3"
+)
+
+func TestOrderRefund(t *testing.T) {
+	t.Parallel()
+
+	testOrderID := int64(12345)
+	testItems := []domain.Item{
+		{Sku: 1001, Count: 2},
+		{Sku: 1002, Count: 0},
+	}
+	testReturned := []domain.Item{{Sku: 1001, Count: 2}}
+	testAllocations := []domain.Allocation{
+		{Sku: 1001, WarehouseID: 1, Count: 2},
+	}
+
+	testTotalPrice := int64(300)
+
+	capturedIntent := domain.PaymentIntent{
+		OrderID:           testOrderID,
+		ProviderPaymentID: testProviderPaymentID,
+		Status:            domain.PaymentStatusCaptured,
+		Amount:            testTotalPrice,
+		Currency:          domain.DefaultCurrency,
+	}
+	partialIntent := capturedIntent
+	partialIntent.Amount = testTotalPrice - 1
+	refundedIntent := capturedIntent
+	refundedIntent.Status = domain.PaymentStatusRefunded
+
+	type mocks struct {
+		mockGetPaymentIntent testhelpers.NeedCallWithErrAndResult[domain.PaymentIntent]
+		mockRefund           testhelpers.NeedCallWithErr
+		mockRestock          bool
+	}
+
+	testCases := []struct {
+		name             string
+		status           domain.OrderStatus
+		lockedStatus     domain.OrderStatus
+		restock          bool
+		mocks            mocks
+		expectedStatuses []domain.OrderStatus
+		expectedErr      error
+	}{
+		{
+			name:   "success: orderservice.OrderRefund",
+			status: domain.OrderStatusPayed,
+			mocks: mocks{
+				mockGetPaymentIntent: testhelpers.NewNeedCallWithErrAndResult(capturedIntent, nil),
+				mockRefund:           testhelpers.NewNeedCallWithErr(nil),
+			},
+			expectedStatuses: []domain.OrderStatus{domain.OrderStatusRefundRequested, domain.OrderStatusRefunded},
+		},
+		{
+			name:    "success: orderservice.OrderRefund with restock",
+			status:  domain.OrderStatusPayed,
+			restock: true,
+			mocks: mocks{
+				mockGetPaymentIntent: testhelpers.NewNeedCallWithErrAndResult(capturedIntent, nil),
+				mockRefund:           testhelpers.NewNeedCallWithErr(nil),
+				mockRestock:          true,
+			},
+			expectedStatuses: []domain.OrderStatus{domain.OrderStatusRefundRequested, domain.OrderStatusRefunded},
+		},
+		{
+			name:   "success: orderservice.OrderRefund order without payment intent",
+			status: domain.OrderStatusPayed,
+			mocks: mocks{
+				mockGetPaymentIntent: testhelpers.NewNeedCallWithErrAndResult(domain.PaymentIntent{}, domain.ErrPaymentNotFound),
+			},
+			expectedStatuses: []domain.OrderStatus{domain.OrderStatusRefundRequested, domain.OrderStatusRefunded},
+		},
+		{
+			name:   "success: orderservice.OrderRefund retries requested refund",
+			status: domain.OrderStatusRefundRequested,
+			mocks: mocks{
+				mockGetPaymentIntent: testhelpers.NewNeedCallWithErrAndResult(capturedIntent, nil),
+				mockRefund:           testhelpers.NewNeedCallWithErr(nil),
+			},
+			expectedStatuses: []domain.OrderStatus{domain.OrderStatusRefunded},
+		},
+		{
+			name:         "success: orderservice.OrderRefund concurrent call refunded order first",
+			status:       domain.OrderStatusRefundRequested,
+			lockedStatus: domain.OrderStatusRefunded,
+			mocks: mocks{
+				mockGetPaymentIntent: testhelpers.NewNeedCallWithErrAndResult(refundedIntent, nil),
+			},
+		},
+		{
+			name:   "success: orderservice.OrderRefund completes refund after provider refunded payment",
+			status: domain.OrderStatusRefundRequested,
+			mocks: mocks{
+				mockGetPaymentIntent: testhelpers.NewNeedCallWithErrAndResult(refundedIntent, nil),
+			},
+			expectedStatuses: []domain.OrderStatus{domain.OrderStatusRefunded},
+		},
+		{
+			name:   "success: orderservice.OrderRefund already refunded order returns nil",
+			status: domain.OrderStatusRefunded,
+		},
+		{
+			name:        "fail: orderservice.OrderRefund order in wrong status",
+			status:      domain.OrderStatusAwaitingPayment,
+			expectedErr: domain.ErrRefundStatusOrder,
+		},
+		{
+			name:   "fail: orderservice.OrderRefund payment gateway error",
+			status: domain.OrderStatusPayed,
+			mocks: mocks{
+				mockGetPaymentIntent: testhelpers.NewNeedCallWithErrAndResult(capturedIntent, nil),
+				mockRefund:           testhelpers.NewNeedCallWithErr(testhelpers.ErrForTest),
+			},
+			expectedStatuses: []domain.OrderStatus{domain.OrderStatusRefundRequested},
+			expectedErr:      domain.ErrPaymentUnavailable,
+		},
+		{
+			name:   "fail: orderservice.OrderRefund captured amount differs from order total",
+			status: domain.OrderStatusPayed,
+			mocks: mocks{
+				mockGetPaymentIntent: testhelpers.NewNeedCallWithErrAndResult(partialIntent, nil),
+			},
+			expectedStatuses: []domain.OrderStatus{domain.OrderStatusRefundRequested},
+			expectedErr:      domain.ErrPaymentAmountMismatch,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			f := setUp(t)
+			ctx := context.Background()
+
+			f.txManager.ReadCommittedMock.Set(func(ctx context.Context, fn txmanager.Handler) error {
+				return fn(ctx)
+			})
+
+			current := tc.status
+			var (
+				statuses []domain.OrderStatus
+				locks    int
+			)
+
+			f.orderRepository.GetByOrderIDForUpdateMock.Set(func(_ context.Context, orderID int64) (domain.Order, error) {
+				f.Equal(testOrderID, orderID)
+
+				locks++
+				if locks > 1 && tc.lockedStatus != "" {
+					current = tc.lockedStatus
+				}
+
+				return domain.Order{
+					UserID:     1,
+					Status:     current,
+					Items:      testItems,
+					TotalPrice: testTotalPrice,
+					Currency:   domain.DefaultCurrency,
+				}, nil
+			})
+
+			if len(tc.expectedStatuses) > 0 {
+				f.orderRepository.SetStatusAndCreateEventMock.Set(
+					func(_ context.Context, _ int64, status domain.OrderStatus, _ domain.Event) error {
+						current = status
+						statuses = append(statuses, status)
+
+						return nil
+					})
+			}
+
+			if tc.mocks.mockGetPaymentIntent.NeedCall {
+				f.paymentRepository.GetPaymentIntentMock.
+					Expect(minimock.AnyContext, testOrderID).
+					Return(tc.mocks.mockGetPaymentIntent.Result, tc.mocks.mockGetPaymentIntent.Err)
+			}
+
+			if tc.mocks.mockRefund.NeedCall {
+				refunded := domain.PaymentResult{
+					ProviderPaymentID: testProviderPaymentID,
+					Status:            domain.PaymentStatusRefunded,
+					Amount:            testTotalPrice,
+					Currency:          domain.DefaultCurrency,
+				}
+
+				f.paymentGateway.RefundMock.
+					Expect(minimock.AnyContext, testProviderPaymentID, testTotalPrice, domain.DefaultCurrency,
+						fmt.Sprintf("order-%d-refund", testOrderID)).
+					Return(refunded, tc.mocks.mockRefund.Err)
+
+				if tc.mocks.mockRefund.Err == nil {
+					f.paymentRepository.UpdatePaymentIntentMock.
+						Expect(minimock.AnyContext, testOrderID, refunded).
+						Return(nil)
+				}
+			}
+
+			if tc.mocks.mockRestock {
+				f.orderRepository.GetAllocationsByOrderIDMock.
+					Expect(minimock.AnyContext, testOrderID).
+					Return(testAllocations, nil)
+				f.stockService.ReturnToStockMock.
+					Expect(minimock.AnyContext, testOrderID, testAllocations).
+					Return(nil)
+				f.orderRepository.ReturnOrderItemsMock.
+					Expect(minimock.AnyContext, testOrderID, testReturned).
+					Return(nil)
+				f.orderRepository.DecreaseOrderAllocationsMock.
+					Expect(minimock.AnyContext, testOrderID, testAllocations).
+					Return(nil)
+				f.eventRepository.CreateEventMock.Return(nil)
+			}
+
+			err := f.executor.OrderRefund(ctx, testOrderID, tc.restock)
+
+			if tc.expectedErr != nil {
+				f.ErrorIs(err, tc.expectedErr)
+			} else {
+				f.NoError(err)
+			}
+
+			f.Equal(tc.expectedStatuses, statuses)
+		})
+	}
+}
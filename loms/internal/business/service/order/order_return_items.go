@@ -0,0 +1,66 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"route256/loms/internal/domain"
+	"route256/loms/internal/infra/sharding"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+func (s *Service) OrderReturnItems(ctx context.Context, orderID int64, items []domain.Item) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "orderService.OrderReturnItems")
+	defer span.Finish()
+
+	ctx = sharding.WithID(ctx, orderID)
+	items = domain.MergeItems(items)
+
+	err := s.readCommitted(ctx, func(ctx context.Context) error {
+		order, err := s.orderRepository.GetByOrderIDForUpdate(ctx, orderID)
+		if err != nil {
+			return fmt.Errorf("orderRepository.GetByOrderIDForUpdate: %w", err)
+		}
+
+		if err = s.orderStates.Validate(order.Status, domain.OrderActionReturnItems); err != nil {
+			return err
+		}
+
+		if _, err = subtractItems(order.Items, items); err != nil {
+			return err
+		}
+
+		return s.returnItems(ctx, orderID, order, items)
+	})
+
+	if err != nil {
+		return fmt.Errorf("txManager.ReadCommitted: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) returnItems(ctx context.Context, orderID int64, order domain.Order, items []domain.Item) error {
+	released, err := s.releaseAllocations(ctx, orderID, items)
+	if err != nil {
+		return err
+	}
+
+	if err = s.changeStock(ctx, domain.StockMovementReturn, orderID, released, s.stockService.ReturnToStock); err != nil {
+		return fmt.Errorf("stockService.ReturnToStock: %w", err)
+	}
+
+	if err = s.orderRepository.ReturnOrderItems(ctx, orderID, items); err != nil {
+		return fmt.Errorf("orderRepository.ReturnOrderItems: %w", err)
+	}
+
+	if err = s.orderRepository.DecreaseOrderAllocations(ctx, orderID, released); err != nil {
+		return fmt.Errorf("orderRepository.DecreaseOrderAllocations: %w", err)
+	}
+
+	if err = s.createItemsEvent(ctx, orderID, order, orderEventItemsReturned, items); err != nil {
+		return fmt.Errorf("createItemsEvent: %w", err)
+	}
+
+	return nil
+}
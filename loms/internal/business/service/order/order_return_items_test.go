@@ -0,0 +1,246 @@
+package order_test
+
+import (
+	"context"
+	"fmt"
+	"route256/loms/internal/domain"
+	txmanager "route256/loms/internal/infra/tx_manager"
+	eventsv1 "route256/loms/internal/pb/events/v1"
+	testhelpers "route256/loms/internal/tool"
+	"testing"
+
+	"github.com/gojuno/minimock/v3"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestOrderReturnItems(t *testing.T) {
+	t.Parallel()
+
+	testOrderID := int64(12345)
+	testOrder := domain.Order{
+		UserID: 1,
+		Status: domain.OrderStatusPayed,
+		Items: []domain.Item{
+			{Sku: 1001, Count: 2},
+			{Sku: 1002, Count: 1},
+		},
+	}
+
+	testAllocations := []domain.Allocation{
+		{Sku: 1001, WarehouseID: 1, Count: 1},
+		{Sku: 1001, WarehouseID: 3, Count: 1},
+		{Sku: 1002, WarehouseID: 1, Count: 1},
+	}
+
+	testItems := []domain.Item{{Sku: 1001, Count: 2}}
+	testReleased := []domain.Allocation{
+		{Sku: 1001, WarehouseID: 3, Count: 1},
+		{Sku: 1001, WarehouseID: 1, Count: 1},
+	}
+
+	type mocks struct {
+		mockGetByOrderIDForUpdate    testhelpers.NeedCallWithErr
+		mockGetAllocationsByOrderID  testhelpers.NeedCallWithErr
+		mockReturnToStock            testhelpers.NeedCallWithErr
+		mockReturnOrderItems         testhelpers.NeedCallWithErr
+		mockDecreaseOrderAllocations testhelpers.NeedCallWithErr
+		mockCreateEvent              testhelpers.NeedCallWithErr
+	}
+
+	testCases := []struct {
+		name        string
+		order       domain.Order
+		items       []domain.Item
+		mocks       mocks
+		expectedErr error
+	}{
+		{
+			name:  "success: orderservice.OrderReturnItems",
+			order: testOrder,
+			items: testItems,
+			mocks: mocks{
+				mockGetByOrderIDForUpdate:    testhelpers.NewNeedCallWithErr(nil),
+				mockGetAllocationsByOrderID:  testhelpers.NewNeedCallWithErr(nil),
+				mockReturnToStock:            testhelpers.NewNeedCallWithErr(nil),
+				mockReturnOrderItems:         testhelpers.NewNeedCallWithErr(nil),
+				mockDecreaseOrderAllocations: testhelpers.NewNeedCallWithErr(nil),
+				mockCreateEvent:              testhelpers.NewNeedCallWithErr(nil),
+			},
+			expectedErr: nil,
+		},
+		{
+			name:  "success: orderservice.OrderReturnItems duplicate sku is merged",
+			order: testOrder,
+			items: []domain.Item{{Sku: 1001, Count: 1}, {Sku: 1001, Count: 1}},
+			mocks: mocks{
+				mockGetByOrderIDForUpdate:    testhelpers.NewNeedCallWithErr(nil),
+				mockGetAllocationsByOrderID:  testhelpers.NewNeedCallWithErr(nil),
+				mockReturnToStock:            testhelpers.NewNeedCallWithErr(nil),
+				mockReturnOrderItems:         testhelpers.NewNeedCallWithErr(nil),
+				mockDecreaseOrderAllocations: testhelpers.NewNeedCallWithErr(nil),
+				mockCreateEvent:              testhelpers.NewNeedCallWithErr(nil),
+			},
+		},
+		{
+			name:  "fail: orderservice.OrderReturnItems GetByOrderIDForUpdate error",
+			order: domain.Order{},
+			items: testItems,
+			mocks: mocks{
+				mockGetByOrderIDForUpdate: testhelpers.NewNeedCallWithErr(testhelpers.ErrForTest),
+			},
+			expectedErr: testhelpers.ErrForTest,
+		},
+		{
+			name: "fail: order is not payed",
+			order: domain.Order{
+				UserID: 1,
+				Status: domain.OrderStatusAwaitingPayment,
+				Items:  testOrder.Items,
+			},
+			items: testItems,
+			mocks: mocks{
+				mockGetByOrderIDForUpdate: testhelpers.NewNeedCallWithErr(nil),
+			},
+			expectedErr: domain.ErrReturnItemsStatus,
+		},
+		{
+			name:  "fail: count exceeds count in order",
+			order: testOrder,
+			items: []domain.Item{{Sku: 1001, Count: 3}},
+			mocks: mocks{
+				mockGetByOrderIDForUpdate: testhelpers.NewNeedCallWithErr(nil),
+			},
+			expectedErr: domain.ErrInvalidOrderItems,
+		},
+		{
+			name:  "fail: orderservice.OrderReturnItems GetAllocationsByOrderID error",
+			order: testOrder,
+			items: testItems,
+			mocks: mocks{
+				mockGetByOrderIDForUpdate:   testhelpers.NewNeedCallWithErr(nil),
+				mockGetAllocationsByOrderID: testhelpers.NewNeedCallWithErr(testhelpers.ErrForTest),
+			},
+			expectedErr: testhelpers.ErrForTest,
+		},
+		{
+			name:  "fail: orderservice.OrderReturnItems ReturnToStock error",
+			order: testOrder,
+			items: testItems,
+			mocks: mocks{
+				mockGetByOrderIDForUpdate:   testhelpers.NewNeedCallWithErr(nil),
+				mockGetAllocationsByOrderID: testhelpers.NewNeedCallWithErr(nil),
+				mockReturnToStock:           testhelpers.NewNeedCallWithErr(testhelpers.ErrForTest),
+			},
+			expectedErr: testhelpers.ErrForTest,
+		},
+		{
+			name:  "fail: orderservice.OrderReturnItems ReturnOrderItems error",
+			order: testOrder,
+			items: testItems,
+			mocks: mocks{
+				mockGetByOrderIDForUpdate:   testhelpers.NewNeedCallWithErr(nil),
+				mockGetAllocationsByOrderID: testhelpers.NewNeedCallWithErr(nil),
+				mockReturnToStock:           testhelpers.NewNeedCallWithErr(nil),
+				mockReturnOrderItems:        testhelpers.NewNeedCallWithErr(testhelpers.ErrForTest),
+			},
+			expectedErr: testhelpers.ErrForTest,
+		},
+		{
+			name:  "fail: orderservice.OrderReturnItems DecreaseOrderAllocations error",
+			order: testOrder,
+			items: testItems,
+			mocks: mocks{
+				mockGetByOrderIDForUpdate:    testhelpers.NewNeedCallWithErr(nil),
+				mockGetAllocationsByOrderID:  testhelpers.NewNeedCallWithErr(nil),
+				mockReturnToStock:            testhelpers.NewNeedCallWithErr(nil),
+				mockReturnOrderItems:         testhelpers.NewNeedCallWithErr(nil),
+				mockDecreaseOrderAllocations: testhelpers.NewNeedCallWithErr(testhelpers.ErrForTest),
+			},
+			expectedErr: testhelpers.ErrForTest,
+		},
+		{
+			name:  "fail: orderservice.OrderReturnItems CreateEvent error",
+			order: testOrder,
+			items: testItems,
+			mocks: mocks{
+				mockGetByOrderIDForUpdate:    testhelpers.NewNeedCallWithErr(nil),
+				mockGetAllocationsByOrderID:  testhelpers.NewNeedCallWithErr(nil),
+				mockReturnToStock:            testhelpers.NewNeedCallWithErr(nil),
+				mockReturnOrderItems:         testhelpers.NewNeedCallWithErr(nil),
+				mockDecreaseOrderAllocations: testhelpers.NewNeedCallWithErr(nil),
+				mockCreateEvent:              testhelpers.NewNeedCallWithErr(testhelpers.ErrForTest),
+			},
+			expectedErr: testhelpers.ErrForTest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			f := setUp(t)
+
+			ctx := context.Background()
+
+			f.txManager.ReadCommittedMock.Set(func(ctx context.Context, fn txmanager.Handler) error {
+				return fn(ctx)
+			})
+
+			if tc.mocks.mockGetByOrderIDForUpdate.NeedCall {
+				f.orderRepository.GetByOrderIDForUpdateMock.
+					Expect(minimock.AnyContext, testOrderID).
+					Return(tc.order, tc.mocks.mockGetByOrderIDForUpdate.Err)
+			}
+
+			if tc.mocks.mockGetAllocationsByOrderID.NeedCall {
+				f.orderRepository.GetAllocationsByOrderIDMock.
+					Expect(minimock.AnyContext, testOrderID).
+					Return(testAllocations, tc.mocks.mockGetAllocationsByOrderID.Err)
+			}
+
+			if tc.mocks.mockReturnToStock.NeedCall {
+				f.stockService.ReturnToStockMock.
+					Expect(minimock.AnyContext, testOrderID, testReleased).
+					Return(tc.mocks.mockReturnToStock.Err)
+			}
+
+			if tc.mocks.mockReturnOrderItems.NeedCall {
+				f.orderRepository.ReturnOrderItemsMock.
+					Expect(minimock.AnyContext, testOrderID, testItems).
+					Return(tc.mocks.mockReturnOrderItems.Err)
+			}
+
+			if tc.mocks.mockDecreaseOrderAllocations.NeedCall {
+				f.orderRepository.DecreaseOrderAllocationsMock.
+					Expect(minimock.AnyContext, testOrderID, testReleased).
+					Return(tc.mocks.mockDecreaseOrderAllocations.Err)
+			}
+
+			if tc.mocks.mockCreateEvent.NeedCall {
+				f.eventRepository.CreateEventMock.Set(func(_ context.Context, event domain.Event) error {
+					var payload eventsv1.OrderEvent
+					if err := proto.Unmarshal(event.Payload, &payload); err != nil {
+						return err
+					}
+					if payload.GetType() != eventsv1.OrderEventType_ORDER_EVENT_TYPE_ITEMS_RETURNED {
+						return fmt.Errorf("unexpected event: got %v", payload.GetType())
+					}
+					if payload.GetOrderId() != testOrderID {
+						return fmt.Errorf("unexpected orderID: got %d", payload.GetOrderId())
+					}
+
+					return tc.mocks.mockCreateEvent.Err
+				})
+			}
+
+			err := f.executor.OrderReturnItems(ctx, testOrderID, tc.items)
+
+			if tc.expectedErr != nil {
+				f.Error(err)
+				f.ErrorContains(err, tc.expectedErr.Error())
+			} else {
+				f.NoError(err)
+			}
+		})
+	}
+}
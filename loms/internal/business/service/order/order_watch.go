@@ -0,0 +1,103 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"route256/loms/internal/business/service/order/watch"
+	"route256/loms/internal/domain"
+	"route256/loms/internal/infra/sharding"
+	txmanager "route256/loms/internal/infra/tx_manager"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+type statusChangesKey struct{}
+
+type statusChanges struct {
+	updates []domain.OrderStatusUpdate
+}
+
+func (s *Service) WatchOrder(ctx context.Context, orderID int64) (domain.OrderStatusUpdate, *watch.Subscription, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "orderService.WatchOrder")
+	defer span.Finish()
+
+	ctx = sharding.WithID(ctx, orderID)
+
+	// Subscribe before reading so that a transition committed in between is not lost.
+	sub := s.statusBroadcaster.Subscribe(orderID)
+
+	var order domain.Order
+	err := s.txManagerMaster.ReadCommitted(ctx, func(ctx context.Context) error {
+		var err error
+		order, err = s.orderRepository.GetByOrderID(ctx, orderID)
+		if err != nil {
+			return fmt.Errorf("orderRepository.GetByOrderID: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		sub.Close()
+		return domain.OrderStatusUpdate{}, nil, fmt.Errorf("txManager.ReadCommitted: %w", err)
+	}
+
+	current := domain.OrderStatusUpdate{
+		OrderID:   orderID,
+		Status:    order.Status,
+		ChangedAt: order.UpdatedAt,
+	}
+
+	return current, sub, nil
+}
+
+func (s *Service) readCommitted(ctx context.Context, fnc txmanager.Handler) error {
+	if _, ok := ctx.Value(statusChangesKey{}).(*statusChanges); ok {
+		return s.txManagerMaster.ReadCommitted(ctx, fnc)
+	}
+
+	changes := &statusChanges{}
+	ctx = context.WithValue(ctx, statusChangesKey{}, changes)
+
+	stock := &stockChanges{}
+	ctx = context.WithValue(ctx, stockChangesKey{}, stock)
+
+	err := s.txManagerMaster.ReadCommitted(ctx, func(ctx context.Context) error {
+		changes.updates = changes.updates[:0]
+
+		if err := s.compensateStockChanges(ctx, stock); err != nil {
+			return err
+		}
+
+		return fnc(ctx)
+	})
+	if err != nil {
+		if errCompensate := s.compensateStockChanges(ctx, stock); errCompensate != nil {
+			return errors.Join(err, errCompensate)
+		}
+
+		return err
+	}
+
+	for _, update := range changes.updates {
+		s.statusBroadcaster.Publish(update)
+	}
+
+	return nil
+}
+
+func (s *Service) recordStatus(ctx context.Context, orderID int64, status domain.OrderStatus) {
+	update := domain.OrderStatusUpdate{
+		OrderID:   orderID,
+		Status:    status,
+		ChangedAt: time.Now(),
+	}
+
+	if changes, ok := ctx.Value(statusChangesKey{}).(*statusChanges); ok {
+		changes.updates = append(changes.updates, update)
+		return
+	}
+
+	s.statusBroadcaster.Publish(update)
+}
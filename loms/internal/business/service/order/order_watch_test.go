@@ -0,0 +1,141 @@
+package order_test
+
+import (
+	"context"
+	"route256/loms/internal/domain"
+	txmanager "route256/loms/internal/infra/tx_manager"
+	testhelpers "route256/loms/internal/tool"
+	"testing"
+	"time"
+
+	"github.com/gojuno/minimock/v3"
+)
+
+func TestWatchOrder(t *testing.T) {
+	t.Parallel()
+
+	testOrderID := int64(12345)
+	testUpdatedAt := time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name        string
+		repoErr     error
+		expectedErr error
+	}{
+		{
+			name: "success: orderservice.WatchOrder returns current status",
+		},
+		{
+			name:        "fail: orderservice.WatchOrder GetByOrderID error",
+			repoErr:     domain.ErrOrderNotFound,
+			expectedErr: domain.ErrOrderNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			f := setUp(t)
+			ctx := context.Background()
+
+			f.txManager.ReadCommittedMock.Set(func(ctx context.Context, fn txmanager.Handler) error {
+				return fn(ctx)
+			})
+
+			f.orderRepository.GetByOrderIDMock.
+				Expect(minimock.AnyContext, testOrderID).
+				Return(domain.Order{Status: domain.OrderStatusAwaitingPayment, UpdatedAt: testUpdatedAt}, tc.repoErr)
+
+			current, sub, err := f.executor.WatchOrder(ctx, testOrderID)
+
+			if tc.expectedErr != nil {
+				f.ErrorIs(err, tc.expectedErr)
+				f.Nil(sub)
+				return
+			}
+
+			f.NoError(err)
+			defer sub.Close()
+
+			f.Equal(domain.OrderStatusUpdate{
+				OrderID:   testOrderID,
+				Status:    domain.OrderStatusAwaitingPayment,
+				ChangedAt: testUpdatedAt,
+			}, current)
+		})
+	}
+}
+
+func TestOrderStatusPublishedAfterCommit(t *testing.T) {
+	t.Parallel()
+
+	testOrderID := int64(12345)
+
+	testCases := []struct {
+		name            string
+		commitErr       error
+		expectPublished bool
+	}{
+		{
+			name:            "success: status is published after commit",
+			expectPublished: true,
+		},
+		{
+			name:      "success: status is not published when commit fails",
+			commitErr: testhelpers.ErrForTest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			f := setUp(t)
+			ctx := context.Background()
+
+			sub := f.statusBroadcaster.Subscribe(testOrderID)
+			defer sub.Close()
+
+			f.txManager.ReadCommittedMock.Set(func(ctx context.Context, fn txmanager.Handler) error {
+				if err := fn(ctx); err != nil {
+					return err
+				}
+
+				f.Empty(sub.Updates())
+
+				return tc.commitErr
+			})
+
+			f.orderRepository.GetByOrderIDForUpdateMock.
+				Expect(minimock.AnyContext, testOrderID).
+				Return(domain.Order{UserID: 1, Status: domain.OrderStatusAwaitingPayment}, nil)
+			f.expectNewPaymentIntent(testOrderID)
+
+			if tc.expectPublished {
+				f.expectAuthorize(testOrderID, domain.PaymentStatusCaptured, nil)
+				f.paymentRepository.UpdatePaymentIntentMock.Return(nil)
+				f.orderRepository.GetAllocationsByOrderIDMock.
+					Expect(minimock.AnyContext, testOrderID).
+					Return(nil, nil)
+				f.stockService.ReserveRemoveMock.Return(nil)
+				f.orderRepository.SetStatusAndCreateEventMock.Return(nil)
+			}
+
+			err := f.executor.OrderPay(ctx, testOrderID)
+
+			if !tc.expectPublished {
+				f.ErrorIs(err, tc.commitErr)
+				f.Empty(sub.Updates())
+				return
+			}
+
+			f.NoError(err)
+			f.Len(sub.Updates(), 1)
+
+			update := <-sub.Updates()
+			f.Equal(testOrderID, update.OrderID)
+			f.Equal(domain.OrderStatusPayed, update.Status)
+		})
+	}
+}
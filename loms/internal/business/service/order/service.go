@@ -2,8 +2,10 @@ package order
 
 import (
 	"context"
+	"route256/loms/internal/business/service/order/watch"
 	"route256/loms/internal/domain"
 	txmanager "route256/loms/internal/infra/tx_manager"
+	"time"
 )
 
 //go:generate rm -rf mock
@@ -14,6 +16,18 @@ type orderRepository interface {
 	CreateOrderItems(ctx context.Context, orderID int64, items []domain.Item) error
 	GetByOrderID(ctx context.Context, orderID int64) (domain.Order, error)
 	GetByOrderIDForUpdate(ctx context.Context, orderID int64) (domain.Order, error)
+	ListByUserID(ctx context.Context, filter domain.OrderListFilter) ([]domain.Order, error)
+	GetExpiredUnpaidOrderIDsForUpdate(
+		ctx context.Context,
+		paymentDeadline time.Duration,
+		excludedIDs []int64,
+		limit int32) ([]int64, error)
+	CreateOrderAllocations(ctx context.Context, orderID int64, allocations []domain.Allocation) error
+	GetAllocationsByOrderID(ctx context.Context, orderID int64) ([]domain.Allocation, error)
+	GetStatusHistory(ctx context.Context, orderID int64) ([]domain.OrderStatusChange, error)
+	DecreaseOrderAllocations(ctx context.Context, orderID int64, allocations []domain.Allocation) error
+	CancelOrderItems(ctx context.Context, orderID int64, items []domain.Item) error
+	ReturnOrderItems(ctx context.Context, orderID int64, items []domain.Item) error
 	SetStatus(ctx context.Context, orderID int64, status domain.OrderStatus) error
 	SetStatusAndCreateEvent(ctx context.Context, orderID int64, status domain.OrderStatus, event domain.Event) error
 }
@@ -23,20 +37,51 @@ type txManager interface {
 }
 
 type stockService interface {
-	Reserve(ctx context.Context, items []domain.Item) error
-	ReserveRemove(ctx context.Context, items []domain.Item) error
-	ReserveCancel(ctx context.Context, items []domain.Item) error
+	Reserve(ctx context.Context, orderID int64, items []domain.Item) ([]domain.Allocation, error)
+	ReserveRemove(ctx context.Context, orderID int64, allocations []domain.Allocation) error
+	ReserveCancel(ctx context.Context, orderID int64, allocations []domain.Allocation) error
+	ReturnToStock(ctx context.Context, orderID int64, allocations []domain.Allocation) error
+	Compensate(ctx context.Context, orderID int64, kind domain.StockMovementKind, allocations []domain.Allocation) error
 }
 
 type eventRepository interface {
 	CreateEvent(ctx context.Context, events domain.Event) error
 }
+
+type paymentRepository interface {
+	CreatePaymentIntent(ctx context.Context, intent domain.PaymentIntent) (domain.PaymentIntent, error)
+	GetPaymentIntent(ctx context.Context, orderID int64) (domain.PaymentIntent, error)
+	UpdatePaymentIntent(ctx context.Context, orderID int64, result domain.PaymentResult) error
+}
+
+type paymentGateway interface {
+	Name() string
+	Authorize(ctx context.Context, req domain.PaymentRequest) (domain.PaymentResult, error)
+	Capture(ctx context.Context, providerPaymentID string, amount int64, currency string) (domain.PaymentResult, error)
+	Refund(
+		ctx context.Context,
+		providerPaymentID string,
+		amount int64,
+		currency string,
+		idempotencyKey string) (domain.PaymentResult, error)
+}
+
+type statusBroadcaster interface {
+	Publish(update domain.OrderStatusUpdate)
+	Subscribe(orderID int64) *watch.Subscription
+}
+
 type Service struct {
-	orderTopic      string
-	orderRepository orderRepository
-	stockService    stockService
-	txManagerMaster txManager
-	eventRepository eventRepository
+	orderTopic        string
+	orderRepository   orderRepository
+	stockService      stockService
+	txManagerMaster   txManager
+	eventRepository   eventRepository
+	statusBroadcaster statusBroadcaster
+	paymentRepository paymentRepository
+	paymentGateway    paymentGateway
+	orderStates       *domain.OrderStateMachine
+	compensateStock   bool
 }
 
 func New(
@@ -45,12 +90,23 @@ func New(
 	eventRepository eventRepository,
 	stockService stockService,
 	txManagerMaster txManager,
+	statusBroadcaster statusBroadcaster,
+	paymentRepository paymentRepository,
+	paymentGateway paymentGateway,
+	compensateStock bool,
 ) *Service {
-	return &Service{
-		orderTopic:      orderTopic,
-		eventRepository: eventRepository,
-		orderRepository: orderRepository,
-		stockService:    stockService,
-		txManagerMaster: txManagerMaster,
+	s := &Service{
+		orderTopic:        orderTopic,
+		eventRepository:   eventRepository,
+		orderRepository:   orderRepository,
+		stockService:      stockService,
+		txManagerMaster:   txManagerMaster,
+		statusBroadcaster: statusBroadcaster,
+		paymentRepository: paymentRepository,
+		paymentGateway:    paymentGateway,
+		compensateStock:   compensateStock,
 	}
+	s.orderStates = domain.NewOrderStateMachine(s.changeStatus)
+
+	return s
 }
@@ -0,0 +1,94 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"route256/loms/internal/domain"
+	"route256/loms/internal/infra/logger"
+)
+
+// Without sharding stock and orders share the master database, so stock changes
+// join the order transaction. With sharding they commit on the master before the
+// order shard transaction does: such changes are recorded and compensated when
+// the order transaction attempt fails, both on rollback and before a retry.
+// A crash between the two commits still leaves the stock change in place, the
+// stock movement log keyed by order id is the source for reconciling it.
+
+type stockChangesKey struct{}
+
+type stockChange struct {
+	kind        domain.StockMovementKind
+	orderID     int64
+	allocations []domain.Allocation
+}
+
+type stockChanges struct {
+	changes []stockChange
+}
+
+func (s *Service) reserveStock(ctx context.Context, orderID int64, items []domain.Item) ([]domain.Allocation, error) {
+	allocations, err := s.stockService.Reserve(ctx, orderID, items)
+	if err != nil {
+		return nil, err
+	}
+
+	s.recordStockChange(ctx, domain.StockMovementReserve, orderID, allocations)
+
+	return allocations, nil
+}
+
+func (s *Service) changeStock(
+	ctx context.Context,
+	kind domain.StockMovementKind,
+	orderID int64,
+	allocations []domain.Allocation,
+	change func(ctx context.Context, orderID int64, allocations []domain.Allocation) error) error {
+	if err := change(ctx, orderID, allocations); err != nil {
+		return err
+	}
+
+	s.recordStockChange(ctx, kind, orderID, allocations)
+
+	return nil
+}
+
+func (s *Service) recordStockChange(
+	ctx context.Context,
+	kind domain.StockMovementKind,
+	orderID int64,
+	allocations []domain.Allocation) {
+	if !s.compensateStock || len(allocations) == 0 {
+		return
+	}
+
+	if changes, ok := ctx.Value(stockChangesKey{}).(*stockChanges); ok {
+		changes.changes = append(changes.changes, stockChange{kind: kind, orderID: orderID, allocations: allocations})
+	}
+}
+
+// compensateStockChanges reverts recorded changes in reverse order. Changes that
+// could not be reverted stay recorded so that a later call retries them.
+func (s *Service) compensateStockChanges(ctx context.Context, changes *stockChanges) error {
+	var errs []error
+
+	failed := make([]stockChange, 0)
+
+	for idx := len(changes.changes) - 1; idx >= 0; idx-- {
+		change := changes.changes[idx]
+
+		if err := s.stockService.Compensate(ctx, change.orderID, change.kind, change.allocations); err != nil {
+			errs = append(errs, fmt.Errorf("stockService.Compensate %s order %d: %w", change.kind, change.orderID, err))
+			failed = append([]stockChange{change}, failed...)
+		}
+	}
+
+	changes.changes = failed
+
+	if err := errors.Join(errs...); err != nil {
+		logger.Errorf(ctx, "compensate stock changes: %v", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+package order_test
+
+import (
+	"context"
+	"route256/loms/internal/domain"
+	txmanager "route256/loms/internal/infra/tx_manager"
+	testhelpers "route256/loms/internal/tool"
+	"testing"
+
+	"github.com/gojuno/minimock/v3"
+)
+
+func TestStockCompensation(t *testing.T) {
+	t.Parallel()
+
+	testOrderID := int64(12345)
+	testOrder := domain.Order{
+		UserID: 1,
+		Status: domain.OrderStatusAwaitingPayment,
+		Items:  []domain.Item{{Sku: 1001, Count: 2}},
+	}
+	testAllocations := []domain.Allocation{{Sku: 1001, WarehouseID: 1, Count: 2}}
+
+	testCases := []struct {
+		name            string
+		compensateStock bool
+		attempts        int
+		setStatusErrs   []error
+		compensateErr   error
+		expectedCalls   int
+		expectedErr     error
+	}{
+		{
+			name:            "success: committed change is not compensated",
+			compensateStock: true,
+			attempts:        1,
+			setStatusErrs:   []error{nil},
+		},
+		{
+			name:            "fail: rolled back change is compensated",
+			compensateStock: true,
+			attempts:        1,
+			setStatusErrs:   []error{testhelpers.ErrForTest},
+			expectedCalls:   1,
+			expectedErr:     testhelpers.ErrForTest,
+		},
+		{
+			name:            "success: change of failed attempt is compensated before retry",
+			compensateStock: true,
+			attempts:        2,
+			setStatusErrs:   []error{testhelpers.ErrForTest, nil},
+			expectedCalls:   1,
+		},
+		{
+			name:            "fail: compensation error is returned",
+			compensateStock: true,
+			attempts:        1,
+			setStatusErrs:   []error{testhelpers.ErrForTest},
+			compensateErr:   domain.ErrInvalidReserveOperation,
+			expectedCalls:   1,
+			expectedErr:     domain.ErrInvalidReserveOperation,
+		},
+		{
+			name:          "fail: change in shared transaction is not compensated",
+			attempts:      1,
+			setStatusErrs: []error{testhelpers.ErrForTest},
+			expectedErr:   testhelpers.ErrForTest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			f := setUpWithCompensation(t, tc.compensateStock)
+
+			ctx := context.Background()
+
+			f.txManager.ReadCommittedMock.Set(func(ctx context.Context, fn txmanager.Handler) error {
+				var err error
+				for range tc.attempts {
+					if err = fn(ctx); err == nil {
+						return nil
+					}
+				}
+
+				return err
+			})
+
+			f.orderRepository.GetByOrderIDForUpdateMock.
+				Expect(minimock.AnyContext, testOrderID).
+				Return(testOrder, nil)
+			f.orderRepository.GetAllocationsByOrderIDMock.
+				Expect(minimock.AnyContext, testOrderID).
+				Return(testAllocations, nil)
+			f.stockService.ReserveCancelMock.
+				Expect(minimock.AnyContext, testOrderID, testAllocations).
+				Return(nil)
+
+			setStatusCalls := 0
+			f.orderRepository.SetStatusAndCreateEventMock.Set(
+				func(_ context.Context, _ int64, _ domain.OrderStatus, _ domain.Event) error {
+					err := tc.setStatusErrs[setStatusCalls]
+					setStatusCalls++
+					return err
+				})
+
+			compensateCalls := 0
+			if tc.expectedCalls > 0 {
+				f.stockService.CompensateMock.Set(
+					func(_ context.Context, orderID int64, kind domain.StockMovementKind, allocations []domain.Allocation) error {
+						f.Equal(testOrderID, orderID)
+						f.Equal(domain.StockMovementReserveCancel, kind)
+						f.Equal(testAllocations, allocations)
+						compensateCalls++
+						return tc.compensateErr
+					})
+			}
+
+			err := f.executor.OrderCancel(ctx, testOrderID)
+
+			if tc.expectedErr != nil {
+				f.ErrorIs(err, tc.expectedErr)
+			} else {
+				f.NoError(err)
+			}
+
+			f.Equal(tc.expectedCalls, compensateCalls)
+		})
+	}
+}
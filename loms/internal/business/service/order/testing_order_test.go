@@ -1,13 +1,25 @@
 package order_test
 
 import (
+	"fmt"
 	orderservice "route256/loms/internal/business/service/order"
 	"route256/loms/internal/business/service/order/mock"
-
+	"route256/loms/internal/business/service/order/watch"
+	"route256/loms/internal/domain"
+	"route256/loms/internal/infra/logger"
 	"testing"
 
 	"github.com/gojuno/minimock/v3"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	testWatchBufferSize = 8
+
+	testPaymentProvider   = "test-provider"
+	testProviderPaymentID = "test-payment-id"
 )
 
 type fixture struct {
@@ -19,25 +31,69 @@ type fixture struct {
 
 	txManager *mock.TxManagerMock
 
+	statusBroadcaster *watch.Broadcaster
+
+	paymentRepository *mock.PaymentRepositoryMock
+	paymentGateway    *mock.PaymentGatewayMock
+
 	executor *orderservice.Service
 }
 
 func setUp(t *testing.T) *fixture {
+	return setUpWithCompensation(t, false)
+}
+
+func setUpWithCompensation(t *testing.T, compensateStock bool) *fixture {
 	ctrl := minimock.NewController(t)
 
+	err := logger.Init(zapcore.DebugLevel)
+	require.NoError(t, err)
+
 	orderRepository := mock.NewOrderRepositoryMock(ctrl)
 	eventRepository := mock.NewEventRepositoryMock(ctrl)
 	txManager := mock.NewTxManagerMock(ctrl)
 	stockService := mock.NewStockServiceMock(ctrl)
+	paymentRepository := mock.NewPaymentRepositoryMock(ctrl)
+	paymentGateway := mock.NewPaymentGatewayMock(ctrl)
 
-	executor := orderservice.New("test-topic", orderRepository, eventRepository, stockService, txManager)
+	statusBroadcaster := watch.New(testWatchBufferSize, watch.SlowConsumerDisconnect)
+
+	executor := orderservice.New("test-topic", orderRepository, eventRepository, stockService, txManager,
+		statusBroadcaster, paymentRepository, paymentGateway, compensateStock)
 
 	return &fixture{
-		Assertions:      assert.New(t),
-		orderRepository: orderRepository,
-		eventRepository: eventRepository,
-		stockService:    stockService,
-		txManager:       txManager,
-		executor:        executor,
+		Assertions:        assert.New(t),
+		orderRepository:   orderRepository,
+		eventRepository:   eventRepository,
+		stockService:      stockService,
+		txManager:         txManager,
+		statusBroadcaster: statusBroadcaster,
+		paymentRepository: paymentRepository,
+		paymentGateway:    paymentGateway,
+		executor:          executor,
 	}
 }
+
+func (f *fixture) expectNewPaymentIntent(orderID int64) {
+	f.paymentRepository.GetPaymentIntentMock.
+		Expect(minimock.AnyContext, orderID).
+		Return(domain.PaymentIntent{}, domain.ErrPaymentNotFound)
+	f.paymentGateway.NameMock.Return(testPaymentProvider)
+	f.paymentRepository.CreatePaymentIntentMock.
+		Expect(minimock.AnyContext, domain.PaymentIntent{OrderID: orderID, Provider: testPaymentProvider}).
+		Return(domain.PaymentIntent{
+			OrderID:  orderID,
+			Provider: testPaymentProvider,
+			Status:   domain.PaymentStatusPending,
+		}, nil)
+}
+
+func (f *fixture) expectAuthorize(orderID int64, status domain.PaymentStatus, err error) {
+	f.paymentGateway.AuthorizeMock.
+		Expect(minimock.AnyContext, domain.PaymentRequest{
+			OrderID:        orderID,
+			UserID:         1,
+			IdempotencyKey: fmt.Sprintf("order-%d-0", orderID),
+		}).
+		Return(domain.PaymentResult{ProviderPaymentID: testProviderPaymentID, Status: status}, err)
+}
@@ -0,0 +1,106 @@
+package watch
+
+import (
+	"errors"
+	"route256/loms/internal/domain"
+	"sync"
+)
+
+var (
+	ErrSlowConsumer = errors.New("subscriber is too slow")
+	ErrClosed       = errors.New("broadcaster is closed")
+)
+
+type SlowConsumerPolicy string
+
+const (
+	SlowConsumerDisconnect SlowConsumerPolicy = "disconnect"
+	SlowConsumerDropOldest SlowConsumerPolicy = "drop_oldest"
+)
+
+type Broadcaster struct {
+	bufferSize int
+	policy     SlowConsumerPolicy
+
+	mx          sync.Mutex
+	closed      bool
+	subscribers map[int64]map[*Subscription]struct{}
+}
+
+func New(bufferSize int, policy SlowConsumerPolicy) *Broadcaster {
+	return &Broadcaster{
+		bufferSize:  max(bufferSize, 1),
+		policy:      policy,
+		subscribers: make(map[int64]map[*Subscription]struct{}),
+	}
+}
+
+func (b *Broadcaster) Subscribe(orderID int64) *Subscription {
+	sub := &Subscription{
+		broadcaster: b,
+		orderID:     orderID,
+		updates:     make(chan domain.OrderStatusUpdate, b.bufferSize),
+		done:        make(chan struct{}),
+	}
+
+	b.mx.Lock()
+	defer b.mx.Unlock()
+
+	if b.closed {
+		sub.stop(ErrClosed)
+		return sub
+	}
+
+	if b.subscribers[orderID] == nil {
+		b.subscribers[orderID] = make(map[*Subscription]struct{})
+	}
+
+	b.subscribers[orderID][sub] = struct{}{}
+
+	return sub
+}
+
+func (b *Broadcaster) Publish(update domain.OrderStatusUpdate) {
+	b.mx.Lock()
+	defer b.mx.Unlock()
+
+	for sub := range b.subscribers[update.OrderID] {
+		if sub.offer(update, b.policy) {
+			continue
+		}
+
+		b.remove(sub)
+		sub.stop(ErrSlowConsumer)
+	}
+}
+
+func (b *Broadcaster) Close() {
+	b.mx.Lock()
+	defer b.mx.Unlock()
+
+	b.closed = true
+
+	for _, subs := range b.subscribers {
+		for sub := range subs {
+			sub.stop(ErrClosed)
+		}
+	}
+
+	clear(b.subscribers)
+}
+
+func (b *Broadcaster) unsubscribe(sub *Subscription) {
+	b.mx.Lock()
+	defer b.mx.Unlock()
+
+	b.remove(sub)
+}
+
+func (b *Broadcaster) remove(sub *Subscription) {
+	subs := b.subscribers[sub.orderID]
+	delete(subs, sub)
+
+	if len(subs) == 0 {
+		delete(b.subscribers, sub.orderID)
+	}
+}
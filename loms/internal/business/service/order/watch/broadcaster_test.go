@@ -0,0 +1,100 @@
+package watch_test
+
+import (
+	"route256/loms/internal/business/service/order/watch"
+	"route256/loms/internal/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testOrderID = int64(12345)
+
+func update(status domain.OrderStatus) domain.OrderStatusUpdate {
+	return domain.OrderStatusUpdate{OrderID: testOrderID, Status: status}
+}
+
+func TestBroadcasterPublish(t *testing.T) {
+	t.Parallel()
+
+	a := assert.New(t)
+	b := watch.New(2, watch.SlowConsumerDisconnect)
+
+	sub := b.Subscribe(testOrderID)
+	other := b.Subscribe(testOrderID + 1)
+	defer sub.Close()
+	defer other.Close()
+
+	b.Publish(update(domain.OrderStatusPayed))
+
+	a.Equal(update(domain.OrderStatusPayed), <-sub.Updates())
+	a.Empty(other.Updates())
+	a.NoError(sub.Err())
+}
+
+func TestBroadcasterSlowConsumer(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		policy         watch.SlowConsumerPolicy
+		expectedErr    error
+		expectedStatus []domain.OrderStatus
+	}{
+		{
+			name:           "disconnect: slow subscriber is dropped",
+			policy:         watch.SlowConsumerDisconnect,
+			expectedErr:    watch.ErrSlowConsumer,
+			expectedStatus: []domain.OrderStatus{domain.OrderStatusNew},
+		},
+		{
+			name:           "drop_oldest: slow subscriber keeps the latest update",
+			policy:         watch.SlowConsumerDropOldest,
+			expectedStatus: []domain.OrderStatus{domain.OrderStatusPayed},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			a := assert.New(t)
+			b := watch.New(1, tc.policy)
+
+			sub := b.Subscribe(testOrderID)
+			defer sub.Close()
+
+			b.Publish(update(domain.OrderStatusNew))
+			b.Publish(update(domain.OrderStatusPayed))
+
+			if tc.expectedErr != nil {
+				<-sub.Done()
+			}
+
+			a.ErrorIs(sub.Err(), tc.expectedErr)
+
+			for _, status := range tc.expectedStatus {
+				a.Equal(status, (<-sub.Updates()).Status)
+			}
+
+			a.Empty(sub.Updates())
+		})
+	}
+}
+
+func TestBroadcasterClose(t *testing.T) {
+	t.Parallel()
+
+	a := assert.New(t)
+	b := watch.New(1, watch.SlowConsumerDisconnect)
+
+	sub := b.Subscribe(testOrderID)
+	b.Close()
+
+	<-sub.Done()
+	a.ErrorIs(sub.Err(), watch.ErrClosed)
+
+	late := b.Subscribe(testOrderID)
+	<-late.Done()
+	a.ErrorIs(late.Err(), watch.ErrClosed)
+}
@@ -0,0 +1,69 @@
+package watch
+
+import (
+	"route256/loms/internal/domain"
+	"sync"
+)
+
+type Subscription struct {
+	broadcaster *Broadcaster
+	orderID     int64
+	updates     chan domain.OrderStatusUpdate
+
+	once sync.Once
+	done chan struct{}
+	err  error
+}
+
+func (s *Subscription) Updates() <-chan domain.OrderStatusUpdate {
+	return s.updates
+}
+
+func (s *Subscription) Done() <-chan struct{} {
+	return s.done
+}
+
+func (s *Subscription) Err() error {
+	select {
+	case <-s.done:
+		return s.err
+	default:
+		return nil
+	}
+}
+
+func (s *Subscription) Close() {
+	s.broadcaster.unsubscribe(s)
+	s.stop(nil)
+}
+
+func (s *Subscription) offer(update domain.OrderStatusUpdate, policy SlowConsumerPolicy) bool {
+	select {
+	case s.updates <- update:
+		return true
+	default:
+	}
+
+	if policy != SlowConsumerDropOldest {
+		return false
+	}
+
+	select {
+	case <-s.updates:
+	default:
+	}
+
+	select {
+	case s.updates <- update:
+	default:
+	}
+
+	return true
+}
+
+func (s *Subscription) stop(err error) {
+	s.once.Do(func() {
+		s.err = err
+		close(s.done)
+	})
+}
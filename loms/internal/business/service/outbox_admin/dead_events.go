@@ -0,0 +1,49 @@
+package outboxadmin
+
+import (
+	"context"
+	"fmt"
+	"route256/loms/internal/domain"
+	"route256/loms/internal/infra/sharding"
+	"sort"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+const defaultDeadEventsLimit = 20
+
+func (s *Service) DeadEvents(ctx context.Context, filter domain.DeadEventsFilter) ([]domain.Event, int64, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "outboxAdminService.DeadEvents")
+	defer span.Finish()
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultDeadEventsLimit
+	}
+
+	filter.Limit = limit + 1
+
+	var events []domain.Event
+	for shard := range s.shardCount {
+		shardEvents, err := s.eventRepository.ListDeadEvents(sharding.WithShard(ctx, shard), filter)
+		if err != nil {
+			return nil, 0, fmt.Errorf("eventRepository.ListDeadEvents: shard %d: %w", shard, err)
+		}
+
+		events = append(events, shardEvents...)
+	}
+
+	if s.shardCount > 1 {
+		sort.Slice(events, func(i, j int) bool {
+			return events[i].ID > events[j].ID
+		})
+	}
+
+	var nextCursor int64
+	if len(events) > int(limit) {
+		events = events[:limit]
+		nextCursor = events[limit-1].ID
+	}
+
+	return events, nextCursor, nil
+}
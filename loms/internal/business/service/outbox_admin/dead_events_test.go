@@ -0,0 +1,120 @@
+package outboxadmin_test
+
+import (
+	"context"
+	"fmt"
+	"route256/loms/internal/domain"
+	"route256/loms/internal/infra/sharding"
+	testhelpers "route256/loms/internal/tool"
+	"testing"
+
+	"github.com/gojuno/minimock/v3"
+)
+
+func TestDeadEvents(t *testing.T) {
+	t.Parallel()
+
+	testEvents := []domain.Event{
+		{ID: 9, Topic: "loms.order-events", Key: "3", Status: domain.EventStatusDead, Attempts: 10},
+		{ID: 7, Topic: "loms.order-events", Key: "2", Status: domain.EventStatusDead, Attempts: 10},
+		{ID: 4, Topic: "loms.order-events", Key: "1", Status: domain.EventStatusDead, Attempts: 10},
+	}
+
+	testCases := []struct {
+		name               string
+		filter             domain.DeadEventsFilter
+		expectedRepoFilter domain.DeadEventsFilter
+		repoResult         []domain.Event
+		repoErr            error
+		expected           []domain.Event
+		expectedCursor     int64
+		expectedErr        error
+	}{
+		{
+			name:               "success: last page",
+			filter:             domain.DeadEventsFilter{Limit: 5},
+			expectedRepoFilter: domain.DeadEventsFilter{Limit: 6},
+			repoResult:         testEvents,
+			expected:           testEvents,
+			expectedCursor:     0,
+		},
+		{
+			name:               "success: has next page",
+			filter:             domain.DeadEventsFilter{Cursor: 10, Limit: 2},
+			expectedRepoFilter: domain.DeadEventsFilter{Cursor: 10, Limit: 3},
+			repoResult:         testEvents,
+			expected:           testEvents[:2],
+			expectedCursor:     7,
+		},
+		{
+			name:               "success: default limit",
+			filter:             domain.DeadEventsFilter{},
+			expectedRepoFilter: domain.DeadEventsFilter{Limit: 21},
+			repoResult:         nil,
+			expected:           nil,
+			expectedCursor:     0,
+		},
+		{
+			name:               "fail: ListDeadEvents returns error",
+			filter:             domain.DeadEventsFilter{Limit: 5},
+			expectedRepoFilter: domain.DeadEventsFilter{Limit: 6},
+			repoErr:            testhelpers.ErrForTest,
+			expectedErr:        testhelpers.ErrForTest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			f := setUp(t)
+
+			f.repository.ListDeadEventsMock.
+				Expect(minimock.AnyContext, tc.expectedRepoFilter).
+				Return(tc.repoResult, tc.repoErr)
+
+			events, nextCursor, err := f.executor.DeadEvents(ctx, tc.filter)
+
+			if tc.expectedErr != nil {
+				f.Error(err)
+				f.ErrorIs(err, tc.expectedErr)
+			} else {
+				f.NoError(err)
+				f.Equal(tc.expected, events)
+				f.Equal(tc.expectedCursor, nextCursor)
+			}
+		})
+	}
+}
+
+func TestDeadEventsSharded(t *testing.T) {
+	t.Parallel()
+
+	shardEvents := map[int][]domain.Event{
+		0: {
+			{ID: 9, Topic: "loms.order-events", Key: "3", Status: domain.EventStatusDead},
+			{ID: 4, Topic: "loms.order-events", Key: "1", Status: domain.EventStatusDead},
+		},
+		1: {
+			{ID: sharding.ID(1, 7), Topic: "loms.order-events", Key: "2", Status: domain.EventStatusDead},
+		},
+	}
+
+	ctx := context.Background()
+	f := setUpWithShards(t, 2)
+
+	f.repository.ListDeadEventsMock.Set(func(ctx context.Context, filter domain.DeadEventsFilter) ([]domain.Event, error) {
+		if filter.Limit != 3 {
+			return nil, fmt.Errorf("unexpected limit: got %d", filter.Limit)
+		}
+
+		return shardEvents[sharding.ShardFromContext(ctx, 2)], nil
+	})
+
+	events, nextCursor, err := f.executor.DeadEvents(ctx, domain.DeadEventsFilter{Limit: 2})
+
+	f.NoError(err)
+	f.Equal([]domain.Event{shardEvents[1][0], shardEvents[0][0]}, events)
+	f.Equal(int64(9), nextCursor)
+}
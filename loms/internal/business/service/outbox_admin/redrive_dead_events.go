@@ -0,0 +1,47 @@
+package outboxadmin
+
+import (
+	"context"
+	"fmt"
+	"route256/loms/internal/infra/sharding"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+func (s *Service) RedriveDeadEvents(ctx context.Context, ids []int64) ([]int64, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "outboxAdminService.RedriveDeadEvents")
+	defer span.Finish()
+
+	var redrivenIDs []int64
+
+	for shard, shardIDs := range s.groupByShard(ids) {
+		if err := s.txManagerMaster.ReadCommitted(sharding.WithShard(ctx, shard), func(txCtx context.Context) error {
+			redriven, err := s.eventRepository.RedriveDeadEvents(txCtx, shardIDs)
+			if err != nil {
+				return fmt.Errorf("eventRepository.RedriveDeadEvents: shard %d: %w", shard, err)
+			}
+
+			redrivenIDs = append(redrivenIDs, redriven...)
+
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	return redrivenIDs, nil
+}
+
+func (s *Service) groupByShard(ids []int64) map[int][]int64 {
+	if s.shardCount == 1 {
+		return map[int][]int64{0: ids}
+	}
+
+	groups := make(map[int][]int64)
+	for _, id := range ids {
+		shard := sharding.ShardByID(id)
+		groups[shard] = append(groups[shard], id)
+	}
+
+	return groups
+}
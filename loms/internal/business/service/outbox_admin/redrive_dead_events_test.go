@@ -0,0 +1,92 @@
+package outboxadmin_test
+
+import (
+	"context"
+	"fmt"
+	"route256/loms/internal/infra/sharding"
+	testhelpers "route256/loms/internal/tool"
+	"slices"
+	"testing"
+
+	"github.com/gojuno/minimock/v3"
+)
+
+func TestRedriveDeadEvents(t *testing.T) {
+	t.Parallel()
+
+	testIDs := []int64{4, 7, 9}
+
+	testCases := []struct {
+		name        string
+		repoResult  []int64
+		repoErr     error
+		expected    []int64
+		expectedErr error
+	}{
+		{
+			name:       "success: all events redriven",
+			repoResult: testIDs,
+			expected:   testIDs,
+		},
+		{
+			name:       "success: only dead events redriven",
+			repoResult: testIDs[:1],
+			expected:   testIDs[:1],
+		},
+		{
+			name:        "fail: RedriveDeadEvents returns error",
+			repoErr:     testhelpers.ErrForTest,
+			expectedErr: testhelpers.ErrForTest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			f := setUp(t)
+
+			f.repository.RedriveDeadEventsMock.
+				Expect(minimock.AnyContext, testIDs).
+				Return(tc.repoResult, tc.repoErr)
+
+			redrivenIDs, err := f.executor.RedriveDeadEvents(ctx, testIDs)
+
+			if tc.expectedErr != nil {
+				f.Error(err)
+				f.ErrorIs(err, tc.expectedErr)
+				f.Nil(redrivenIDs)
+			} else {
+				f.NoError(err)
+				f.Equal(tc.expected, redrivenIDs)
+			}
+		})
+	}
+}
+
+func TestRedriveDeadEventsSharded(t *testing.T) {
+	t.Parallel()
+
+	shardIDs := map[int][]int64{
+		0: {4, 9},
+		1: {sharding.ID(1, 7)},
+	}
+
+	ctx := context.Background()
+	f := setUpWithShards(t, 2)
+
+	f.repository.RedriveDeadEventsMock.Set(func(ctx context.Context, ids []int64) ([]int64, error) {
+		shard := sharding.ShardFromContext(ctx, 2)
+		if !slices.Equal(shardIDs[shard], ids) {
+			return nil, fmt.Errorf("unexpected ids for shard %d: got %v", shard, ids)
+		}
+
+		return ids, nil
+	})
+
+	redrivenIDs, err := f.executor.RedriveDeadEvents(ctx, []int64{4, sharding.ID(1, 7), 9})
+
+	f.NoError(err)
+	f.ElementsMatch([]int64{4, 9, sharding.ID(1, 7)}, redrivenIDs)
+}
@@ -0,0 +1,33 @@
+package outboxadmin
+
+import (
+	"context"
+	"route256/loms/internal/domain"
+	txmanager "route256/loms/internal/infra/tx_manager"
+)
+
+//go:generate rm -rf mock
+//go:generate mkdir -p mock
+//go:generate minimock -i * -o ./mock -s "_mock.go" -g
+type eventRepository interface {
+	ListDeadEvents(ctx context.Context, filter domain.DeadEventsFilter) ([]domain.Event, error)
+	RedriveDeadEvents(ctx context.Context, ids []int64) ([]int64, error)
+}
+
+type txManager interface {
+	ReadCommitted(ctx context.Context, f txmanager.Handler) error
+}
+
+type Service struct {
+	eventRepository eventRepository
+	txManagerMaster txManager
+	shardCount      int
+}
+
+func New(eventRepository eventRepository, txManagerMaster txManager, shardCount int) *Service {
+	return &Service{
+		eventRepository: eventRepository,
+		txManagerMaster: txManagerMaster,
+		shardCount:      max(shardCount, 1),
+	}
+}
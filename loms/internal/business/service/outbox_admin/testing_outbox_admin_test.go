@@ -0,0 +1,53 @@
+package outboxadmin_test
+
+import (
+	"context"
+	outboxadminservice "route256/loms/internal/business/service/outbox_admin"
+	"route256/loms/internal/business/service/outbox_admin/mock"
+	logger "route256/loms/internal/infra/logger"
+	txmanager "route256/loms/internal/infra/tx_manager"
+	"testing"
+
+	"github.com/gojuno/minimock/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+type fixture struct {
+	*assert.Assertions
+
+	repository *mock.EventRepositoryMock
+	txManager  *mock.TxManagerMock
+
+	executor *outboxadminservice.Service
+}
+
+func setUp(t *testing.T) *fixture {
+	return setUpWithShards(t, 1)
+}
+
+func setUpWithShards(t *testing.T, shardCount int) *fixture {
+	ctrl := minimock.NewController(t)
+
+	err := logger.Init(zapcore.DebugLevel)
+	require.NoError(t, err)
+
+	repository := mock.NewEventRepositoryMock(ctrl)
+	txManager := mock.NewTxManagerMock(ctrl)
+
+	txManager.ReadCommittedMock.Optional().Set(func(ctx context.Context, fn txmanager.Handler) error {
+		return fn(ctx)
+	})
+
+	executor := outboxadminservice.New(repository, txManager, shardCount)
+
+	return &fixture{
+		Assertions: assert.New(t),
+
+		repository: repository,
+		txManager:  txManager,
+
+		executor: executor,
+	}
+}
@@ -0,0 +1,97 @@
+package allocation
+
+import (
+	"fmt"
+	"route256/loms/internal/domain"
+	"sort"
+)
+
+type FewestShipmentsStrategy struct{}
+
+func NewFewestShipmentsStrategy() *FewestShipmentsStrategy {
+	return &FewestShipmentsStrategy{}
+}
+
+func (s *FewestShipmentsStrategy) Allocate(
+	items []domain.Item,
+	stocks map[domain.Sku][]domain.WarehouseStock) ([]domain.Allocation, error) {
+	need := make(map[domain.Sku]int64, len(items))
+	free := make(map[int64]map[domain.Sku]int64)
+
+	for _, item := range items {
+		warehouses, ok := stocks[item.Sku]
+		if !ok {
+			return nil, fmt.Errorf("%w: sku %v", domain.ErrStockNotFound, item.Sku)
+		}
+
+		need[item.Sku] += item.Count
+
+		for _, stock := range warehouses {
+			if free[stock.WarehouseID] == nil {
+				free[stock.WarehouseID] = make(map[domain.Sku]int64)
+			}
+			free[stock.WarehouseID][item.Sku] = available(stock)
+		}
+	}
+
+	warehouseIDs := make([]int64, 0, len(free))
+	for warehouseID := range free {
+		warehouseIDs = append(warehouseIDs, warehouseID)
+	}
+	sort.Slice(warehouseIDs, func(i, j int) bool { return warehouseIDs[i] < warehouseIDs[j] })
+
+	allocations := make([]domain.Allocation, 0, len(items))
+
+	for len(need) > 0 {
+		best, bestCovered, bestUnits := int64(0), -1, int64(0)
+
+		for _, warehouseID := range warehouseIDs {
+			covered, units := 0, int64(0)
+			for sku, count := range need {
+				stock := free[warehouseID][sku]
+				if stock >= count {
+					covered++
+				}
+				units += min(max(stock, 0), count)
+			}
+
+			if covered > bestCovered || (covered == bestCovered && units > bestUnits) {
+				best, bestCovered, bestUnits = warehouseID, covered, units
+			}
+		}
+
+		if bestUnits == 0 {
+			return nil, domain.ErrNotEnoughStock
+		}
+
+		for sku, count := range need {
+			take := min(max(free[best][sku], 0), count)
+			if take == 0 {
+				continue
+			}
+
+			allocations = append(allocations, domain.Allocation{
+				Sku:         sku,
+				WarehouseID: best,
+				Count:       take,
+			})
+
+			free[best][sku] -= take
+			need[sku] -= take
+
+			if need[sku] == 0 {
+				delete(need, sku)
+			}
+		}
+	}
+
+	sort.Slice(allocations, func(i, j int) bool {
+		if allocations[i].Sku != allocations[j].Sku {
+			return allocations[i].Sku < allocations[j].Sku
+		}
+
+		return allocations[i].WarehouseID < allocations[j].WarehouseID
+	})
+
+	return allocations, nil
+}
@@ -0,0 +1,85 @@
+package allocation_test
+
+import (
+	"route256/loms/internal/business/service/stock/allocation"
+	"route256/loms/internal/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFewestShipmentsStrategy(t *testing.T) {
+	t.Parallel()
+
+	stocks := map[domain.Sku][]domain.WarehouseStock{
+		1001: {
+			{WarehouseID: 1, Sku: 1001, TotalCount: 10, Reserved: 0},
+			{WarehouseID: 2, Sku: 1001, TotalCount: 10, Reserved: 0},
+		},
+		1002: {
+			{WarehouseID: 2, Sku: 1002, TotalCount: 3, Reserved: 0},
+			{WarehouseID: 3, Sku: 1002, TotalCount: 10, Reserved: 0},
+		},
+		1003: {
+			{WarehouseID: 3, Sku: 1003, TotalCount: 1, Reserved: 0},
+		},
+	}
+
+	testCases := []struct {
+		name        string
+		items       []domain.Item
+		expected    []domain.Allocation
+		expectedErr error
+	}{
+		{
+			name:  "success: one warehouse covers the whole order",
+			items: []domain.Item{{Sku: 1001, Count: 2}, {Sku: 1002, Count: 3}},
+			expected: []domain.Allocation{
+				{Sku: 1001, WarehouseID: 2, Count: 2},
+				{Sku: 1002, WarehouseID: 2, Count: 3},
+			},
+		},
+		{
+			name:  "success: minimal number of warehouses",
+			items: []domain.Item{{Sku: 1001, Count: 2}, {Sku: 1002, Count: 5}, {Sku: 1003, Count: 1}},
+			expected: []domain.Allocation{
+				{Sku: 1001, WarehouseID: 1, Count: 2},
+				{Sku: 1002, WarehouseID: 3, Count: 5},
+				{Sku: 1003, WarehouseID: 3, Count: 1},
+			},
+		},
+		{
+			name:  "success: item split when no warehouse has enough",
+			items: []domain.Item{{Sku: 1001, Count: 15}},
+			expected: []domain.Allocation{
+				{Sku: 1001, WarehouseID: 1, Count: 10},
+				{Sku: 1001, WarehouseID: 2, Count: 5},
+			},
+		},
+		{
+			name:        "fail: not enough stock",
+			items:       []domain.Item{{Sku: 1003, Count: 2}},
+			expectedErr: domain.ErrNotEnoughStock,
+		},
+		{
+			name:        "fail: sku not found",
+			items:       []domain.Item{{Sku: 2002, Count: 1}},
+			expectedErr: domain.ErrStockNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			allocations, err := allocation.NewFewestShipmentsStrategy().Allocate(tc.items, stocks)
+
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, allocations)
+			}
+		})
+	}
+}
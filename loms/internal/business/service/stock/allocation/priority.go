@@ -0,0 +1,85 @@
+package allocation
+
+import (
+	"fmt"
+	"route256/loms/internal/domain"
+	"sort"
+)
+
+type PriorityStrategy struct {
+	ranks map[int64]int
+}
+
+func NewPriorityStrategy(warehouseIDs []int64) *PriorityStrategy {
+	ranks := make(map[int64]int, len(warehouseIDs))
+	for idx, warehouseID := range warehouseIDs {
+		if _, ok := ranks[warehouseID]; !ok {
+			ranks[warehouseID] = idx
+		}
+	}
+
+	return &PriorityStrategy{
+		ranks: ranks,
+	}
+}
+
+func (s *PriorityStrategy) Allocate(
+	items []domain.Item,
+	stocks map[domain.Sku][]domain.WarehouseStock) ([]domain.Allocation, error) {
+	items = domain.MergeItems(items)
+	allocations := make([]domain.Allocation, 0, len(items))
+
+	for _, item := range items {
+		warehouses, ok := stocks[item.Sku]
+		if !ok {
+			return nil, fmt.Errorf("%w: sku %v", domain.ErrStockNotFound, item.Sku)
+		}
+
+		warehouses = append([]domain.WarehouseStock(nil), warehouses...)
+		sort.SliceStable(warehouses, func(i, j int) bool {
+			ri, rj := s.rank(warehouses[i].WarehouseID), s.rank(warehouses[j].WarehouseID)
+			if ri != rj {
+				return ri < rj
+			}
+
+			return warehouses[i].WarehouseID < warehouses[j].WarehouseID
+		})
+
+		need := item.Count
+		for idx := range warehouses {
+			if need == 0 {
+				break
+			}
+
+			stock := &warehouses[idx]
+
+			take := min(available(*stock), need)
+			if take <= 0 {
+				continue
+			}
+
+			stock.Reserved += take
+
+			allocations = append(allocations, domain.Allocation{
+				Sku:         item.Sku,
+				WarehouseID: stock.WarehouseID,
+				Count:       take,
+			})
+			need -= take
+		}
+
+		if need > 0 {
+			return nil, domain.ErrNotEnoughStock
+		}
+	}
+
+	return allocations, nil
+}
+
+func (s *PriorityStrategy) rank(warehouseID int64) int {
+	if rank, ok := s.ranks[warehouseID]; ok {
+		return rank
+	}
+
+	return len(s.ranks)
+}
@@ -0,0 +1,96 @@
+package allocation_test
+
+import (
+	"route256/loms/internal/business/service/stock/allocation"
+	"route256/loms/internal/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriorityStrategy(t *testing.T) {
+	t.Parallel()
+
+	stocks := map[domain.Sku][]domain.WarehouseStock{
+		1001: {
+			{WarehouseID: 1, Sku: 1001, TotalCount: 10, Reserved: 8},
+			{WarehouseID: 2, Sku: 1001, TotalCount: 10, Reserved: 0},
+			{WarehouseID: 3, Sku: 1001, TotalCount: 5, Reserved: 0},
+		},
+	}
+
+	testCases := []struct {
+		name        string
+		priority    []int64
+		items       []domain.Item
+		expected    []domain.Allocation
+		expectedErr error
+	}{
+		{
+			name:     "success: single warehouse by priority",
+			priority: []int64{3, 1},
+			items:    []domain.Item{{Sku: 1001, Count: 4}},
+			expected: []domain.Allocation{
+				{Sku: 1001, WarehouseID: 3, Count: 4},
+			},
+		},
+		{
+			name:     "success: split across warehouses by priority",
+			priority: []int64{1, 3},
+			items:    []domain.Item{{Sku: 1001, Count: 9}},
+			expected: []domain.Allocation{
+				{Sku: 1001, WarehouseID: 1, Count: 2},
+				{Sku: 1001, WarehouseID: 3, Count: 5},
+				{Sku: 1001, WarehouseID: 2, Count: 2},
+			},
+		},
+		{
+			name:     "success: no priority falls back to warehouse id",
+			priority: nil,
+			items:    []domain.Item{{Sku: 1001, Count: 3}},
+			expected: []domain.Allocation{
+				{Sku: 1001, WarehouseID: 1, Count: 2},
+				{Sku: 1001, WarehouseID: 2, Count: 1},
+			},
+		},
+		{
+			name:     "success: duplicate sku is allocated once",
+			priority: []int64{1},
+			items:    []domain.Item{{Sku: 1001, Count: 2}, {Sku: 1001, Count: 2}},
+			expected: []domain.Allocation{
+				{Sku: 1001, WarehouseID: 1, Count: 2},
+				{Sku: 1001, WarehouseID: 2, Count: 2},
+			},
+		},
+		{
+			name:        "fail: duplicate sku exceeds stock",
+			items:       []domain.Item{{Sku: 1001, Count: 9}, {Sku: 1001, Count: 9}},
+			expectedErr: domain.ErrNotEnoughStock,
+		},
+		{
+			name:        "fail: not enough stock",
+			items:       []domain.Item{{Sku: 1001, Count: 18}},
+			expectedErr: domain.ErrNotEnoughStock,
+		},
+		{
+			name:        "fail: sku not found",
+			items:       []domain.Item{{Sku: 2002, Count: 1}},
+			expectedErr: domain.ErrStockNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			allocations, err := allocation.NewPriorityStrategy(tc.priority).Allocate(tc.items, stocks)
+
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, allocations)
+			}
+		})
+	}
+}
@@ -0,0 +1,11 @@
+package allocation
+
+import "route256/loms/internal/domain"
+
+type Strategy interface {
+	Allocate(items []domain.Item, stocks map[domain.Sku][]domain.WarehouseStock) ([]domain.Allocation, error)
+}
+
+func available(stock domain.WarehouseStock) int64 {
+	return stock.TotalCount - stock.Reserved
+}
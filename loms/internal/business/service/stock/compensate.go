@@ -0,0 +1,66 @@
+package stock
+
+import (
+	"context"
+	"fmt"
+	"route256/loms/internal/domain"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+// Compensate reverts a stock change of the given kind made for the order, it is
+// used when the order transaction the change belonged to has not committed.
+func (s *Service) Compensate(
+	ctx context.Context,
+	orderID int64,
+	kind domain.StockMovementKind,
+	allocations []domain.Allocation) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "stockService.Compensate")
+	defer span.Finish()
+
+	var revert func(stock *domain.WarehouseStock, count int64) error
+
+	switch kind {
+	case domain.StockMovementReserve:
+		revert = func(stock *domain.WarehouseStock, count int64) error {
+			if stock.Reserved < count {
+				return domain.ErrInvalidReserveOperation
+			}
+
+			stock.Reserved -= count
+
+			return nil
+		}
+	case domain.StockMovementReserveCancel:
+		revert = func(stock *domain.WarehouseStock, count int64) error {
+			if stock.TotalCount < stock.Reserved+count {
+				return domain.ErrInvalidReserveOperation
+			}
+
+			stock.Reserved += count
+
+			return nil
+		}
+	case domain.StockMovementReserveRemove:
+		revert = func(stock *domain.WarehouseStock, count int64) error {
+			stock.TotalCount += count
+			stock.Reserved += count
+
+			return nil
+		}
+	case domain.StockMovementReturn:
+		revert = func(stock *domain.WarehouseStock, count int64) error {
+			if stock.TotalCount-count < stock.Reserved {
+				return domain.ErrInvalidReserveOperation
+			}
+
+			stock.TotalCount -= count
+
+			return nil
+		}
+	default:
+		return fmt.Errorf("%w: cannot compensate %q", domain.ErrInvalidReserveOperation, kind)
+	}
+
+	return s.applyAllocations(ctx, domain.StockMovementCompensate, orderID, allocations, revert)
+}
@@ -0,0 +1,102 @@
+package stock_test
+
+import (
+	"context"
+	"route256/loms/internal/domain"
+	"testing"
+
+	"github.com/gojuno/minimock/v3"
+)
+
+func TestCompensate(t *testing.T) {
+	t.Parallel()
+
+	testOrderID := int64(12345)
+	testAllocation := domain.Allocation{Sku: 1001, WarehouseID: 1, Count: 2}
+	testStock := domain.WarehouseStock{WarehouseID: 1, Sku: 1001, TotalCount: 10, Reserved: 5}
+
+	testCases := []struct {
+		name        string
+		kind        domain.StockMovementKind
+		stock       domain.WarehouseStock
+		expected    domain.WarehouseStock
+		expectedErr error
+	}{
+		{
+			name:     "success: reserve is released",
+			kind:     domain.StockMovementReserve,
+			stock:    testStock,
+			expected: domain.WarehouseStock{WarehouseID: 1, Sku: 1001, TotalCount: 10, Reserved: 3},
+		},
+		{
+			name:     "success: reserve cancel is reserved again",
+			kind:     domain.StockMovementReserveCancel,
+			stock:    testStock,
+			expected: domain.WarehouseStock{WarehouseID: 1, Sku: 1001, TotalCount: 10, Reserved: 7},
+		},
+		{
+			name:     "success: reserve remove is put back",
+			kind:     domain.StockMovementReserveRemove,
+			stock:    testStock,
+			expected: domain.WarehouseStock{WarehouseID: 1, Sku: 1001, TotalCount: 12, Reserved: 7},
+		},
+		{
+			name:     "success: return is taken back",
+			kind:     domain.StockMovementReturn,
+			stock:    testStock,
+			expected: domain.WarehouseStock{WarehouseID: 1, Sku: 1001, TotalCount: 8, Reserved: 5},
+		},
+		{
+			name:        "fail: return is already reserved by others",
+			kind:        domain.StockMovementReturn,
+			stock:       domain.WarehouseStock{WarehouseID: 1, Sku: 1001, TotalCount: 6, Reserved: 5},
+			expectedErr: domain.ErrInvalidReserveOperation,
+		},
+		{
+			name:        "fail: kind cannot be compensated",
+			kind:        domain.StockMovementRestock,
+			expectedErr: domain.ErrInvalidReserveOperation,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			f := setUp(t)
+
+			if tc.stock.Sku != 0 {
+				f.repository.GetStocksBySkuForUpdateMock.
+					Expect(minimock.AnyContext, []domain.Item{{Sku: testAllocation.Sku, Count: testAllocation.Count}}).
+					Return(map[domain.Sku][]domain.WarehouseStock{tc.stock.Sku: {tc.stock}}, nil)
+			}
+
+			if tc.expectedErr == nil {
+				f.repository.UpdateStocksMock.
+					Expect(minimock.AnyContext, []domain.WarehouseStock{tc.expected}).
+					Return(nil)
+				f.repository.CreateStockMovementsMock.
+					Expect(minimock.AnyContext, []domain.StockMovement{{
+						WarehouseID:   1,
+						Sku:           1001,
+						Kind:          domain.StockMovementCompensate,
+						OrderID:       testOrderID,
+						TotalDelta:    tc.expected.TotalCount - tc.stock.TotalCount,
+						ReservedDelta: tc.expected.Reserved - tc.stock.Reserved,
+						TotalCount:    tc.expected.TotalCount,
+						Reserved:      tc.expected.Reserved,
+					}}).
+					Return(nil)
+			}
+
+			err := f.executor.Compensate(ctx, testOrderID, tc.kind, []domain.Allocation{testAllocation})
+
+			if tc.expectedErr != nil {
+				f.ErrorIs(err, tc.expectedErr)
+			} else {
+				f.NoError(err)
+			}
+		})
+	}
+}
@@ -8,33 +8,77 @@ import (
 	"github.com/opentracing/opentracing-go"
 )
 
-func (s *Service) Reserve(ctx context.Context, items []domain.Item) error {
+func (s *Service) Reserve(ctx context.Context, orderID int64, items []domain.Item) ([]domain.Allocation, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "stockService.Reserve")
 	defer span.Finish()
 
+	var allocations []domain.Allocation
+
+	err := s.txManager.ReadCommitted(ctx, func(ctx context.Context) error {
+		var err error
+		allocations, err = s.reserve(ctx, orderID, items)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return allocations, nil
+}
+
+func (s *Service) reserve(ctx context.Context, orderID int64, items []domain.Item) ([]domain.Allocation, error) {
 	stocks, err := s.stockRepository.GetStocksBySkuForUpdate(ctx, items)
 	if err != nil {
-		return fmt.Errorf("stockRepository.GetStockBySkuForUpdate: %w", err)
+		return nil, fmt.Errorf("stockRepository.GetStockBySkuForUpdate: %w", err)
 	}
 
 	for _, item := range items {
-		stock, ok := stocks[item.Sku]
+		if _, ok := stocks[item.Sku]; !ok {
+			return nil, fmt.Errorf("%w: sku %v", domain.ErrStockNotFound, item.Sku)
+		}
+	}
+
+	allocations, err := s.allocationStrategy.Allocate(items, stocks)
+	if err != nil {
+		return nil, fmt.Errorf("allocationStrategy.Allocate: %w", err)
+	}
+
+	index := make(map[warehouseSku]int)
+	updated := make([]domain.WarehouseStock, 0, len(allocations))
+	movements := make([]domain.StockMovement, 0, len(allocations))
+
+	for _, allocation := range allocations {
+		key := warehouseSku{warehouseID: allocation.WarehouseID, sku: allocation.Sku}
+
+		idx, ok := index[key]
 		if !ok {
-			return fmt.Errorf("%w: sku %v", domain.ErrStockNotFound, item.Sku)
+			stock, found := findWarehouseStock(stocks[allocation.Sku], allocation.WarehouseID)
+			if !found {
+				return nil, fmt.Errorf("%w: sku %v warehouse %d", domain.ErrStockNotFound, allocation.Sku, allocation.WarehouseID)
+			}
+
+			idx = len(updated)
+			index[key] = idx
+			updated = append(updated, stock)
 		}
 
-		if stock.TotalCount < (stock.Reserved + item.Count) {
-			return domain.ErrNotEnoughStock
+		if updated[idx].TotalCount < (updated[idx].Reserved + allocation.Count) {
+			return nil, domain.ErrNotEnoughStock
 		}
 
-		stock.Reserved += item.Count
+		before := updated[idx]
+		updated[idx].Reserved += allocation.Count
+
+		movements = append(movements, newStockMovement(domain.StockMovementReserve, orderID, before, updated[idx]))
+	}
 
-		stocks[item.Sku] = stock
+	if err := s.stockRepository.UpdateStocks(ctx, updated); err != nil {
+		return nil, fmt.Errorf("stockRepository.UpdateStockCount: %w", err)
 	}
 
-	if err := s.stockRepository.UpdateStocks(ctx, stocks); err != nil {
-		return fmt.Errorf("stockRepository.UpdateStockCount: %w", err)
+	if err := s.stockRepository.CreateStockMovements(ctx, movements); err != nil {
+		return nil, fmt.Errorf("stockRepository.CreateStockMovements: %w", err)
 	}
 
-	return nil
+	return allocations, nil
 }
@@ -2,40 +2,23 @@ package stock
 
 import (
 	"context"
-	"fmt"
 	"route256/loms/internal/domain"
 
 	"github.com/opentracing/opentracing-go"
 )
 
-func (s *Service) ReserveRemove(ctx context.Context, items []domain.Item) error {
+func (s *Service) ReserveRemove(ctx context.Context, orderID int64, allocations []domain.Allocation) error {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "stockService.ReserveRemove")
 	defer span.Finish()
 
-	stocks, err := s.stockRepository.GetStocksBySkuForUpdate(ctx, items)
-	if err != nil {
-		return fmt.Errorf("stockRepository.GetStockBySkuForUpdate: %w", err)
-	}
-
-	for _, item := range items {
-		stock, ok := stocks[item.Sku]
-		if !ok {
-			return fmt.Errorf("%w: sku %v", domain.ErrStockNotFound, item.Sku)
-		}
-
-		if stock.TotalCount < item.Count || stock.Reserved < item.Count {
+	return s.applyAllocations(ctx, domain.StockMovementReserveRemove, orderID, allocations, func(stock *domain.WarehouseStock, count int64) error {
+		if stock.TotalCount < count || stock.Reserved < count {
 			return domain.ErrInvalidReserveOperation
 		}
 
-		stock.TotalCount -= item.Count
-		stock.Reserved -= item.Count
-
-		stocks[item.Sku] = stock
-	}
-
-	if err := s.stockRepository.UpdateStocks(ctx, stocks); err != nil {
-		return fmt.Errorf("stockRepository.UpdateStockCount: %w", err)
-	}
+		stock.TotalCount -= count
+		stock.Reserved -= count
 
-	return nil
+		return nil
+	})
 }
@@ -13,31 +13,45 @@ import (
 func TestReserveRemove(t *testing.T) {
 	t.Parallel()
 
+	testOrderID := int64(12345)
 	testItem := domain.Item{Sku: 1001, Count: 2}
-	expected := make(map[domain.Sku]domain.Stock)
-	expected[1001] = domain.Stock{
-		TotalCount: 8,
-		Reserved:   3,
+	testAllocation := domain.Allocation{Sku: 1001, WarehouseID: 1, Count: 2}
+	expected := []domain.WarehouseStock{
+		{WarehouseID: 1, Sku: 1001, TotalCount: 8, Reserved: 3},
+	}
+	expectedMovements := []domain.StockMovement{
+		{
+			WarehouseID:   1,
+			Sku:           1001,
+			Kind:          domain.StockMovementReserveRemove,
+			OrderID:       testOrderID,
+			TotalDelta:    -2,
+			ReservedDelta: -2,
+			TotalCount:    8,
+			Reserved:      3,
+		},
 	}
 
 	type mocks struct {
-		getStockBySkuForUpdate testhelpers.NeedCallWithErrAndResult[map[domain.Sku]domain.Stock]
+		getStockBySkuForUpdate testhelpers.NeedCallWithErrAndResult[map[domain.Sku][]domain.WarehouseStock]
 		updateStocks           testhelpers.NeedCallWithErr
+		createStockMovements   testhelpers.NeedCallWithErr
 	}
 
 	testCases := []struct {
 		name        string
 		mocks       mocks
-		expected    map[domain.Sku]domain.Stock
+		expected    []domain.WarehouseStock
 		expectedErr error
 	}{
 		{
 			name: "success: valid reserve removal",
 			mocks: mocks{
-				getStockBySkuForUpdate: testhelpers.NewNeedCallWithErrAndResult(map[domain.Sku]domain.Stock{
-					1001: {TotalCount: 10, Reserved: 5},
+				getStockBySkuForUpdate: testhelpers.NewNeedCallWithErrAndResult(map[domain.Sku][]domain.WarehouseStock{
+					1001: {{WarehouseID: 1, Sku: 1001, TotalCount: 10, Reserved: 5}},
 				}, nil),
-				updateStocks: testhelpers.NewNeedCallWithErr(nil),
+				updateStocks:         testhelpers.NewNeedCallWithErr(nil),
+				createStockMovements: testhelpers.NewNeedCallWithErr(nil),
 			},
 			expected:    expected,
 			expectedErr: nil,
@@ -45,15 +59,15 @@ func TestReserveRemove(t *testing.T) {
 		{
 			name: "fail: GetStockBySkuForUpdate returns error",
 			mocks: mocks{
-				getStockBySkuForUpdate: testhelpers.NewNeedCallWithErrAndResult(map[domain.Sku]domain.Stock{}, testhelpers.ErrForTest),
+				getStockBySkuForUpdate: testhelpers.NewNeedCallWithErrAndResult(map[domain.Sku][]domain.WarehouseStock{}, testhelpers.ErrForTest),
 			},
 			expectedErr: fmt.Errorf("stockRepository.GetStockBySkuForUpdate: %w", testhelpers.ErrForTest),
 		},
 		{
 			name: "fail: not enough reserved",
 			mocks: mocks{
-				getStockBySkuForUpdate: testhelpers.NewNeedCallWithErrAndResult(map[domain.Sku]domain.Stock{
-					1001: {TotalCount: 10, Reserved: 1},
+				getStockBySkuForUpdate: testhelpers.NewNeedCallWithErrAndResult(map[domain.Sku][]domain.WarehouseStock{
+					1001: {{WarehouseID: 1, Sku: 1001, TotalCount: 10, Reserved: 1}},
 				}, nil),
 			},
 			expectedErr: domain.ErrInvalidReserveOperation,
@@ -61,8 +75,8 @@ func TestReserveRemove(t *testing.T) {
 		{
 			name: "fail: not enough total count",
 			mocks: mocks{
-				getStockBySkuForUpdate: testhelpers.NewNeedCallWithErrAndResult(map[domain.Sku]domain.Stock{
-					1001: {TotalCount: 1, Reserved: 5},
+				getStockBySkuForUpdate: testhelpers.NewNeedCallWithErrAndResult(map[domain.Sku][]domain.WarehouseStock{
+					1001: {{WarehouseID: 1, Sku: 1001, TotalCount: 1, Reserved: 5}},
 				}, nil),
 			},
 			expectedErr: domain.ErrInvalidReserveOperation,
@@ -70,8 +84,8 @@ func TestReserveRemove(t *testing.T) {
 		{
 			name: "fail: UpdateStocks returns error",
 			mocks: mocks{
-				getStockBySkuForUpdate: testhelpers.NewNeedCallWithErrAndResult(map[domain.Sku]domain.Stock{
-					1001: {TotalCount: 10, Reserved: 5},
+				getStockBySkuForUpdate: testhelpers.NewNeedCallWithErrAndResult(map[domain.Sku][]domain.WarehouseStock{
+					1001: {{WarehouseID: 1, Sku: 1001, TotalCount: 10, Reserved: 5}},
 				}, nil),
 				updateStocks: testhelpers.NewNeedCallWithErr(testhelpers.ErrForTest),
 			},
@@ -79,9 +93,21 @@ func TestReserveRemove(t *testing.T) {
 			expectedErr: fmt.Errorf("stockRepository.UpdateStockCount: %w", testhelpers.ErrForTest),
 		},
 		{
-			name: "fail: sku not found",
+			name: "fail: CreateStockMovements returns error",
 			mocks: mocks{
-				getStockBySkuForUpdate: testhelpers.NewNeedCallWithErrAndResult(map[domain.Sku]domain.Stock{}, nil),
+				getStockBySkuForUpdate: testhelpers.NewNeedCallWithErrAndResult(map[domain.Sku][]domain.WarehouseStock{
+					1001: {{WarehouseID: 1, Sku: 1001, TotalCount: 10, Reserved: 5}},
+				}, nil),
+				updateStocks:         testhelpers.NewNeedCallWithErr(nil),
+				createStockMovements: testhelpers.NewNeedCallWithErr(testhelpers.ErrForTest),
+			},
+			expected:    expected,
+			expectedErr: fmt.Errorf("stockRepository.CreateStockMovements: %w", testhelpers.ErrForTest),
+		},
+		{
+			name: "fail: warehouse stock not found",
+			mocks: mocks{
+				getStockBySkuForUpdate: testhelpers.NewNeedCallWithErrAndResult(map[domain.Sku][]domain.WarehouseStock{}, nil),
 			},
 			expectedErr: fmt.Errorf("%w: sku %v", domain.ErrStockNotFound, testItem.Sku),
 		},
@@ -106,7 +132,13 @@ func TestReserveRemove(t *testing.T) {
 					Return(tc.mocks.updateStocks.Err)
 			}
 
-			err := f.executor.ReserveRemove(ctx, []domain.Item{testItem})
+			if tc.mocks.createStockMovements.NeedCall {
+				f.repository.CreateStockMovementsMock.
+					Expect(minimock.AnyContext, expectedMovements).
+					Return(tc.mocks.createStockMovements.Err)
+			}
+
+			err := f.executor.ReserveRemove(ctx, testOrderID, []domain.Allocation{testAllocation})
 
 			if tc.expectedErr != nil {
 				f.Error(err)
@@ -13,34 +13,65 @@ import (
 func TestReserve(t *testing.T) {
 	t.Parallel()
 
+	testOrderID := int64(12345)
 	testItem := domain.Item{Sku: 1001, Count: 2}
-	expected := make(map[domain.Sku]domain.Stock)
-	expected[1001] = domain.Stock{
-		TotalCount: 10,
-		Reserved:   7,
+	testStocks := map[domain.Sku][]domain.WarehouseStock{
+		1001: {
+			{WarehouseID: 1, Sku: 1001, TotalCount: 10, Reserved: 9},
+			{WarehouseID: 2, Sku: 1001, TotalCount: 10, Reserved: 5},
+		},
+	}
+	testAllocations := []domain.Allocation{
+		{Sku: 1001, WarehouseID: 1, Count: 1},
+		{Sku: 1001, WarehouseID: 2, Count: 1},
+	}
+	expected := []domain.WarehouseStock{
+		{WarehouseID: 1, Sku: 1001, TotalCount: 10, Reserved: 10},
+		{WarehouseID: 2, Sku: 1001, TotalCount: 10, Reserved: 6},
+	}
+	expectedMovements := []domain.StockMovement{
+		{
+			WarehouseID:   1,
+			Sku:           1001,
+			Kind:          domain.StockMovementReserve,
+			OrderID:       testOrderID,
+			TotalDelta:    0,
+			ReservedDelta: 1,
+			TotalCount:    10,
+			Reserved:      10,
+		},
+		{
+			WarehouseID:   2,
+			Sku:           1001,
+			Kind:          domain.StockMovementReserve,
+			OrderID:       testOrderID,
+			TotalDelta:    0,
+			ReservedDelta: 1,
+			TotalCount:    10,
+			Reserved:      6,
+		},
 	}
 
 	type mocks struct {
-		getStockBySkuForUpdate testhelpers.NeedCallWithErrAndResult[map[domain.Sku]domain.Stock]
+		getStockBySkuForUpdate testhelpers.NeedCallWithErrAndResult[map[domain.Sku][]domain.WarehouseStock]
+		allocate               testhelpers.NeedCallWithErrAndResult[[]domain.Allocation]
 		updateStocks           testhelpers.NeedCallWithErr
+		createStockMovements   testhelpers.NeedCallWithErr
 	}
 
 	testCases := []struct {
 		name        string
 		mocks       mocks
-		expected    map[domain.Sku]domain.Stock
+		expected    []domain.WarehouseStock
 		expectedErr error
 	}{
 		{
 			name: "success: enough stock, update success",
 			mocks: mocks{
-				getStockBySkuForUpdate: testhelpers.NewNeedCallWithErrAndResult(map[domain.Sku]domain.Stock{
-					1001: {
-						TotalCount: 10,
-						Reserved:   5,
-					},
-				}, nil),
-				updateStocks: testhelpers.NewNeedCallWithErr(nil),
+				getStockBySkuForUpdate: testhelpers.NewNeedCallWithErrAndResult(testStocks, nil),
+				allocate:               testhelpers.NewNeedCallWithErrAndResult(testAllocations, nil),
+				updateStocks:           testhelpers.NewNeedCallWithErr(nil),
+				createStockMovements:   testhelpers.NewNeedCallWithErr(nil),
 			},
 			expected:    expected,
 			expectedErr: nil,
@@ -48,18 +79,24 @@ func TestReserve(t *testing.T) {
 		{
 			name: "fail: GetStockBySkuForUpdate returns error",
 			mocks: mocks{
-				getStockBySkuForUpdate: testhelpers.NewNeedCallWithErrAndResult(map[domain.Sku]domain.Stock{}, testhelpers.ErrForTest),
+				getStockBySkuForUpdate: testhelpers.NewNeedCallWithErrAndResult(map[domain.Sku][]domain.WarehouseStock{}, testhelpers.ErrForTest),
 			},
 			expectedErr: fmt.Errorf("stockRepository.GetStockBySkuForUpdate: %w", testhelpers.ErrForTest),
 		},
 		{
-			name: "fail: not enough stock",
+			name: "fail: allocation strategy returns not enough stock",
+			mocks: mocks{
+				getStockBySkuForUpdate: testhelpers.NewNeedCallWithErrAndResult(testStocks, nil),
+				allocate:               testhelpers.NewNeedCallWithErrAndResult([]domain.Allocation(nil), domain.ErrNotEnoughStock),
+			},
+			expectedErr: domain.ErrNotEnoughStock,
+		},
+		{
+			name: "fail: allocation exceeds warehouse stock",
 			mocks: mocks{
-				getStockBySkuForUpdate: testhelpers.NewNeedCallWithErrAndResult(map[domain.Sku]domain.Stock{
-					1001: {
-						TotalCount: 6,
-						Reserved:   5,
-					},
+				getStockBySkuForUpdate: testhelpers.NewNeedCallWithErrAndResult(testStocks, nil),
+				allocate: testhelpers.NewNeedCallWithErrAndResult([]domain.Allocation{
+					{Sku: 1001, WarehouseID: 1, Count: 2},
 				}, nil),
 			},
 			expectedErr: domain.ErrNotEnoughStock,
@@ -67,21 +104,28 @@ func TestReserve(t *testing.T) {
 		{
 			name: "fail: UpdateStocks returns error",
 			mocks: mocks{
-				getStockBySkuForUpdate: testhelpers.NewNeedCallWithErrAndResult(map[domain.Sku]domain.Stock{
-					1001: {
-						TotalCount: 10,
-						Reserved:   5,
-					},
-				}, nil),
-				updateStocks: testhelpers.NewNeedCallWithErr(testhelpers.ErrForTest),
+				getStockBySkuForUpdate: testhelpers.NewNeedCallWithErrAndResult(testStocks, nil),
+				allocate:               testhelpers.NewNeedCallWithErrAndResult(testAllocations, nil),
+				updateStocks:           testhelpers.NewNeedCallWithErr(testhelpers.ErrForTest),
 			},
 			expected:    expected,
 			expectedErr: fmt.Errorf("stockRepository.UpdateStockCount: %w", testhelpers.ErrForTest),
 		},
+		{
+			name: "fail: CreateStockMovements returns error",
+			mocks: mocks{
+				getStockBySkuForUpdate: testhelpers.NewNeedCallWithErrAndResult(testStocks, nil),
+				allocate:               testhelpers.NewNeedCallWithErrAndResult(testAllocations, nil),
+				updateStocks:           testhelpers.NewNeedCallWithErr(nil),
+				createStockMovements:   testhelpers.NewNeedCallWithErr(testhelpers.ErrForTest),
+			},
+			expected:    expected,
+			expectedErr: fmt.Errorf("stockRepository.CreateStockMovements: %w", testhelpers.ErrForTest),
+		},
 		{
 			name: "fail: stock not found",
 			mocks: mocks{
-				getStockBySkuForUpdate: testhelpers.NewNeedCallWithErrAndResult(map[domain.Sku]domain.Stock{}, nil),
+				getStockBySkuForUpdate: testhelpers.NewNeedCallWithErrAndResult(map[domain.Sku][]domain.WarehouseStock{}, nil),
 			},
 			expectedErr: fmt.Errorf("%w: sku %v", domain.ErrStockNotFound, testItem.Sku),
 		},
@@ -100,19 +144,126 @@ func TestReserve(t *testing.T) {
 					Return(tc.mocks.getStockBySkuForUpdate.Result, tc.mocks.getStockBySkuForUpdate.Err)
 			}
 
+			if tc.mocks.allocate.NeedCall {
+				f.strategy.AllocateMock.
+					Expect([]domain.Item{testItem}, tc.mocks.getStockBySkuForUpdate.Result).
+					Return(tc.mocks.allocate.Result, tc.mocks.allocate.Err)
+			}
+
 			if tc.mocks.updateStocks.NeedCall {
 				f.repository.UpdateStocksMock.
 					Expect(minimock.AnyContext, tc.expected).
 					Return(tc.mocks.updateStocks.Err)
 			}
 
-			err := f.executor.Reserve(ctx, []domain.Item{testItem})
+			if tc.mocks.createStockMovements.NeedCall {
+				f.repository.CreateStockMovementsMock.
+					Expect(minimock.AnyContext, expectedMovements).
+					Return(tc.mocks.createStockMovements.Err)
+			}
+
+			allocations, err := f.executor.Reserve(ctx, testOrderID, []domain.Item{testItem})
 
 			if tc.expectedErr != nil {
 				f.Error(err)
 				f.ErrorContains(err, tc.expectedErr.Error())
 			} else {
 				f.NoError(err)
+				f.Equal(testAllocations, allocations)
+			}
+		})
+	}
+}
+
+func TestReserveDuplicateSku(t *testing.T) {
+	t.Parallel()
+
+	testOrderID := int64(12345)
+	testItems := []domain.Item{{Sku: 1001, Count: 5}, {Sku: 1001, Count: 5}}
+	testStocks := map[domain.Sku][]domain.WarehouseStock{
+		1001: {
+			{WarehouseID: 1, Sku: 1001, TotalCount: 20, Reserved: 0},
+		},
+	}
+
+	testCases := []struct {
+		name              string
+		allocations       []domain.Allocation
+		expected          []domain.WarehouseStock
+		expectedMovements []domain.StockMovement
+		expectedErr       error
+	}{
+		{
+			name: "success: allocations of the same warehouse are accumulated",
+			allocations: []domain.Allocation{
+				{Sku: 1001, WarehouseID: 1, Count: 5},
+				{Sku: 1001, WarehouseID: 1, Count: 5},
+			},
+			expected: []domain.WarehouseStock{
+				{WarehouseID: 1, Sku: 1001, TotalCount: 20, Reserved: 10},
+			},
+			expectedMovements: []domain.StockMovement{
+				{
+					WarehouseID:   1,
+					Sku:           1001,
+					Kind:          domain.StockMovementReserve,
+					OrderID:       testOrderID,
+					ReservedDelta: 5,
+					TotalCount:    20,
+					Reserved:      5,
+				},
+				{
+					WarehouseID:   1,
+					Sku:           1001,
+					Kind:          domain.StockMovementReserve,
+					OrderID:       testOrderID,
+					ReservedDelta: 5,
+					TotalCount:    20,
+					Reserved:      10,
+				},
+			},
+		},
+		{
+			name: "fail: accumulated allocations exceed warehouse stock",
+			allocations: []domain.Allocation{
+				{Sku: 1001, WarehouseID: 1, Count: 15},
+				{Sku: 1001, WarehouseID: 1, Count: 15},
+			},
+			expectedErr: domain.ErrNotEnoughStock,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			f := setUp(t)
+
+			f.repository.GetStocksBySkuForUpdateMock.
+				Expect(minimock.AnyContext, testItems).
+				Return(testStocks, nil)
+
+			f.strategy.AllocateMock.
+				Expect(testItems, testStocks).
+				Return(tc.allocations, nil)
+
+			if tc.expectedErr == nil {
+				f.repository.UpdateStocksMock.
+					Expect(minimock.AnyContext, tc.expected).
+					Return(nil)
+				f.repository.CreateStockMovementsMock.
+					Expect(minimock.AnyContext, tc.expectedMovements).
+					Return(nil)
+			}
+
+			allocations, err := f.executor.Reserve(ctx, testOrderID, testItems)
+
+			if tc.expectedErr != nil {
+				f.ErrorIs(err, tc.expectedErr)
+			} else {
+				f.NoError(err)
+				f.Equal(tc.allocations, allocations)
 			}
 		})
 	}
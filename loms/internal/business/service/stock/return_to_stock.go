@@ -0,0 +1,19 @@
+package stock
+
+import (
+	"context"
+	"route256/loms/internal/domain"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+func (s *Service) ReturnToStock(ctx context.Context, orderID int64, allocations []domain.Allocation) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "stockService.ReturnToStock")
+	defer span.Finish()
+
+	return s.applyAllocations(ctx, domain.StockMovementReturn, orderID, allocations, func(stock *domain.WarehouseStock, count int64) error {
+		stock.TotalCount += count
+
+		return nil
+	})
+}
@@ -0,0 +1,133 @@
+package stock_test
+
+import (
+	"context"
+	"fmt"
+	"route256/loms/internal/domain"
+	testhelpers "route256/loms/internal/tool"
+	"testing"
+
+	"github.com/gojuno/minimock/v3"
+)
+
+func TestReturnToStock(t *testing.T) {
+	t.Parallel()
+
+	testOrderID := int64(12345)
+	testItem := domain.Item{Sku: 1001, Count: 2}
+	testAllocation := domain.Allocation{Sku: 1001, WarehouseID: 1, Count: 2}
+	expected := []domain.WarehouseStock{
+		{WarehouseID: 1, Sku: 1001, TotalCount: 12, Reserved: 5},
+	}
+	expectedMovements := []domain.StockMovement{
+		{
+			WarehouseID:   1,
+			Sku:           1001,
+			Kind:          domain.StockMovementReturn,
+			OrderID:       testOrderID,
+			TotalDelta:    2,
+			ReservedDelta: 0,
+			TotalCount:    12,
+			Reserved:      5,
+		},
+	}
+
+	type mocks struct {
+		getStockBySkuForUpdate testhelpers.NeedCallWithErrAndResult[map[domain.Sku][]domain.WarehouseStock]
+		updateStocks           testhelpers.NeedCallWithErr
+		createStockMovements   testhelpers.NeedCallWithErr
+	}
+
+	testCases := []struct {
+		name        string
+		mocks       mocks
+		expected    []domain.WarehouseStock
+		expectedErr error
+	}{
+		{
+			name: "success: return to stock successful",
+			mocks: mocks{
+				getStockBySkuForUpdate: testhelpers.NewNeedCallWithErrAndResult(map[domain.Sku][]domain.WarehouseStock{
+					1001: {{WarehouseID: 1, Sku: 1001, TotalCount: 10, Reserved: 5}},
+				}, nil),
+				updateStocks:         testhelpers.NewNeedCallWithErr(nil),
+				createStockMovements: testhelpers.NewNeedCallWithErr(nil),
+			},
+			expected:    expected,
+			expectedErr: nil,
+		},
+		{
+			name: "fail: GetStockBySkuForUpdate returns error",
+			mocks: mocks{
+				getStockBySkuForUpdate: testhelpers.NewNeedCallWithErrAndResult(map[domain.Sku][]domain.WarehouseStock{}, testhelpers.ErrForTest),
+			},
+			expectedErr: fmt.Errorf("stockRepository.GetStockBySkuForUpdate: %w", testhelpers.ErrForTest),
+		},
+		{
+			name: "fail: UpdateStocks returns error",
+			mocks: mocks{
+				getStockBySkuForUpdate: testhelpers.NewNeedCallWithErrAndResult(map[domain.Sku][]domain.WarehouseStock{
+					1001: {{WarehouseID: 1, Sku: 1001, TotalCount: 10, Reserved: 5}},
+				}, nil),
+				updateStocks: testhelpers.NewNeedCallWithErr(testhelpers.ErrForTest),
+			},
+			expected:    expected,
+			expectedErr: fmt.Errorf("stockRepository.UpdateStockCount: %w", testhelpers.ErrForTest),
+		},
+		{
+			name: "fail: CreateStockMovements returns error",
+			mocks: mocks{
+				getStockBySkuForUpdate: testhelpers.NewNeedCallWithErrAndResult(map[domain.Sku][]domain.WarehouseStock{
+					1001: {{WarehouseID: 1, Sku: 1001, TotalCount: 10, Reserved: 5}},
+				}, nil),
+				updateStocks:         testhelpers.NewNeedCallWithErr(nil),
+				createStockMovements: testhelpers.NewNeedCallWithErr(testhelpers.ErrForTest),
+			},
+			expected:    expected,
+			expectedErr: fmt.Errorf("stockRepository.CreateStockMovements: %w", testhelpers.ErrForTest),
+		},
+		{
+			name: "fail: warehouse stock not found",
+			mocks: mocks{
+				getStockBySkuForUpdate: testhelpers.NewNeedCallWithErrAndResult(map[domain.Sku][]domain.WarehouseStock{}, nil),
+			},
+			expectedErr: fmt.Errorf("%w: sku %v", domain.ErrStockNotFound, testItem.Sku),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			f := setUp(t)
+
+			if tc.mocks.getStockBySkuForUpdate.NeedCall {
+				f.repository.GetStocksBySkuForUpdateMock.
+					Expect(minimock.AnyContext, []domain.Item{testItem}).
+					Return(tc.mocks.getStockBySkuForUpdate.Result, tc.mocks.getStockBySkuForUpdate.Err)
+			}
+
+			if tc.mocks.updateStocks.NeedCall {
+				f.repository.UpdateStocksMock.
+					Expect(minimock.AnyContext, tc.expected).
+					Return(tc.mocks.updateStocks.Err)
+			}
+
+			if tc.mocks.createStockMovements.NeedCall {
+				f.repository.CreateStockMovementsMock.
+					Expect(minimock.AnyContext, expectedMovements).
+					Return(tc.mocks.createStockMovements.Err)
+			}
+
+			err := f.executor.ReturnToStock(ctx, testOrderID, []domain.Allocation{testAllocation})
+
+			if tc.expectedErr != nil {
+				f.Error(err)
+				f.ErrorContains(err, tc.expectedErr.Error())
+			} else {
+				f.NoError(err)
+			}
+		})
+	}
+}
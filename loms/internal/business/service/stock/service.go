@@ -2,24 +2,137 @@ package stock
 
 import (
 	"context"
+	"fmt"
 	"route256/loms/internal/domain"
+	txmanager "route256/loms/internal/infra/tx_manager"
 )
 
 //go:generate rm -rf mock
 //go:generate mkdir -p mock
 //go:generate minimock -i * -o ./mock -s "_mock.go" -g
 type stockRepository interface {
-	GetStockBySku(ctx context.Context, sku domain.Sku) (domain.Stock, error)
-	GetStocksBySkuForUpdate(ctx context.Context, items []domain.Item) (map[domain.Sku]domain.Stock, error)
-	UpdateStocks(ctx context.Context, stocks map[domain.Sku]domain.Stock) error
+	GetWarehouseStocksBySku(ctx context.Context, sku domain.Sku) ([]domain.WarehouseStock, error)
+	GetStocksBySkuForUpdate(ctx context.Context, items []domain.Item) (map[domain.Sku][]domain.WarehouseStock, error)
+	UpdateStocks(ctx context.Context, stocks []domain.WarehouseStock) error
+	CreateStockMovements(ctx context.Context, movements []domain.StockMovement) error
+}
+
+type allocationStrategy interface {
+	Allocate(items []domain.Item, stocks map[domain.Sku][]domain.WarehouseStock) ([]domain.Allocation, error)
+}
+
+type txManager interface {
+	ReadCommitted(ctx context.Context, f txmanager.Handler) error
 }
 
 type Service struct {
-	stockRepository stockRepository
+	stockRepository    stockRepository
+	allocationStrategy allocationStrategy
+	txManager          txManager
 }
 
-func New(stockRepository stockRepository) *Service {
+func New(stockRepository stockRepository, allocationStrategy allocationStrategy, txManager txManager) *Service {
 	return &Service{
-		stockRepository: stockRepository,
+		stockRepository:    stockRepository,
+		allocationStrategy: allocationStrategy,
+		txManager:          txManager,
 	}
 }
+
+type warehouseSku struct {
+	warehouseID int64
+	sku         domain.Sku
+}
+
+func (s *Service) applyAllocations(
+	ctx context.Context,
+	kind domain.StockMovementKind,
+	orderID int64,
+	allocations []domain.Allocation,
+	apply func(stock *domain.WarehouseStock, count int64) error) error {
+	return s.txManager.ReadCommitted(ctx, func(ctx context.Context) error {
+		return s.applyAllocationsInTx(ctx, kind, orderID, allocations, apply)
+	})
+}
+
+func (s *Service) applyAllocationsInTx(
+	ctx context.Context,
+	kind domain.StockMovementKind,
+	orderID int64,
+	allocations []domain.Allocation,
+	apply func(stock *domain.WarehouseStock, count int64) error) error {
+	items := make([]domain.Item, len(allocations))
+	for idx, allocation := range allocations {
+		items[idx] = domain.Item{Sku: allocation.Sku, Count: allocation.Count}
+	}
+
+	stocks, err := s.stockRepository.GetStocksBySkuForUpdate(ctx, items)
+	if err != nil {
+		return fmt.Errorf("stockRepository.GetStockBySkuForUpdate: %w", err)
+	}
+
+	index := make(map[warehouseSku]int)
+	updated := make([]domain.WarehouseStock, 0, len(allocations))
+	movements := make([]domain.StockMovement, 0, len(allocations))
+
+	for _, allocation := range allocations {
+		key := warehouseSku{warehouseID: allocation.WarehouseID, sku: allocation.Sku}
+
+		idx, ok := index[key]
+		if !ok {
+			stock, found := findWarehouseStock(stocks[allocation.Sku], allocation.WarehouseID)
+			if !found {
+				return fmt.Errorf("%w: sku %v warehouse %d", domain.ErrStockNotFound, allocation.Sku, allocation.WarehouseID)
+			}
+
+			idx = len(updated)
+			index[key] = idx
+			updated = append(updated, stock)
+		}
+
+		before := updated[idx]
+
+		if err := apply(&updated[idx], allocation.Count); err != nil {
+			return err
+		}
+
+		movements = append(movements, newStockMovement(kind, orderID, before, updated[idx]))
+	}
+
+	if err := s.stockRepository.UpdateStocks(ctx, updated); err != nil {
+		return fmt.Errorf("stockRepository.UpdateStockCount: %w", err)
+	}
+
+	if err := s.stockRepository.CreateStockMovements(ctx, movements); err != nil {
+		return fmt.Errorf("stockRepository.CreateStockMovements: %w", err)
+	}
+
+	return nil
+}
+
+func newStockMovement(
+	kind domain.StockMovementKind,
+	orderID int64,
+	before domain.WarehouseStock,
+	after domain.WarehouseStock) domain.StockMovement {
+	return domain.StockMovement{
+		WarehouseID:   after.WarehouseID,
+		Sku:           after.Sku,
+		Kind:          kind,
+		OrderID:       orderID,
+		TotalDelta:    after.TotalCount - before.TotalCount,
+		ReservedDelta: after.Reserved - before.Reserved,
+		TotalCount:    after.TotalCount,
+		Reserved:      after.Reserved,
+	}
+}
+
+func findWarehouseStock(stocks []domain.WarehouseStock, warehouseID int64) (domain.WarehouseStock, bool) {
+	for _, stock := range stocks {
+		if stock.WarehouseID == warehouseID {
+			return stock, true
+		}
+	}
+
+	return domain.WarehouseStock{}, false
+}
@@ -8,20 +8,23 @@ import (
 	"github.com/opentracing/opentracing-go"
 )
 
-func (s *Service) StocksInfo(ctx context.Context, sku domain.Sku) (int64, error) {
+func (s *Service) StocksInfo(ctx context.Context, sku domain.Sku) (int64, []domain.WarehouseStock, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "stockService.StocksInfo")
 	defer span.Finish()
 
-	stockData, err := s.stockRepository.GetStockBySku(ctx, sku)
+	stocks, err := s.stockRepository.GetWarehouseStocksBySku(ctx, sku)
 	if err != nil {
-		return 0, fmt.Errorf("stockRepository.GetStockBySku: %w", err)
+		return 0, nil, fmt.Errorf("stockRepository.GetWarehouseStocksBySku: %w", err)
 	}
 
-	remainderCount := stockData.TotalCount - stockData.Reserved
+	var remainderCount int64
+	for _, stock := range stocks {
+		remainderCount += stock.TotalCount - stock.Reserved
+	}
 
 	if remainderCount <= 0 {
-		return 0, domain.ErrNotEnoughStock
+		return 0, nil, domain.ErrNotEnoughStock
 	}
 
-	return remainderCount, nil
+	return remainderCount, stocks, nil
 }
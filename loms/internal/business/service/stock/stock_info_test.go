@@ -15,7 +15,7 @@ func TestStocksInfo(t *testing.T) {
 	testSku := domain.Sku(12345)
 
 	type mocks struct {
-		mockGetStockBySku testhelpers.NeedCallWithErrAndResult[domain.Stock]
+		mockGetStockBySku testhelpers.NeedCallWithErrAndResult[[]domain.WarehouseStock]
 	}
 
 	testCases := []struct {
@@ -28,19 +28,19 @@ func TestStocksInfo(t *testing.T) {
 		{
 			name: "success: enough stock",
 			mocks: mocks{
-				mockGetStockBySku: testhelpers.NewNeedCallWithErrAndResult(domain.Stock{
-					TotalCount: 20,
-					Reserved:   5,
+				mockGetStockBySku: testhelpers.NewNeedCallWithErrAndResult([]domain.WarehouseStock{
+					{WarehouseID: 1, Sku: testSku, TotalCount: 20, Reserved: 5},
+					{WarehouseID: 2, Sku: testSku, TotalCount: 7, Reserved: 2},
 				}, nil),
 			},
 			expectedSku:   testSku,
-			expectedStock: 15,
+			expectedStock: 20,
 			expectedErr:   nil,
 		},
 		{
 			name: "fail: repository error",
 			mocks: mocks{
-				mockGetStockBySku: testhelpers.NewNeedCallWithErrAndResult(domain.Stock{}, testhelpers.ErrForTest),
+				mockGetStockBySku: testhelpers.NewNeedCallWithErrAndResult([]domain.WarehouseStock(nil), testhelpers.ErrForTest),
 			},
 			expectedSku:   testSku,
 			expectedStock: 0,
@@ -49,9 +49,8 @@ func TestStocksInfo(t *testing.T) {
 		{
 			name: "fail: not enough stock (zero remainder)",
 			mocks: mocks{
-				mockGetStockBySku: testhelpers.NewNeedCallWithErrAndResult(domain.Stock{
-					TotalCount: 10,
-					Reserved:   10,
+				mockGetStockBySku: testhelpers.NewNeedCallWithErrAndResult([]domain.WarehouseStock{
+					{WarehouseID: 1, Sku: testSku, TotalCount: 10, Reserved: 10},
 				}, nil),
 			},
 			expectedSku:   testSku,
@@ -61,9 +60,8 @@ func TestStocksInfo(t *testing.T) {
 		{
 			name: "fail: not enough stock (negative remainder)",
 			mocks: mocks{
-				mockGetStockBySku: testhelpers.NewNeedCallWithErrAndResult(domain.Stock{
-					TotalCount: 5,
-					Reserved:   10,
+				mockGetStockBySku: testhelpers.NewNeedCallWithErrAndResult([]domain.WarehouseStock{
+					{WarehouseID: 1, Sku: testSku, TotalCount: 5, Reserved: 10},
 				}, nil),
 			},
 			expectedSku:   testSku,
@@ -80,12 +78,12 @@ func TestStocksInfo(t *testing.T) {
 			f := setUp(t)
 
 			if tc.mocks.mockGetStockBySku.NeedCall {
-				f.repository.GetStockBySkuMock.
+				f.repository.GetWarehouseStocksBySkuMock.
 					Expect(minimock.AnyContext, tc.expectedSku).
 					Return(tc.mocks.mockGetStockBySku.Result, tc.mocks.mockGetStockBySku.Err)
 			}
 
-			stock, err := f.executor.StocksInfo(ctx, tc.expectedSku)
+			stock, warehouses, err := f.executor.StocksInfo(ctx, tc.expectedSku)
 
 			if tc.expectedErr != nil {
 				f.Error(err)
@@ -94,6 +92,7 @@ func TestStocksInfo(t *testing.T) {
 			} else {
 				f.NoError(err)
 				f.Equal(tc.expectedStock, stock)
+				f.Equal(tc.mocks.mockGetStockBySku.Result, warehouses)
 			}
 		})
 	}
@@ -1,8 +1,10 @@
 package stock_test
 
 import (
+	"context"
 	stockservice "route256/loms/internal/business/service/stock"
 	"route256/loms/internal/business/service/stock/mock"
+	txmanager "route256/loms/internal/infra/tx_manager"
 	"testing"
 
 	"github.com/gojuno/minimock/v3"
@@ -13,6 +15,7 @@ type fixture struct {
 	*assert.Assertions
 
 	repository *mock.StockRepositoryMock
+	strategy   *mock.AllocationStrategyMock
 
 	executor *stockservice.Service
 }
@@ -21,13 +24,20 @@ func setUp(t *testing.T) *fixture {
 	ctrl := minimock.NewController(t)
 
 	repository := mock.NewStockRepositoryMock(ctrl)
+	strategy := mock.NewAllocationStrategyMock(ctrl)
+	txManager := mock.NewTxManagerMock(ctrl)
 
-	executor := stockservice.New(repository)
+	txManager.ReadCommittedMock.Optional().Set(func(ctx context.Context, fn txmanager.Handler) error {
+		return fn(ctx)
+	})
+
+	executor := stockservice.New(repository, strategy, txManager)
 
 	return &fixture{
 		Assertions: assert.New(t),
 
 		repository: repository,
+		strategy:   strategy,
 
 		executor: executor,
 	}
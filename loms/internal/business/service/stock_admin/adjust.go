@@ -0,0 +1,52 @@
+package stockadmin
+
+import (
+	"context"
+	"fmt"
+	"route256/loms/internal/domain"
+	logger "route256/loms/internal/infra/logger"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+func (s *Service) Adjust(
+	ctx context.Context,
+	warehouseID int64,
+	sku domain.Sku,
+	totalCount int64,
+	reason string) (domain.WarehouseStock, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "stockAdminService.Adjust")
+	defer span.Finish()
+
+	span.SetTag("reason", reason)
+
+	var stock domain.WarehouseStock
+
+	err := s.txManagerMaster.ReadCommitted(ctx, func(ctx context.Context) error {
+		var err error
+
+		stock, err = s.getStockForUpdate(ctx, warehouseID, sku)
+		if err != nil {
+			return err
+		}
+
+		if stock.Reserved > totalCount {
+			return fmt.Errorf("%w: sku %v warehouse %d reserved %d",
+				domain.ErrInvalidStockCount, sku, warehouseID, stock.Reserved)
+		}
+
+		logger.Infof(ctx, "stock adjust: sku %v warehouse %d total_count %d -> %d, reason: %s",
+			sku, warehouseID, stock.TotalCount, totalCount, reason)
+
+		before := stock
+		stock.TotalCount = totalCount
+
+		return s.updateStock(ctx, domain.StockMovementAdjust, reason, before, stock)
+	})
+
+	if err != nil {
+		return domain.WarehouseStock{}, fmt.Errorf("txManager.ReadCommitted: %w", err)
+	}
+
+	return stock, nil
+}
@@ -0,0 +1,44 @@
+package stockadmin
+
+import (
+	"context"
+	"fmt"
+	"route256/loms/internal/domain"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+func (s *Service) CreateSku(ctx context.Context, warehouseID int64, sku domain.Sku, totalCount int64) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "stockAdminService.CreateSku")
+	defer span.Finish()
+
+	stock := domain.WarehouseStock{
+		WarehouseID: warehouseID,
+		Sku:         sku,
+		TotalCount:  totalCount,
+	}
+
+	err := s.txManagerMaster.ReadCommitted(ctx, func(ctx context.Context) error {
+		if err := s.stockRepository.CreateStock(ctx, stock); err != nil {
+			return fmt.Errorf("stockRepository.CreateStock: %w", err)
+		}
+
+		if err := s.stockRepository.CreateStockMovements(ctx, []domain.StockMovement{{
+			WarehouseID: warehouseID,
+			Sku:         sku,
+			Kind:        domain.StockMovementCreate,
+			TotalDelta:  totalCount,
+			TotalCount:  totalCount,
+		}}); err != nil {
+			return fmt.Errorf("stockRepository.CreateStockMovements: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("txManager.ReadCommitted: %w", err)
+	}
+
+	return nil
+}
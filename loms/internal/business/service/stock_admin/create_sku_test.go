@@ -0,0 +1,98 @@
+package stockadmin_test
+
+import (
+	"context"
+	"route256/loms/internal/domain"
+	testhelpers "route256/loms/internal/tool"
+	"testing"
+
+	"github.com/gojuno/minimock/v3"
+)
+
+func TestCreateSku(t *testing.T) {
+	t.Parallel()
+
+	testSku := domain.Sku(1001)
+	testWarehouseID := int64(2)
+
+	type mocks struct {
+		createStock          testhelpers.NeedCallWithErr
+		createStockMovements testhelpers.NeedCallWithErr
+	}
+
+	testCases := []struct {
+		name        string
+		mocks       mocks
+		expectedErr error
+	}{
+		{
+			name: "success: sku created",
+			mocks: mocks{
+				createStock:          testhelpers.NewNeedCallWithErr(nil),
+				createStockMovements: testhelpers.NewNeedCallWithErr(nil),
+			},
+		},
+		{
+			name: "fail: sku already exists",
+			mocks: mocks{
+				createStock: testhelpers.NewNeedCallWithErr(domain.ErrStockAlreadyExists),
+			},
+			expectedErr: domain.ErrStockAlreadyExists,
+		},
+		{
+			name: "fail: CreateStock returns error",
+			mocks: mocks{
+				createStock: testhelpers.NewNeedCallWithErr(testhelpers.ErrForTest),
+			},
+			expectedErr: testhelpers.ErrForTest,
+		},
+		{
+			name: "fail: CreateStockMovements returns error",
+			mocks: mocks{
+				createStock:          testhelpers.NewNeedCallWithErr(nil),
+				createStockMovements: testhelpers.NewNeedCallWithErr(testhelpers.ErrForTest),
+			},
+			expectedErr: testhelpers.ErrForTest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			f := setUp(t)
+
+			if tc.mocks.createStock.NeedCall {
+				f.repository.CreateStockMock.
+					Expect(minimock.AnyContext, domain.WarehouseStock{
+						WarehouseID: testWarehouseID,
+						Sku:         testSku,
+						TotalCount:  10,
+					}).
+					Return(tc.mocks.createStock.Err)
+			}
+
+			if tc.mocks.createStockMovements.NeedCall {
+				f.repository.CreateStockMovementsMock.
+					Expect(minimock.AnyContext, []domain.StockMovement{{
+						WarehouseID: testWarehouseID,
+						Sku:         testSku,
+						Kind:        domain.StockMovementCreate,
+						TotalDelta:  10,
+						TotalCount:  10,
+					}}).
+					Return(tc.mocks.createStockMovements.Err)
+			}
+
+			err := f.executor.CreateSku(ctx, testWarehouseID, testSku, 10)
+
+			if tc.expectedErr != nil {
+				f.Error(err)
+				f.ErrorIs(err, tc.expectedErr)
+			} else {
+				f.NoError(err)
+			}
+		})
+	}
+}
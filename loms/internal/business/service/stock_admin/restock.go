@@ -0,0 +1,40 @@
+package stockadmin
+
+import (
+	"context"
+	"fmt"
+	"route256/loms/internal/domain"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+func (s *Service) Restock(
+	ctx context.Context,
+	warehouseID int64,
+	sku domain.Sku,
+	count int64) (domain.WarehouseStock, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "stockAdminService.Restock")
+	defer span.Finish()
+
+	var stock domain.WarehouseStock
+
+	err := s.txManagerMaster.ReadCommitted(ctx, func(ctx context.Context) error {
+		var err error
+
+		stock, err = s.getStockForUpdate(ctx, warehouseID, sku)
+		if err != nil {
+			return err
+		}
+
+		before := stock
+		stock.TotalCount += count
+
+		return s.updateStock(ctx, domain.StockMovementRestock, "", before, stock)
+	})
+
+	if err != nil {
+		return domain.WarehouseStock{}, fmt.Errorf("txManager.ReadCommitted: %w", err)
+	}
+
+	return stock, nil
+}
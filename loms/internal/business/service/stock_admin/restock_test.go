@@ -0,0 +1,141 @@
+package stockadmin_test
+
+import (
+	"context"
+	"route256/loms/internal/domain"
+	testhelpers "route256/loms/internal/tool"
+	"testing"
+
+	"github.com/gojuno/minimock/v3"
+)
+
+func TestRestock(t *testing.T) {
+	t.Parallel()
+
+	testSku := domain.Sku(1001)
+	testWarehouseID := int64(2)
+
+	type mocks struct {
+		getStocksBySkuForUpdate testhelpers.NeedCallWithErrAndResult[map[domain.Sku][]domain.WarehouseStock]
+		updateStocks            testhelpers.NeedCallWithErr
+		createStockMovements    testhelpers.NeedCallWithErr
+	}
+
+	testCases := []struct {
+		name        string
+		mocks       mocks
+		expected    domain.WarehouseStock
+		expectedErr error
+	}{
+		{
+			name: "success: restock adds count to total",
+			mocks: mocks{
+				getStocksBySkuForUpdate: testhelpers.NewNeedCallWithErrAndResult(map[domain.Sku][]domain.WarehouseStock{
+					testSku: {
+						{WarehouseID: 1, Sku: testSku, TotalCount: 7, Reserved: 0},
+						{WarehouseID: testWarehouseID, Sku: testSku, TotalCount: 10, Reserved: 3},
+					},
+				}, nil),
+				updateStocks:         testhelpers.NewNeedCallWithErr(nil),
+				createStockMovements: testhelpers.NewNeedCallWithErr(nil),
+			},
+			expected: domain.WarehouseStock{WarehouseID: testWarehouseID, Sku: testSku, TotalCount: 15, Reserved: 3},
+		},
+		{
+			name: "fail: GetStocksBySkuForUpdate returns error",
+			mocks: mocks{
+				getStocksBySkuForUpdate: testhelpers.NewNeedCallWithErrAndResult(map[domain.Sku][]domain.WarehouseStock{}, testhelpers.ErrForTest),
+			},
+			expectedErr: testhelpers.ErrForTest,
+		},
+		{
+			name: "fail: sku not found in returned map",
+			mocks: mocks{
+				getStocksBySkuForUpdate: testhelpers.NewNeedCallWithErrAndResult(map[domain.Sku][]domain.WarehouseStock{}, nil),
+			},
+			expectedErr: domain.ErrStockNotFound,
+		},
+		{
+			name: "fail: warehouse not found for sku",
+			mocks: mocks{
+				getStocksBySkuForUpdate: testhelpers.NewNeedCallWithErrAndResult(map[domain.Sku][]domain.WarehouseStock{
+					testSku: {{WarehouseID: 1, Sku: testSku, TotalCount: 7, Reserved: 0}},
+				}, nil),
+			},
+			expectedErr: domain.ErrStockNotFound,
+		},
+		{
+			name: "fail: UpdateStocks returns error",
+			mocks: mocks{
+				getStocksBySkuForUpdate: testhelpers.NewNeedCallWithErrAndResult(map[domain.Sku][]domain.WarehouseStock{
+					testSku: {
+						{WarehouseID: 1, Sku: testSku, TotalCount: 7, Reserved: 0},
+						{WarehouseID: testWarehouseID, Sku: testSku, TotalCount: 10, Reserved: 3},
+					},
+				}, nil),
+				updateStocks: testhelpers.NewNeedCallWithErr(testhelpers.ErrForTest),
+			},
+			expected:    domain.WarehouseStock{WarehouseID: testWarehouseID, Sku: testSku, TotalCount: 15, Reserved: 3},
+			expectedErr: testhelpers.ErrForTest,
+		},
+		{
+			name: "fail: CreateStockMovements returns error",
+			mocks: mocks{
+				getStocksBySkuForUpdate: testhelpers.NewNeedCallWithErrAndResult(map[domain.Sku][]domain.WarehouseStock{
+					testSku: {
+						{WarehouseID: 1, Sku: testSku, TotalCount: 7, Reserved: 0},
+						{WarehouseID: testWarehouseID, Sku: testSku, TotalCount: 10, Reserved: 3},
+					},
+				}, nil),
+				updateStocks:         testhelpers.NewNeedCallWithErr(nil),
+				createStockMovements: testhelpers.NewNeedCallWithErr(testhelpers.ErrForTest),
+			},
+			expected:    domain.WarehouseStock{WarehouseID: testWarehouseID, Sku: testSku, TotalCount: 15, Reserved: 3},
+			expectedErr: testhelpers.ErrForTest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			f := setUp(t)
+
+			if tc.mocks.getStocksBySkuForUpdate.NeedCall {
+				f.repository.GetStocksBySkuForUpdateMock.
+					Expect(minimock.AnyContext, []domain.Item{{Sku: testSku}}).
+					Return(tc.mocks.getStocksBySkuForUpdate.Result, tc.mocks.getStocksBySkuForUpdate.Err)
+			}
+
+			if tc.mocks.updateStocks.NeedCall {
+				f.repository.UpdateStocksMock.
+					Expect(minimock.AnyContext, []domain.WarehouseStock{tc.expected}).
+					Return(tc.mocks.updateStocks.Err)
+			}
+
+			if tc.mocks.createStockMovements.NeedCall {
+				f.repository.CreateStockMovementsMock.
+					Expect(minimock.AnyContext, []domain.StockMovement{{
+						WarehouseID: testWarehouseID,
+						Sku:         testSku,
+						Kind:        domain.StockMovementRestock,
+						TotalDelta:  5,
+						TotalCount:  tc.expected.TotalCount,
+						Reserved:    tc.expected.Reserved,
+					}}).
+					Return(tc.mocks.createStockMovements.Err)
+			}
+
+			stock, err := f.executor.Restock(ctx, testWarehouseID, testSku, 5)
+
+			if tc.expectedErr != nil {
+				f.Error(err)
+				f.ErrorContains(err, tc.expectedErr.Error())
+			} else {
+				f.NoError(err)
+				f.Equal(tc.expected, stock)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package stockadmin
+
+import (
+	"context"
+	"fmt"
+	"route256/loms/internal/domain"
+	txmanager "route256/loms/internal/infra/tx_manager"
+)
+
+//go:generate rm -rf mock
+//go:generate mkdir -p mock
+//go:generate minimock -i * -o ./mock -s "_mock.go" -g
+type stockRepository interface {
+	GetStocksBySkuForUpdate(ctx context.Context, items []domain.Item) (map[domain.Sku][]domain.WarehouseStock, error)
+	UpdateStocks(ctx context.Context, stocks []domain.WarehouseStock) error
+	CreateStock(ctx context.Context, stock domain.WarehouseStock) error
+	CreateStockMovements(ctx context.Context, movements []domain.StockMovement) error
+	ListStockMovements(ctx context.Context, filter domain.StockHistoryFilter) ([]domain.StockMovement, error)
+}
+
+type txManager interface {
+	ReadCommitted(ctx context.Context, f txmanager.Handler) error
+}
+
+type Service struct {
+	stockRepository stockRepository
+	txManagerMaster txManager
+}
+
+func New(stockRepository stockRepository, txManagerMaster txManager) *Service {
+	return &Service{
+		stockRepository: stockRepository,
+		txManagerMaster: txManagerMaster,
+	}
+}
+
+func (s *Service) getStockForUpdate(
+	ctx context.Context,
+	warehouseID int64,
+	sku domain.Sku) (domain.WarehouseStock, error) {
+	stocks, err := s.stockRepository.GetStocksBySkuForUpdate(ctx, []domain.Item{{Sku: sku}})
+	if err != nil {
+		return domain.WarehouseStock{}, fmt.Errorf("stockRepository.GetStocksBySkuForUpdate: %w", err)
+	}
+
+	for _, stock := range stocks[sku] {
+		if stock.WarehouseID == warehouseID {
+			return stock, nil
+		}
+	}
+
+	return domain.WarehouseStock{}, fmt.Errorf("%w: sku %v warehouse %d", domain.ErrStockNotFound, sku, warehouseID)
+}
+
+func (s *Service) updateStock(
+	ctx context.Context,
+	kind domain.StockMovementKind,
+	reason string,
+	before domain.WarehouseStock,
+	after domain.WarehouseStock) error {
+	if err := s.stockRepository.UpdateStocks(ctx, []domain.WarehouseStock{after}); err != nil {
+		return fmt.Errorf("stockRepository.UpdateStocks: %w", err)
+	}
+
+	if err := s.stockRepository.CreateStockMovements(ctx, []domain.StockMovement{{
+		WarehouseID:   after.WarehouseID,
+		Sku:           after.Sku,
+		Kind:          kind,
+		Reason:        reason,
+		TotalDelta:    after.TotalCount - before.TotalCount,
+		ReservedDelta: after.Reserved - before.Reserved,
+		TotalCount:    after.TotalCount,
+		Reserved:      after.Reserved,
+	}}); err != nil {
+		return fmt.Errorf("stockRepository.CreateStockMovements: %w", err)
+	}
+
+	return nil
+}
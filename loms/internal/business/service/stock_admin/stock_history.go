@@ -0,0 +1,38 @@
+package stockadmin
+
+import (
+	"context"
+	"fmt"
+	"route256/loms/internal/domain"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+const defaultStockHistoryLimit = 20
+
+func (s *Service) StockHistory(
+	ctx context.Context,
+	filter domain.StockHistoryFilter) ([]domain.StockMovement, int64, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "stockAdminService.StockHistory")
+	defer span.Finish()
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultStockHistoryLimit
+	}
+
+	filter.Limit = limit + 1
+
+	movements, err := s.stockRepository.ListStockMovements(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("stockRepository.ListStockMovements: %w", err)
+	}
+
+	var nextCursor int64
+	if len(movements) > int(limit) {
+		movements = movements[:limit]
+		nextCursor = movements[limit-1].ID
+	}
+
+	return movements, nextCursor, nil
+}
@@ -0,0 +1,88 @@
+package stockadmin_test
+
+import (
+	"context"
+	"route256/loms/internal/domain"
+	testhelpers "route256/loms/internal/tool"
+	"testing"
+
+	"github.com/gojuno/minimock/v3"
+)
+
+func TestStockHistory(t *testing.T) {
+	t.Parallel()
+
+	testSku := domain.Sku(1001)
+	testMovements := []domain.StockMovement{
+		{ID: 5, WarehouseID: 1, Sku: testSku, Kind: domain.StockMovementReserve, OrderID: 7, ReservedDelta: 1},
+		{ID: 4, WarehouseID: 1, Sku: testSku, Kind: domain.StockMovementRestock, TotalDelta: 10},
+		{ID: 2, WarehouseID: 1, Sku: testSku, Kind: domain.StockMovementCreate, TotalDelta: 5},
+	}
+
+	testCases := []struct {
+		name               string
+		filter             domain.StockHistoryFilter
+		expectedRepoFilter domain.StockHistoryFilter
+		repoResult         []domain.StockMovement
+		repoErr            error
+		expected           []domain.StockMovement
+		expectedCursor     int64
+		expectedErr        error
+	}{
+		{
+			name:               "success: last page",
+			filter:             domain.StockHistoryFilter{Sku: testSku, Limit: 5},
+			expectedRepoFilter: domain.StockHistoryFilter{Sku: testSku, Limit: 6},
+			repoResult:         testMovements,
+			expected:           testMovements,
+			expectedCursor:     0,
+		},
+		{
+			name:               "success: has next page",
+			filter:             domain.StockHistoryFilter{Sku: testSku, WarehouseID: 1, Cursor: 10, Limit: 2},
+			expectedRepoFilter: domain.StockHistoryFilter{Sku: testSku, WarehouseID: 1, Cursor: 10, Limit: 3},
+			repoResult:         testMovements,
+			expected:           testMovements[:2],
+			expectedCursor:     4,
+		},
+		{
+			name:               "success: default limit",
+			filter:             domain.StockHistoryFilter{Sku: testSku},
+			expectedRepoFilter: domain.StockHistoryFilter{Sku: testSku, Limit: 21},
+			repoResult:         nil,
+			expected:           nil,
+			expectedCursor:     0,
+		},
+		{
+			name:               "fail: ListStockMovements returns error",
+			filter:             domain.StockHistoryFilter{Sku: testSku, Limit: 5},
+			expectedRepoFilter: domain.StockHistoryFilter{Sku: testSku, Limit: 6},
+			repoErr:            testhelpers.ErrForTest,
+			expectedErr:        testhelpers.ErrForTest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			f := setUp(t)
+
+			f.repository.ListStockMovementsMock.
+				Expect(minimock.AnyContext, tc.expectedRepoFilter).
+				Return(tc.repoResult, tc.repoErr)
+
+			movements, nextCursor, err := f.executor.StockHistory(ctx, tc.filter)
+
+			if tc.expectedErr != nil {
+				f.Error(err)
+				f.ErrorIs(err, tc.expectedErr)
+			} else {
+				f.NoError(err)
+				f.Equal(tc.expected, movements)
+				f.Equal(tc.expectedCursor, nextCursor)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package stockadmin_test
+
+import (
+	"context"
+	stockadminservice "route256/loms/internal/business/service/stock_admin"
+	"route256/loms/internal/business/service/stock_admin/mock"
+	logger "route256/loms/internal/infra/logger"
+	txmanager "route256/loms/internal/infra/tx_manager"
+	"testing"
+
+	"github.com/gojuno/minimock/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+type fixture struct {
+	*assert.Assertions
+
+	repository *mock.StockRepositoryMock
+	txManager  *mock.TxManagerMock
+
+	executor *stockadminservice.Service
+}
+
+func setUp(t *testing.T) *fixture {
+	ctrl := minimock.NewController(t)
+
+	err := logger.Init(zapcore.DebugLevel)
+	require.NoError(t, err)
+
+	repository := mock.NewStockRepositoryMock(ctrl)
+	txManager := mock.NewTxManagerMock(ctrl)
+
+	txManager.ReadCommittedMock.Optional().Set(func(ctx context.Context, fn txmanager.Handler) error {
+		return fn(ctx)
+	})
+
+	executor := stockadminservice.New(repository, txManager)
+
+	return &fixture{
+		Assertions: assert.New(t),
+
+		repository: repository,
+		txManager:  txManager,
+
+		executor: executor,
+	}
+}
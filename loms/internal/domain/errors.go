@@ -9,5 +9,18 @@ var (
 	ErrInvalidReserveOperation = errors.New("значение превышает количество остатков")
 	ErrCancelOrder             = errors.New("невозможность отменить неудавшийся заказ, а также оплаченный")
 	ErrPayStatusOrder          = errors.New("оплата заказа в невалидном статусе невозможна")
+	ErrCancelItemsStatus       = errors.New("частичная отмена возможна только для заказа, ожидающего оплаты")
+	ErrReturnItemsStatus       = errors.New("возврат товаров возможен только для оплаченного заказа")
+	ErrRefundStatusOrder       = errors.New("возврат средств возможен только для оплаченного заказа")
+	ErrOrderTransition         = errors.New("недопустимый переход статуса заказа")
+	ErrInvalidOrderItems       = errors.New("товары отсутствуют в заказе или их количество превышает количество в заказе")
+	ErrOrderCurrency           = errors.New("все товары заказа должны быть в одной валюте")
+	ErrStockAlreadyExists      = errors.New("сток по данному SKU уже существует")
+	ErrInvalidStockCount       = errors.New("количество резерва не может превышать общее количество товара")
+	ErrPaymentDeclined         = errors.New("платеж отклонен платежным провайдером")
+	ErrPaymentPending          = errors.New("платеж ожидает подтверждения платежного провайдера")
+	ErrPaymentUnavailable      = errors.New("платежный провайдер недоступен")
+	ErrPaymentNotFound         = errors.New("нет информации о платеже по данному заказу")
+	ErrPaymentAmountMismatch   = errors.New("сумма платежа не совпадает с суммой заказа")
 	ErrInternalServerError     = errors.New("проблемы из-за неисправностей в системе")
 )
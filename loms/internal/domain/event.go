@@ -9,17 +9,36 @@ type OrderEvent struct {
 }
 
 type Event struct {
-	ID      int64
-	Topic   string
-	Key     string
-	Payload []byte
-	Status  EventStatus
+	ID            int64
+	EventID       string
+	Topic         string
+	Key           string
+	Payload       []byte
+	Status        EventStatus
+	Attempts      int32
+	CreatedAt     time.Time
+	NextAttemptAt time.Time
+	TraceContext  map[string]string
+	RequestID     string
 }
 
 type EventStatus string
 
 const (
-	EventStatusNew   EventStatus = "new"
-	EventStatusSent  EventStatus = "sent"
-	EventStatusError EventStatus = "error"
+	EventStatusNew     EventStatus = "new"
+	EventStatusSent    EventStatus = "sent"
+	EventStatusPending EventStatus = "pending"
+	EventStatusError   EventStatus = "error"
+	EventStatusDead    EventStatus = "dead"
 )
+
+type EventRetryPolicy struct {
+	MaxAttempts int32
+	BackoffBase time.Duration
+	BackoffMax  time.Duration
+}
+
+type DeadEventsFilter struct {
+	Cursor int64
+	Limit  int32
+}
@@ -1,16 +1,46 @@
 package domain
 
+import "time"
+
 type Sku int64
 
+const DefaultCurrency = "RUB"
+
 type Item struct {
-	Sku   Sku
-	Count int64
+	Sku      Sku
+	Count    int64
+	Price    int64
+	Currency string
+}
+
+// MergeItems sums counts of items with the same sku keeping the first occurrence order.
+func MergeItems(items []Item) []Item {
+	result := make([]Item, 0, len(items))
+	index := make(map[Sku]int, len(items))
+
+	for _, item := range items {
+		if idx, ok := index[item.Sku]; ok {
+			result[idx].Count += item.Count
+			continue
+		}
+
+		index[item.Sku] = len(result)
+		result = append(result, item)
+	}
+
+	return result
 }
 
 type Order struct {
-	UserID int64
-	Status OrderStatus
-	Items  []Item
+	ID          int64
+	UserID      int64
+	Status      OrderStatus
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Items       []Item
+	Allocations []Allocation
+	TotalPrice  int64
+	Currency    string
 }
 
 type OrderStatus string
@@ -21,4 +51,26 @@ const (
 	OrderStatusFailed          OrderStatus = "failed"
 	OrderStatusPayed           OrderStatus = "payed"
 	OrderStatusCancelled       OrderStatus = "cancelled"
+	OrderStatusRefundRequested OrderStatus = "refund requested"
+	OrderStatusRefunded        OrderStatus = "refunded"
 )
+
+type OrderStatusChange struct {
+	Status    OrderStatus
+	ChangedAt time.Time
+}
+
+type OrderStatusUpdate struct {
+	OrderID   int64
+	Status    OrderStatus
+	ChangedAt time.Time
+}
+
+type OrderListFilter struct {
+	UserID      int64
+	Statuses    []OrderStatus
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	Cursor      int64
+	Limit       int32
+}
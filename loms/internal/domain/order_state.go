@@ -0,0 +1,217 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+type OrderAction string
+
+const (
+	OrderActionReserve       OrderAction = "reserve"
+	OrderActionFail          OrderAction = "fail"
+	OrderActionPay           OrderAction = "pay"
+	OrderActionCancel        OrderAction = "cancel"
+	OrderActionCancelItems   OrderAction = "cancel items"
+	OrderActionReturnItems   OrderAction = "return items"
+	OrderActionRequestRefund OrderAction = "request refund"
+	OrderActionRefund        OrderAction = "refund"
+)
+
+// OrderTransition describes an action allowed in the From statuses. Done lists
+// statuses in which the action has already taken effect, firing it there is a no-op.
+type OrderTransition struct {
+	Action OrderAction
+	From   []OrderStatus
+	To     OrderStatus
+	Done   []OrderStatus
+	Err    error
+}
+
+var orderStatuses = []OrderStatus{
+	OrderStatusNew,
+	OrderStatusAwaitingPayment,
+	OrderStatusFailed,
+	OrderStatusPayed,
+	OrderStatusCancelled,
+	OrderStatusRefundRequested,
+	OrderStatusRefunded,
+}
+
+var orderTransitions = []OrderTransition{
+	{
+		Action: OrderActionReserve,
+		From:   []OrderStatus{OrderStatusNew},
+		To:     OrderStatusAwaitingPayment,
+		Err:    ErrOrderTransition,
+	},
+	{
+		Action: OrderActionFail,
+		From:   []OrderStatus{OrderStatusNew},
+		To:     OrderStatusFailed,
+		Done:   []OrderStatus{OrderStatusFailed},
+		Err:    ErrOrderTransition,
+	},
+	{
+		Action: OrderActionPay,
+		From:   []OrderStatus{OrderStatusAwaitingPayment},
+		To:     OrderStatusPayed,
+		Done:   []OrderStatus{OrderStatusPayed},
+		Err:    ErrPayStatusOrder,
+	},
+	{
+		Action: OrderActionCancel,
+		From:   []OrderStatus{OrderStatusNew, OrderStatusAwaitingPayment},
+		To:     OrderStatusCancelled,
+		Done:   []OrderStatus{OrderStatusCancelled},
+		Err:    ErrCancelOrder,
+	},
+	{
+		Action: OrderActionCancelItems,
+		From:   []OrderStatus{OrderStatusAwaitingPayment},
+		To:     OrderStatusAwaitingPayment,
+		Err:    ErrCancelItemsStatus,
+	},
+	{
+		Action: OrderActionReturnItems,
+		From:   []OrderStatus{OrderStatusPayed},
+		To:     OrderStatusPayed,
+		Err:    ErrReturnItemsStatus,
+	},
+	{
+		Action: OrderActionRequestRefund,
+		From:   []OrderStatus{OrderStatusPayed},
+		To:     OrderStatusRefundRequested,
+		Done:   []OrderStatus{OrderStatusRefundRequested, OrderStatusRefunded},
+		Err:    ErrRefundStatusOrder,
+	},
+	{
+		Action: OrderActionRefund,
+		From:   []OrderStatus{OrderStatusRefundRequested},
+		To:     OrderStatusRefunded,
+		Done:   []OrderStatus{OrderStatusRefunded},
+		Err:    ErrRefundStatusOrder,
+	},
+}
+
+type OrderTransitionError struct {
+	Status OrderStatus
+	Action OrderAction
+	Err    error
+}
+
+func (e *OrderTransitionError) Error() string {
+	return fmt.Sprintf("%v: action %q is not allowed in status %q", e.Err, e.Action, e.Status)
+}
+
+func (e *OrderTransitionError) Unwrap() []error {
+	return []error{ErrOrderTransition, e.Err}
+}
+
+// OrderHook is run by Fire after the transition is validated. Guards are hooks
+// that only check the order, side effects go after them.
+type OrderHook func(ctx context.Context, orderID int64, order Order, to OrderStatus) error
+
+type OrderStateMachine struct {
+	transitions  map[OrderAction]OrderTransition
+	onTransition OrderHook
+}
+
+func NewOrderStateMachine(onTransition OrderHook) *OrderStateMachine {
+	transitions := make(map[OrderAction]OrderTransition, len(orderTransitions))
+	for _, transition := range orderTransitions {
+		transitions[transition.Action] = transition
+	}
+
+	return &OrderStateMachine{
+		transitions:  transitions,
+		onTransition: onTransition,
+	}
+}
+
+func (m *OrderStateMachine) Done(status OrderStatus, action OrderAction) bool {
+	return slices.Contains(m.transitions[action].Done, status)
+}
+
+func (m *OrderStateMachine) Validate(status OrderStatus, action OrderAction) error {
+	transition, ok := m.transitions[action]
+	if !ok {
+		return &OrderTransitionError{Status: status, Action: action, Err: ErrOrderTransition}
+	}
+
+	if !slices.Contains(transition.From, status) {
+		return &OrderTransitionError{Status: status, Action: action, Err: transition.Err}
+	}
+
+	return nil
+}
+
+func (m *OrderStateMachine) Fire(
+	ctx context.Context,
+	orderID int64,
+	order Order,
+	action OrderAction,
+	hooks ...OrderHook) error {
+	if m.Done(order.Status, action) {
+		return nil
+	}
+
+	if err := m.Validate(order.Status, action); err != nil {
+		return err
+	}
+
+	to := m.transitions[action].To
+
+	for _, hook := range hooks {
+		if err := hook(ctx, orderID, order, to); err != nil {
+			return err
+		}
+	}
+
+	if to == order.Status || m.onTransition == nil {
+		return nil
+	}
+
+	return m.onTransition(ctx, orderID, order, to)
+}
+
+func (m *OrderStateMachine) DOT() string {
+	var b strings.Builder
+
+	b.WriteString("digraph order_states {\n")
+	b.WriteString("\trankdir=LR;\n")
+	b.WriteString("\tstart [shape=point];\n")
+
+	for _, status := range orderStatuses {
+		shape := "box"
+		if m.terminal(status) {
+			shape = "doublecircle"
+		}
+
+		fmt.Fprintf(&b, "\t%q [shape=%s];\n", status, shape)
+	}
+
+	fmt.Fprintf(&b, "\tstart -> %q;\n", OrderStatusNew)
+
+	for _, transition := range orderTransitions {
+		for _, from := range transition.From {
+			fmt.Fprintf(&b, "\t%q -> %q [label=%q];\n", from, transition.To, transition.Action)
+		}
+	}
+
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+func (m *OrderStateMachine) terminal(status OrderStatus) bool {
+	for _, transition := range m.transitions {
+		if transition.To != status && slices.Contains(transition.From, status) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,240 @@
+package domain_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"route256/loms/internal/domain"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testOrderStatuses = []domain.OrderStatus{
+	domain.OrderStatusNew,
+	domain.OrderStatusAwaitingPayment,
+	domain.OrderStatusFailed,
+	domain.OrderStatusPayed,
+	domain.OrderStatusCancelled,
+	domain.OrderStatusRefundRequested,
+	domain.OrderStatusRefunded,
+}
+
+func TestOrderStateMachineTransitions(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		action domain.OrderAction
+		from   []domain.OrderStatus
+		to     domain.OrderStatus
+		done   []domain.OrderStatus
+		err    error
+	}{
+		{
+			action: domain.OrderActionReserve,
+			from:   []domain.OrderStatus{domain.OrderStatusNew},
+			to:     domain.OrderStatusAwaitingPayment,
+			err:    domain.ErrOrderTransition,
+		},
+		{
+			action: domain.OrderActionFail,
+			from:   []domain.OrderStatus{domain.OrderStatusNew},
+			to:     domain.OrderStatusFailed,
+			done:   []domain.OrderStatus{domain.OrderStatusFailed},
+			err:    domain.ErrOrderTransition,
+		},
+		{
+			action: domain.OrderActionPay,
+			from:   []domain.OrderStatus{domain.OrderStatusAwaitingPayment},
+			to:     domain.OrderStatusPayed,
+			done:   []domain.OrderStatus{domain.OrderStatusPayed},
+			err:    domain.ErrPayStatusOrder,
+		},
+		{
+			action: domain.OrderActionCancel,
+			from:   []domain.OrderStatus{domain.OrderStatusNew, domain.OrderStatusAwaitingPayment},
+			to:     domain.OrderStatusCancelled,
+			done:   []domain.OrderStatus{domain.OrderStatusCancelled},
+			err:    domain.ErrCancelOrder,
+		},
+		{
+			action: domain.OrderActionCancelItems,
+			from:   []domain.OrderStatus{domain.OrderStatusAwaitingPayment},
+			to:     domain.OrderStatusAwaitingPayment,
+			err:    domain.ErrCancelItemsStatus,
+		},
+		{
+			action: domain.OrderActionReturnItems,
+			from:   []domain.OrderStatus{domain.OrderStatusPayed},
+			to:     domain.OrderStatusPayed,
+			err:    domain.ErrReturnItemsStatus,
+		},
+		{
+			action: domain.OrderActionRequestRefund,
+			from:   []domain.OrderStatus{domain.OrderStatusPayed},
+			to:     domain.OrderStatusRefundRequested,
+			done:   []domain.OrderStatus{domain.OrderStatusRefundRequested, domain.OrderStatusRefunded},
+			err:    domain.ErrRefundStatusOrder,
+		},
+		{
+			action: domain.OrderActionRefund,
+			from:   []domain.OrderStatus{domain.OrderStatusRefundRequested},
+			to:     domain.OrderStatusRefunded,
+			done:   []domain.OrderStatus{domain.OrderStatusRefunded},
+			err:    domain.ErrRefundStatusOrder,
+		},
+	}
+
+	for _, tc := range testCases {
+		for _, status := range testOrderStatuses {
+			t.Run(string(tc.action)+" from "+string(status), func(t *testing.T) {
+				t.Parallel()
+
+				var changed []domain.OrderStatus
+				machine := domain.NewOrderStateMachine(
+					func(_ context.Context, _ int64, _ domain.Order, to domain.OrderStatus) error {
+						changed = append(changed, to)
+						return nil
+					})
+
+				err := machine.Fire(context.Background(), 1, domain.Order{Status: status}, tc.action)
+
+				switch {
+				case slices.Contains(tc.done, status):
+					require.True(t, machine.Done(status, tc.action))
+					require.NoError(t, err)
+					require.Empty(t, changed)
+				case slices.Contains(tc.from, status):
+					require.False(t, machine.Done(status, tc.action))
+					require.NoError(t, machine.Validate(status, tc.action))
+					require.NoError(t, err)
+
+					if tc.to == status {
+						require.Empty(t, changed)
+					} else {
+						require.Equal(t, []domain.OrderStatus{tc.to}, changed)
+					}
+				default:
+					require.ErrorIs(t, err, domain.ErrOrderTransition)
+					require.ErrorIs(t, err, tc.err)
+					require.ErrorIs(t, machine.Validate(status, tc.action), tc.err)
+					require.Empty(t, changed)
+
+					var transitionErr *domain.OrderTransitionError
+					require.ErrorAs(t, err, &transitionErr)
+					require.Equal(t, status, transitionErr.Status)
+					require.Equal(t, tc.action, transitionErr.Action)
+				}
+			})
+		}
+	}
+}
+
+func TestOrderStateMachineUnknownAction(t *testing.T) {
+	t.Parallel()
+
+	machine := domain.NewOrderStateMachine(nil)
+
+	err := machine.Fire(context.Background(), 1, domain.Order{Status: domain.OrderStatusNew}, "unknown")
+
+	require.ErrorIs(t, err, domain.ErrOrderTransition)
+}
+
+func TestOrderStateMachineHooks(t *testing.T) {
+	t.Parallel()
+
+	errHook := errors.New("hook failed")
+
+	testCases := []struct {
+		name            string
+		status          domain.OrderStatus
+		hookErrs        []error
+		expectedCalls   int
+		expectedChanged bool
+		expectedErr     error
+	}{
+		{
+			name:            "success: hooks run in order before status change",
+			status:          domain.OrderStatusAwaitingPayment,
+			hookErrs:        []error{nil, nil},
+			expectedCalls:   2,
+			expectedChanged: true,
+		},
+		{
+			name:          "fail: guard error stops transition",
+			status:        domain.OrderStatusAwaitingPayment,
+			hookErrs:      []error{errHook, nil},
+			expectedCalls: 1,
+			expectedErr:   errHook,
+		},
+		{
+			name:          "fail: side effect error stops transition",
+			status:        domain.OrderStatusAwaitingPayment,
+			hookErrs:      []error{nil, errHook},
+			expectedCalls: 2,
+			expectedErr:   errHook,
+		},
+		{
+			name:     "success: done status runs no hooks",
+			status:   domain.OrderStatusPayed,
+			hookErrs: []error{nil, nil},
+		},
+		{
+			name:        "fail: illegal transition runs no hooks",
+			status:      domain.OrderStatusCancelled,
+			hookErrs:    []error{nil, nil},
+			expectedErr: domain.ErrPayStatusOrder,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				calls   int
+				changed bool
+			)
+
+			machine := domain.NewOrderStateMachine(
+				func(_ context.Context, orderID int64, _ domain.Order, to domain.OrderStatus) error {
+					require.Equal(t, int64(1), orderID)
+					require.Equal(t, domain.OrderStatusPayed, to)
+					changed = true
+					return nil
+				})
+
+			hooks := make([]domain.OrderHook, len(tc.hookErrs))
+			for idx, hookErr := range tc.hookErrs {
+				hooks[idx] = func(_ context.Context, _ int64, _ domain.Order, to domain.OrderStatus) error {
+					require.Equal(t, idx, calls)
+					require.Equal(t, domain.OrderStatusPayed, to)
+					calls++
+					return hookErr
+				}
+			}
+
+			err := machine.Fire(context.Background(), 1, domain.Order{Status: tc.status}, domain.OrderActionPay, hooks...)
+
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			require.Equal(t, tc.expectedCalls, calls)
+			require.Equal(t, tc.expectedChanged, changed)
+		})
+	}
+}
+
+func TestOrderStateMachineDOT(t *testing.T) {
+	t.Parallel()
+
+	expected, err := os.ReadFile("../../../docs/loms_order_states.dot")
+	require.NoError(t, err)
+
+	require.Equal(t, string(expected), domain.NewOrderStateMachine(nil).DOT(),
+		"docs/loms_order_states.dot is stale, run make order-states in loms")
+}
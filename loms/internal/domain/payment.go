@@ -0,0 +1,42 @@
+package domain
+
+import "time"
+
+type PaymentStatus string
+
+const (
+	PaymentStatusPending    PaymentStatus = "pending"
+	PaymentStatusAuthorized PaymentStatus = "authorized"
+	PaymentStatusCaptured   PaymentStatus = "captured"
+	PaymentStatusDeclined   PaymentStatus = "declined"
+	PaymentStatusRefunded   PaymentStatus = "refunded"
+)
+
+type PaymentIntent struct {
+	OrderID           int64
+	Provider          string
+	ProviderPaymentID string
+	Status            PaymentStatus
+	Attempts          int32
+	DeclineReason     string
+	Amount            int64
+	Currency          string
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+type PaymentRequest struct {
+	OrderID        int64
+	UserID         int64
+	IdempotencyKey string
+	Amount         int64
+	Currency       string
+}
+
+type PaymentResult struct {
+	ProviderPaymentID string
+	Status            PaymentStatus
+	Reason            string
+	Amount            int64
+	Currency          string
+}
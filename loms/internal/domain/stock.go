@@ -1,6 +1,57 @@
 package domain
 
+import "time"
+
+const DefaultWarehouseID int64 = 1
+
 type Stock struct {
 	TotalCount int64
 	Reserved   int64
 }
+
+type WarehouseStock struct {
+	WarehouseID int64
+	Sku         Sku
+	TotalCount  int64
+	Reserved    int64
+}
+
+type Allocation struct {
+	Sku         Sku
+	WarehouseID int64
+	Count       int64
+}
+
+type StockMovementKind string
+
+const (
+	StockMovementReserve       StockMovementKind = "reserve"
+	StockMovementReserveRemove StockMovementKind = "reserve_remove"
+	StockMovementReserveCancel StockMovementKind = "reserve_cancel"
+	StockMovementReturn        StockMovementKind = "return"
+	StockMovementRestock       StockMovementKind = "restock"
+	StockMovementAdjust        StockMovementKind = "adjust"
+	StockMovementCreate        StockMovementKind = "create"
+	StockMovementCompensate    StockMovementKind = "compensate"
+)
+
+type StockMovement struct {
+	ID            int64
+	WarehouseID   int64
+	Sku           Sku
+	Kind          StockMovementKind
+	OrderID       int64
+	Reason        string
+	TotalDelta    int64
+	ReservedDelta int64
+	TotalCount    int64
+	Reserved      int64
+	CreatedAt     time.Time
+}
+
+type StockHistoryFilter struct {
+	Sku         Sku
+	WarehouseID int64
+	Cursor      int64
+	Limit       int32
+}
@@ -11,19 +11,45 @@ type Config struct {
 	Service  Service      `yaml:"service"`
 	MasterDB DBConfig     `yaml:"db_master"`
 	ReplicDB DBConfig     `yaml:"db_replica"`
+	Replicas []DBConfig   `yaml:"db_replicas"`
+	Shards   []DBShard    `yaml:"db_shards"`
 	Kafka    KafkaConfig  `yaml:"kafka"`
 	Jaeger   JaegerConfig `yaml:"jaeger"`
+
+	ReadConsistency ReadConsistencyConfig `yaml:"read_consistency"`
+	Health          HealthConfig          `yaml:"health"`
+	Watch           WatchConfig           `yaml:"watch"`
+	Payment         PaymentConfig         `yaml:"payment"`
 }
 
 type Service struct {
-	Host           string `yaml:"host"`
-	GRPCPort       int    `yaml:"grpc_port"`
-	HTTPPort       int    `yaml:"http_port"`
-	SwaggerPort    int    `yaml:"swagger_port"`
-	Timeout        int    `yaml:"timeout"`
-	HandlePeriod   int    `yaml:"handle_period"`
-	LimitOutboxMsg int32  `yaml:"limit_outbox_msg"`
-	LogLevel       string `yaml:"log_level"`
+	Host               string  `yaml:"host"`
+	GRPCPort           int     `yaml:"grpc_port"`
+	HTTPPort           int     `yaml:"http_port"`
+	SwaggerPort        int     `yaml:"swagger_port"`
+	Timeout            int     `yaml:"timeout"`
+	HandlePeriod       int     `yaml:"handle_period"`
+	LimitOutboxMsg     int32   `yaml:"limit_outbox_msg"`
+	OutboxMaxAttempts  int32   `yaml:"outbox_max_attempts"`
+	OutboxBackoff      int     `yaml:"outbox_backoff"`
+	OutboxBackoffMax   int     `yaml:"outbox_backoff_max"`
+	OutboxRetention    int     `yaml:"outbox_retention_days"`
+	OutboxArchive      bool    `yaml:"outbox_archive"`
+	OutboxCleanPeriod  int     `yaml:"outbox_clean_period"`
+	LimitOutboxClean   int32   `yaml:"limit_outbox_clean"`
+	LeaderElection     bool    `yaml:"leader_election"`
+	LeaderCheckPeriod  int     `yaml:"leader_check_period"`
+	TxMaxAttempts      int     `yaml:"tx_max_attempts"`
+	TxBackoffMs        int     `yaml:"tx_backoff_ms"`
+	TxBackoffMaxMs     int     `yaml:"tx_backoff_max_ms"`
+	PaymentDeadline    int     `yaml:"payment_deadline"`
+	UnpaidCancelPeriod int     `yaml:"unpaid_cancel_period"`
+	LimitUnpaidOrders  int32   `yaml:"limit_unpaid_orders"`
+	AdminToken         string  `yaml:"admin_token"`
+	AllocationStrategy string  `yaml:"allocation_strategy"`
+	WarehousePriority  []int64 `yaml:"warehouse_priority"`
+	LogLevel           string  `yaml:"log_level"`
+	Storage            string  `yaml:"storage"`
 }
 
 type DBConfig struct {
@@ -34,12 +60,46 @@ type DBConfig struct {
 	DBname   string `yaml:"db_name"`
 }
 
+type DBShard struct {
+	Master   DBConfig   `yaml:"master"`
+	Replicas []DBConfig `yaml:"replicas"`
+}
+
 type KafkaConfig struct {
-	Host          string `yaml:"host"`
-	Port          int    `yaml:"port"`
-	OrderTopic    string `yaml:"order_topic"`
-	Brokers       string `yaml:"brokers"`
-	RetryCountMsg int    `yaml:"retry_count_msg"`
+	Host            string `yaml:"host"`
+	Port            int    `yaml:"port"`
+	OrderTopic      string `yaml:"order_topic"`
+	Brokers         string `yaml:"brokers"`
+	RetryCountMsg   int    `yaml:"retry_count_msg"`
+	Transactional   bool   `yaml:"transactional"`
+	TransactionalID string `yaml:"transactional_id"`
+}
+
+type HealthConfig struct {
+	CheckPeriodMs          int `yaml:"check_period_ms"`
+	CheckTimeoutMs         int `yaml:"check_timeout_ms"`
+	OutboxBacklogMaxAgeSec int `yaml:"outbox_backlog_max_age_sec"`
+}
+
+type WatchConfig struct {
+	HeartbeatIntervalMs int    `yaml:"heartbeat_interval_ms"`
+	BufferSize          int    `yaml:"buffer_size"`
+	SlowConsumerPolicy  string `yaml:"slow_consumer_policy"`
+}
+
+type PaymentConfig struct {
+	Provider      string `yaml:"provider"`
+	FakeMode      string `yaml:"fake_mode"`
+	TimeoutMs     int    `yaml:"timeout_ms"`
+	WebhookSecret string `yaml:"webhook_secret"`
+}
+
+type ReadConsistencyConfig struct {
+	ReplicaWaitMs        int               `yaml:"replica_wait_ms"`
+	ReplicaMaxLagMs      int               `yaml:"replica_max_lag_ms"`
+	ReplicaCheckPeriodMs int               `yaml:"replica_check_period_ms"`
+	WriteMethods         []string          `yaml:"write_methods"`
+	ReadMethods          map[string]string `yaml:"read_methods"`
 }
 
 type JaegerConfig struct {
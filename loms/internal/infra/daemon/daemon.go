@@ -7,21 +7,47 @@ import (
 	"time"
 )
 
-type eventCronProcessor interface {
+type cronProcessor interface {
 	Do(ctx context.Context) error
 }
 
+type leader interface {
+	IsLeader() bool
+}
+
+type Option func(d *Daemon)
+
+func WithWakeup(wakeup <-chan struct{}) Option {
+	return func(d *Daemon) {
+		d.wakeup = wakeup
+	}
+}
+
+func WithLeader(leader leader) Option {
+	return func(d *Daemon) {
+		d.leader = leader
+	}
+}
+
 type Daemon struct {
-	eventCronProcessor eventCronProcessor
-	interval           time.Duration
-	startOnce          sync.Once
+	cronProcessor cronProcessor
+	interval      time.Duration
+	wakeup        <-chan struct{}
+	leader        leader
+	startOnce     sync.Once
 }
 
-func New(eventCronProcessor eventCronProcessor, interval time.Duration) *Daemon {
-	return &Daemon{
-		eventCronProcessor: eventCronProcessor,
-		interval:           interval,
+func New(cronProcessor cronProcessor, interval time.Duration, opts ...Option) *Daemon {
+	d := &Daemon{
+		cronProcessor: cronProcessor,
+		interval:      interval,
 	}
+
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	return d
 }
 
 func (d *Daemon) Start(ctx context.Context) {
@@ -35,10 +61,20 @@ func (d *Daemon) Start(ctx context.Context) {
 				logger.Infof(ctx, "Daemon stopped")
 				return
 			case <-ticker.C:
-				if err := d.eventCronProcessor.Do(ctx); err != nil {
-					logger.Errorf(ctx, "cronProcessor Do error: %v", err)
-				}
+				d.do(ctx)
+			case <-d.wakeup:
+				d.do(ctx)
 			}
 		}
 	})
 }
+
+func (d *Daemon) do(ctx context.Context) {
+	if d.leader != nil && !d.leader.IsLeader() {
+		return
+	}
+
+	if err := d.cronProcessor.Do(ctx); err != nil {
+		logger.Errorf(ctx, "cronProcessor Do error: %v", err)
+	}
+}
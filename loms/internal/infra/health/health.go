@@ -0,0 +1,226 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"route256/loms/internal/infra/logger"
+	"slices"
+	"sync"
+	"time"
+
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+var ErrCheckTimeout = errors.New("check timed out")
+
+const (
+	statusOK          = "ok"
+	statusUnavailable = "unavailable"
+)
+
+type CheckFunc func(ctx context.Context) error
+
+type check struct {
+	name string
+	fnc  CheckFunc
+}
+
+type Checker struct {
+	timeout  time.Duration
+	checks   []check
+	details  map[string]struct{}
+	services map[string][]string
+	server   *grpchealth.Server
+
+	mx      sync.RWMutex
+	results map[string]error
+}
+
+func New(timeout time.Duration) *Checker {
+	server := grpchealth.NewServer()
+	server.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+
+	return &Checker{
+		timeout:  timeout,
+		details:  make(map[string]struct{}),
+		services: make(map[string][]string),
+		server:   server,
+	}
+}
+
+func (c *Checker) Add(name string, fnc CheckFunc) {
+	c.checks = append(c.checks, check{name: name, fnc: fnc})
+}
+
+// AddDetail registers a check that is reported by the readiness handler but
+// neither makes the instance unready nor stops serving.
+func (c *Checker) AddDetail(name string, fnc CheckFunc) {
+	c.Add(name, fnc)
+	c.details[name] = struct{}{}
+}
+
+func (c *Checker) Service(name string, dependsOn ...string) {
+	c.services[name] = dependsOn
+	c.server.SetServingStatus(name, healthpb.HealthCheckResponse_NOT_SERVING)
+}
+
+func (c *Checker) Server() *grpchealth.Server {
+	return c.server
+}
+
+func (c *Checker) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		c.runChecks(ctx)
+
+		select {
+		case <-ctx.Done():
+			c.server.Shutdown()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *Checker) Ready() (bool, map[string]error) {
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+
+	if c.results == nil {
+		return false, nil
+	}
+
+	ready := true
+	for name, err := range c.results {
+		if err != nil && c.gating(name) {
+			ready = false
+		}
+	}
+
+	return ready, c.results
+}
+
+func (c *Checker) LivenessHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(statusOK))
+	}
+}
+
+func (c *Checker) ReadinessHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ready, results := c.Ready()
+
+		resp := struct {
+			Status string            `json:"status"`
+			Checks map[string]string `json:"checks"`
+		}{
+			Status: statusOK,
+			Checks: make(map[string]string, len(results)),
+		}
+
+		for name, err := range results {
+			resp.Checks[name] = statusOK
+			if err != nil {
+				resp.Checks[name] = err.Error()
+			}
+		}
+
+		code := http.StatusOK
+		if !ready {
+			resp.Status = statusUnavailable
+			code = http.StatusServiceUnavailable
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			logger.Warnf(r.Context(), "failed to write readiness response: %v", err)
+		}
+	}
+}
+
+func (c *Checker) runChecks(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	type result struct {
+		name string
+		err  error
+	}
+
+	resultCh := make(chan result, len(c.checks))
+	for _, chk := range c.checks {
+		go func() {
+			resultCh <- result{name: chk.name, err: chk.fnc(ctx)}
+		}()
+	}
+
+	results := make(map[string]error, len(c.checks))
+	for _, chk := range c.checks {
+		results[chk.name] = ErrCheckTimeout
+	}
+
+	for range c.checks {
+		select {
+		case res := <-resultCh:
+			results[res.name] = res.err
+		case <-ctx.Done():
+		}
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	c.mx.Lock()
+	prev := c.results
+	c.results = results
+	c.mx.Unlock()
+
+	for name, err := range results {
+		if err != nil && (prev == nil || prev[name] == nil) {
+			logger.Warnf(ctx, "health check %s failed: %v", name, err)
+		}
+
+		if err == nil && prev != nil && prev[name] != nil {
+			logger.Infof(ctx, "health check %s recovered", name)
+		}
+	}
+
+	c.updateServingStatus(results)
+}
+
+func (c *Checker) updateServingStatus(results map[string]error) {
+	overall := healthpb.HealthCheckResponse_SERVING
+
+	for name := range results {
+		if results[name] != nil && c.gating(name) {
+			overall = healthpb.HealthCheckResponse_NOT_SERVING
+		}
+	}
+
+	c.server.SetServingStatus("", overall)
+
+	for service, dependsOn := range c.services {
+		status := healthpb.HealthCheckResponse_SERVING
+		for name, err := range results {
+			if err != nil && c.gating(name) && slices.Contains(dependsOn, name) {
+				status = healthpb.HealthCheckResponse_NOT_SERVING
+			}
+		}
+
+		c.server.SetServingStatus(service, status)
+	}
+}
+
+func (c *Checker) gating(name string) bool {
+	_, detail := c.details[name]
+	return !detail
+}
@@ -0,0 +1,110 @@
+package leader
+
+import (
+	"context"
+	"fmt"
+	"route256/loms/internal/infra/logger"
+	"route256/loms/internal/infra/metrics"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const unlockTimeout = 5 * time.Second
+
+type Elector struct {
+	dsn           string
+	lockID        int64
+	checkInterval time.Duration
+	isLeader      atomic.Bool
+}
+
+func New(dsn string, lockID int64, checkInterval time.Duration) *Elector {
+	return &Elector{
+		dsn:           dsn,
+		lockID:        lockID,
+		checkInterval: checkInterval,
+	}
+}
+
+func (e *Elector) IsLeader() bool {
+	return e.isLeader.Load()
+}
+
+func (e *Elector) Run(ctx context.Context) {
+	for ctx.Err() == nil {
+		if err := e.campaign(ctx); err != nil && ctx.Err() == nil {
+			logger.Errorf(ctx, "leader.Elector: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(e.checkInterval):
+		}
+	}
+}
+
+func (e *Elector) campaign(ctx context.Context) error {
+	conn, err := pgx.Connect(ctx, e.dsn)
+	if err != nil {
+		return fmt.Errorf("pgx.Connect: %w", err)
+	}
+	defer conn.Close(context.Background())
+
+	ticker := time.NewTicker(e.checkInterval)
+	defer ticker.Stop()
+
+	for {
+		if !e.IsLeader() {
+			var acquired bool
+			if err := conn.QueryRow(ctx, "SELECT pg_try_advisory_lock($1)", e.lockID).Scan(&acquired); err != nil {
+				return fmt.Errorf("pg_try_advisory_lock: %w", err)
+			}
+
+			if acquired {
+				e.setLeader(ctx, true)
+				defer e.resign(conn)
+			}
+		} else if err := conn.Ping(ctx); err != nil {
+			e.setLeader(ctx, false)
+			return fmt.Errorf("conn.Ping: %w", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (e *Elector) resign(conn *pgx.Conn) {
+	ctx, cancel := context.WithTimeout(context.Background(), unlockTimeout)
+	defer cancel()
+
+	e.setLeader(ctx, false)
+
+	if conn.IsClosed() {
+		return
+	}
+
+	if _, err := conn.Exec(ctx, "SELECT pg_advisory_unlock($1)", e.lockID); err != nil {
+		logger.Errorf(ctx, "leader.Elector: pg_advisory_unlock: %v", err)
+	}
+}
+
+func (e *Elector) setLeader(ctx context.Context, isLeader bool) {
+	if e.isLeader.Swap(isLeader) == isLeader {
+		return
+	}
+
+	metrics.SetLeader(isLeader)
+
+	if isLeader {
+		logger.Infof(ctx, "leader.Elector: acquired leadership")
+	} else {
+		logger.Infof(ctx, "leader.Elector: lost leadership")
+	}
+}
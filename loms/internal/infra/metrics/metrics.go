@@ -20,6 +20,14 @@ const (
 
 	DBQueryStatusOK    = "OK"
 	DBQueryStatusError = "error"
+
+	OutboxRemovedArchived = "archived"
+	OutboxRemovedDeleted  = "deleted"
+
+	ReplicaReadCaughtUp    = "replica"
+	ReplicaReadFallback    = "fallback_master"
+	ReplicaReadMaster      = "master"
+	ReplicaReadUnavailable = "no_healthy_replica"
 )
 
 type Metrics struct {
@@ -36,6 +44,19 @@ type Metrics struct {
 
 	kafkaProduceTotal             *prometheus.CounterVec
 	kafkaProduceDurationHistogram *prometheus.HistogramVec
+
+	outboxRemovedTotal             *prometheus.CounterVec
+	outboxOldestUndeliveredSeconds *prometheus.GaugeVec
+	outboxEventLatencyHistogram    *prometheus.HistogramVec
+
+	leader prometheus.Gauge
+
+	txRetryTotal *prometheus.CounterVec
+
+	replicaReadTotal *prometheus.CounterVec
+
+	dbReplicaUp         *prometheus.GaugeVec
+	dbReplicaLagSeconds *prometheus.GaugeVec
 }
 
 var (
@@ -136,6 +157,72 @@ func Init(_ context.Context) error {
 				},
 				[]string{"topic", "status"},
 			),
+
+			outboxRemovedTotal: promauto.NewCounterVec(
+				prometheus.CounterOpts{
+					Name: appName + "_outbox_removed_total",
+					Help: "Total number of sent outbox events removed by retention job by mode",
+				},
+				[]string{"mode"},
+			),
+
+			outboxOldestUndeliveredSeconds: promauto.NewGaugeVec(
+				prometheus.GaugeOpts{
+					Name: appName + "_outbox_oldest_undelivered_seconds",
+					Help: "Age of the oldest undelivered outbox event in seconds",
+				},
+				[]string{"shard"},
+			),
+
+			outboxEventLatencyHistogram: promauto.NewHistogramVec(
+				prometheus.HistogramOpts{
+					Name: appName + "_outbox_event_latency_seconds",
+					Help: "Histogram of time from outbox insert to Kafka delivery by topic",
+					Buckets: []float64{
+						0.01, 0.05, 0.1, 0.25, 0.5, 1, 2, 5, 10, 30, 60,
+					},
+				},
+				[]string{"topic"},
+			),
+
+			leader: promauto.NewGauge(
+				prometheus.GaugeOpts{
+					Name: appName + "_leader",
+					Help: "Whether this instance is the leader running singleton jobs, 1 or 0",
+				},
+			),
+
+			txRetryTotal: promauto.NewCounterVec(
+				prometheus.CounterOpts{
+					Name: appName + "_tx_retries_total",
+					Help: "Total number of transaction retries by isolation level and SQLSTATE",
+				},
+				[]string{"iso_level", "sqlstate"},
+			),
+
+			replicaReadTotal: promauto.NewCounterVec(
+				prometheus.CounterOpts{
+					Name: appName + "_consistent_reads_total",
+					Help: "Total number of replica-routed reads by the pool that served them",
+				},
+				[]string{"result"},
+			),
+
+			dbReplicaUp: promauto.NewGaugeVec(
+				prometheus.GaugeOpts{
+					Name: appName + "_db_replica_up",
+					Help: "Whether the replica passed its last health check",
+				},
+				[]string{"replica"},
+			),
+
+			dbReplicaLagSeconds: promauto.NewGaugeVec(
+				prometheus.GaugeOpts{
+					Name: appName + "_db_replica_lag_seconds",
+					Help: "Replication lag of the replica observed by the last health check",
+				},
+				[]string{"replica"},
+			),
 		}
 	})
 
@@ -177,3 +264,45 @@ func IncKafkaProduceCounter(topic, status string) {
 func KafkaProduceDurationHistogram(topic, status string, duration float64) {
 	metrics.kafkaProduceDurationHistogram.WithLabelValues(topic, status).Observe(duration)
 }
+
+func AddOutboxRemovedCounter(mode string, count int64) {
+	metrics.outboxRemovedTotal.WithLabelValues(mode).Add(float64(count))
+}
+
+func SetOutboxOldestUndeliveredSeconds(shard string, age float64) {
+	metrics.outboxOldestUndeliveredSeconds.WithLabelValues(shard).Set(age)
+}
+
+func OutboxEventLatencyHistogram(topic string, latency float64) {
+	metrics.outboxEventLatencyHistogram.WithLabelValues(topic).Observe(latency)
+}
+
+func SetLeader(isLeader bool) {
+	if isLeader {
+		metrics.leader.Set(1)
+		return
+	}
+
+	metrics.leader.Set(0)
+}
+
+func IncTxRetryCounter(isoLevel, sqlState string) {
+	metrics.txRetryTotal.WithLabelValues(isoLevel, sqlState).Inc()
+}
+
+func IncReplicaReadCounter(result string) {
+	metrics.replicaReadTotal.WithLabelValues(result).Inc()
+}
+
+func SetDBReplicaUp(replica string, up bool) {
+	if up {
+		metrics.dbReplicaUp.WithLabelValues(replica).Set(1)
+		return
+	}
+
+	metrics.dbReplicaUp.WithLabelValues(replica).Set(0)
+}
+
+func SetDBReplicaLag(replica string, seconds float64) {
+	metrics.dbReplicaLagSeconds.WithLabelValues(replica).Set(seconds)
+}
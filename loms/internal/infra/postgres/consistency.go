@@ -0,0 +1,112 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+	"route256/loms/internal/infra/metrics"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	replicaPollInterval = 10 * time.Millisecond
+
+	lsnSeparator = ","
+)
+
+type readConsistencyKey struct{}
+
+type readConsistency struct {
+	minLSN      string
+	forceMaster bool
+}
+
+func WithMinLSN(ctx context.Context, lsn string) context.Context {
+	return context.WithValue(ctx, readConsistencyKey{}, readConsistency{minLSN: lsn})
+}
+
+func WithMasterRead(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readConsistencyKey{}, readConsistency{forceMaster: true})
+}
+
+func minLSNForShard(ctx context.Context, shard int) context.Context {
+	consistency, ok := ctx.Value(readConsistencyKey{}).(readConsistency)
+	if !ok || consistency.minLSN == "" {
+		return ctx
+	}
+
+	lsns := strings.Split(consistency.minLSN, lsnSeparator)
+	if len(lsns) == 1 {
+		return ctx
+	}
+
+	if shard >= len(lsns) {
+		return WithMasterRead(ctx)
+	}
+
+	return WithMinLSN(ctx, lsns[shard])
+}
+
+func (p *Pools) CurrentLSN(ctx context.Context) (string, error) {
+	var lsn string
+	if err := p.Master.QueryRow(ctx, `SELECT pg_current_wal_lsn()::text`).Scan(&lsn); err != nil {
+		return "", fmt.Errorf("pg_current_wal_lsn: %w", err)
+	}
+
+	return lsn, nil
+}
+
+func (p *Pools) GetReadReplica(ctx context.Context) *pgxpool.Pool {
+	consistency, ok := ctx.Value(readConsistencyKey{}).(readConsistency)
+	if ok && consistency.forceMaster {
+		metrics.IncReplicaReadCounter(metrics.ReplicaReadMaster)
+		return p.Master
+	}
+
+	replica := p.replica()
+	if replica == nil {
+		metrics.IncReplicaReadCounter(metrics.ReplicaReadUnavailable)
+		return p.Master
+	}
+
+	if !ok {
+		return replica.Pool
+	}
+
+	if p.waitReplay(ctx, replica.Pool, consistency.minLSN) {
+		metrics.IncReplicaReadCounter(metrics.ReplicaReadCaughtUp)
+		return replica.Pool
+	}
+
+	metrics.IncReplicaReadCounter(metrics.ReplicaReadFallback)
+	return p.Master
+}
+
+func (p *Pools) waitReplay(ctx context.Context, replica *pgxpool.Pool, lsn string) bool {
+	deadline := time.Now().Add(p.replicaWait)
+
+	for {
+		var replayed bool
+		err := replica.QueryRow(ctx,
+			`SELECT COALESCE(pg_last_wal_replay_lsn() >= $1::pg_lsn, true)`, lsn).Scan(&replayed)
+		if err != nil {
+			return false
+		}
+
+		if replayed {
+			return true
+		}
+
+		if time.Now().Add(replicaPollInterval).After(deadline) {
+			return false
+		}
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(replicaPollInterval):
+		}
+	}
+}
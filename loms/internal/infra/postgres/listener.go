@@ -0,0 +1,79 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+	"route256/loms/internal/infra/logger"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const listenerRetryDelay = time.Second
+
+type Listener struct {
+	dsn           string
+	channel       string
+	notifications chan struct{}
+}
+
+func NewListener(dsn, channel string) *Listener {
+	return &Listener{
+		dsn:           dsn,
+		channel:       channel,
+		notifications: make(chan struct{}, 1),
+	}
+}
+
+func (l *Listener) Notifications() <-chan struct{} {
+	return l.notifications
+}
+
+func (l *Listener) Run(ctx context.Context) {
+	for ctx.Err() == nil {
+		err := l.listen(ctx)
+		if err == nil || ctx.Err() != nil {
+			return
+		}
+
+		logger.Errorf(ctx, "pg.Listener %s: %v", l.channel, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(listenerRetryDelay):
+		}
+	}
+}
+
+func (l *Listener) listen(ctx context.Context) error {
+	conn, err := pgx.Connect(ctx, l.dsn)
+	if err != nil {
+		return fmt.Errorf("pgx.Connect: %w", err)
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{l.channel}.Sanitize()); err != nil {
+		return fmt.Errorf("LISTEN: %w", err)
+	}
+
+	logger.Infof(ctx, "pg.Listener is listening on %s", l.channel)
+
+	// notifications sent while we were disconnected are lost
+	l.wake()
+
+	for {
+		if _, err := conn.WaitForNotification(ctx); err != nil {
+			return fmt.Errorf("conn.WaitForNotification: %w", err)
+		}
+
+		l.wake()
+	}
+}
+
+func (l *Listener) wake() {
+	select {
+	case l.notifications <- struct{}{}:
+	default:
+	}
+}
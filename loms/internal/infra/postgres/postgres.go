@@ -2,39 +2,234 @@ package pg
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net"
+	"route256/loms/internal/infra/logger"
+	"route256/loms/internal/infra/metrics"
+	txmanager "route256/loms/internal/infra/tx_manager"
+	"strconv"
+	"sync/atomic"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+const replicaCheckTimeout = 2 * time.Second
+
+var ErrNoHealthyReplica = errors.New("no healthy replica")
+
+type Replica struct {
+	Name string
+	Pool *pgxpool.Pool
+
+	healthy atomic.Bool
+	lag     atomic.Int64
+}
+
+func (r *Replica) Healthy() bool {
+	return r.healthy.Load()
+}
+
+func (r *Replica) Lag() time.Duration {
+	return time.Duration(r.lag.Load())
+}
+
 type Pools struct {
-	Master  *pgxpool.Pool
-	Replica *pgxpool.Pool
+	Master   *pgxpool.Pool
+	Replicas []*Replica
+
+	replicaWait   time.Duration
+	maxReplicaLag time.Duration
+	next          atomic.Uint64
 }
 
-func New(ctx context.Context, masterDSN, replicaDSN string) (*Pools, error) {
+type Option func(p *Pools)
+
+func WithReplicaWait(wait time.Duration) Option {
+	return func(p *Pools) {
+		p.replicaWait = wait
+	}
+}
+
+func WithMaxReplicaLag(lag time.Duration) Option {
+	return func(p *Pools) {
+		p.maxReplicaLag = lag
+	}
+}
+
+func New(ctx context.Context, masterDSN string, replicaDSNs []string, opts ...Option) (*Pools, error) {
 	masterPool, err := createPool(ctx, masterDSN, "master")
 	if err != nil {
 		return nil, err
 	}
 
-	replicaPool, err := createPool(ctx, replicaDSN, "replica")
-	if err != nil {
-		return nil, err
+	pools := &Pools{
+		Master:   masterPool,
+		Replicas: make([]*Replica, 0, len(replicaDSNs)),
+	}
+
+	for _, opt := range opts {
+		opt(pools)
 	}
 
-	return &Pools{
-		Master:  masterPool,
-		Replica: replicaPool,
-	}, nil
+	for _, dsn := range replicaDSNs {
+		replica, err := createReplica(ctx, dsn)
+		if err != nil {
+			pools.Close()
+			return nil, err
+		}
+
+		pools.Replicas = append(pools.Replicas, replica)
+	}
+
+	return pools, nil
 }
 
-func (p *Pools) GetWriteReplica() *pgxpool.Pool {
+func (p *Pools) GetWriteReplica(_ context.Context) *pgxpool.Pool {
 	return p.Master
 }
 
-func (p *Pools) GetReadReplica() *pgxpool.Pool {
-	return p.Replica
+func (p *Pools) TxKey() any {
+	return txmanager.TxKey
+}
+
+func (p *Pools) ReadTransactor() *ReadTransactor {
+	return &ReadTransactor{pools: p}
+}
+
+func (p *Pools) Close() {
+	p.Master.Close()
+
+	for _, replica := range p.Replicas {
+		replica.Pool.Close()
+	}
+}
+
+func (p *Pools) Ping(ctx context.Context) error {
+	return p.Master.Ping(ctx)
+}
+
+func (p *Pools) CheckReplicas(_ context.Context) error {
+	if len(p.Replicas) == 0 {
+		return nil
+	}
+
+	for _, replica := range p.Replicas {
+		if p.usable(replica) {
+			return nil
+		}
+	}
+
+	return ErrNoHealthyReplica
+}
+
+func (p *Pools) RunHealthCheck(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for _, replica := range p.Replicas {
+			p.checkReplica(ctx, replica)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *Pools) checkReplica(ctx context.Context, replica *Replica) {
+	ctx, cancel := context.WithTimeout(ctx, replicaCheckTimeout)
+	defer cancel()
+
+	var lagSec float64
+	err := replica.Pool.QueryRow(ctx, `
+		SELECT CASE
+			WHEN NOT pg_is_in_recovery() THEN 0
+			WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+			ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+		END::float8`).Scan(&lagSec)
+	if err != nil {
+		if replica.healthy.Swap(false) {
+			logger.Warnf(ctx, "replica %s is unhealthy: %v", replica.Name, err)
+		}
+		metrics.SetDBReplicaUp(replica.Name, false)
+		return
+	}
+
+	if !replica.healthy.Swap(true) {
+		logger.Infof(ctx, "replica %s is healthy", replica.Name)
+	}
+
+	replica.lag.Store(int64(lagSec * float64(time.Second)))
+
+	metrics.SetDBReplicaUp(replica.Name, true)
+	metrics.SetDBReplicaLag(replica.Name, lagSec)
+}
+
+func (p *Pools) replica() *Replica {
+	count := len(p.Replicas)
+	if count == 0 {
+		return nil
+	}
+
+	start := p.next.Add(1)
+	for idx := range count {
+		replica := p.Replicas[(start+uint64(idx))%uint64(count)] // #nosec G115
+		if p.usable(replica) {
+			return replica
+		}
+	}
+
+	return nil
+}
+
+func (p *Pools) usable(replica *Replica) bool {
+	if !replica.Healthy() {
+		return false
+	}
+
+	return p.maxReplicaLag <= 0 || replica.Lag() <= p.maxReplicaLag
+}
+
+type ReadTransactor struct {
+	pools *Pools
+}
+
+func (t *ReadTransactor) BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error) {
+	return t.pools.GetReadReplica(ctx).BeginTx(ctx, txOptions)
+}
+
+func createReplica(ctx context.Context, dsn string) (*Replica, error) {
+	config, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("pgxpool.ParseConfig (replica): %w", err)
+	}
+
+	pool, err := pgxpool.NewWithConfig(ctx, config)
+	if err != nil {
+		return nil, fmt.Errorf("pgxpool.NewWithConfig failed to create replica pool: %w", err)
+	}
+
+	replica := &Replica{
+		Name: net.JoinHostPort(config.ConnConfig.Host, strconv.Itoa(int(config.ConnConfig.Port))),
+		Pool: pool,
+	}
+
+	if err := pool.Ping(ctx); err != nil {
+		logger.Warnf(ctx, "replica %s pool.Ping failed, reads go to master until it recovers: %v", replica.Name, err)
+		metrics.SetDBReplicaUp(replica.Name, false)
+		return replica, nil
+	}
+
+	replica.healthy.Store(true)
+	metrics.SetDBReplicaUp(replica.Name, true)
+
+	return replica, nil
 }
 
 func createPool(ctx context.Context, dsn string, role string) (*pgxpool.Pool, error) {
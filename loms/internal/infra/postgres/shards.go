@@ -0,0 +1,156 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"route256/loms/internal/infra/sharding"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var ErrShardMismatch = errors.New("database already holds data of another shard")
+
+type Shards struct {
+	shards []*Pools
+	txKey  any
+}
+
+func NewShards(ctx context.Context, txKey any, shards ...*Pools) (*Shards, error) {
+	if len(shards) == 0 {
+		return nil, errors.New("at least one shard is required")
+	}
+
+	if len(shards) > sharding.MaxShards {
+		return nil, fmt.Errorf("too many shards: %d, max %d", len(shards), sharding.MaxShards)
+	}
+
+	for idx, pools := range shards {
+		if err := registerShard(ctx, pools, idx); err != nil {
+			return nil, fmt.Errorf("shard %d: %w", idx, err)
+		}
+	}
+
+	return &Shards{
+		shards: shards,
+		txKey:  txKey,
+	}, nil
+}
+
+func (s *Shards) Count() int {
+	return len(s.shards)
+}
+
+func (s *Shards) Shard(idx int) *Pools {
+	return s.shards[idx]
+}
+
+func (s *Shards) TxKey() any {
+	return s.txKey
+}
+
+func (s *Shards) GetWriteReplica(ctx context.Context) *pgxpool.Pool {
+	return s.pools(ctx).GetWriteReplica(ctx)
+}
+
+func (s *Shards) GetReadReplica(ctx context.Context) *pgxpool.Pool {
+	idx := s.index(ctx)
+
+	return s.shards[idx].GetReadReplica(minLSNForShard(ctx, idx))
+}
+
+func (s *Shards) BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error) {
+	return s.pools(ctx).Master.BeginTx(ctx, txOptions)
+}
+
+func (s *Shards) ReadTransactor() *ShardsReadTransactor {
+	return &ShardsReadTransactor{shards: s}
+}
+
+func (s *Shards) CurrentLSN(ctx context.Context) (string, error) {
+	lsns := make([]string, len(s.shards))
+
+	for idx, pools := range s.shards {
+		lsn, err := pools.CurrentLSN(ctx)
+		if err != nil {
+			return "", fmt.Errorf("shard %d: %w", idx, err)
+		}
+
+		lsns[idx] = lsn
+	}
+
+	return strings.Join(lsns, lsnSeparator), nil
+}
+
+func (s *Shards) RunHealthCheck(ctx context.Context, interval time.Duration) {
+	wg := sync.WaitGroup{}
+
+	for _, pools := range s.shards {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			pools.RunHealthCheck(ctx, interval)
+		}()
+	}
+
+	wg.Wait()
+}
+
+func (s *Shards) Close() {
+	for _, pools := range s.shards {
+		pools.Close()
+	}
+}
+
+func (s *Shards) index(ctx context.Context) int {
+	idx := sharding.ShardFromContext(ctx, len(s.shards))
+	if idx < 0 || idx >= len(s.shards) {
+		return 0
+	}
+
+	return idx
+}
+
+func (s *Shards) pools(ctx context.Context) *Pools {
+	return s.shards[s.index(ctx)]
+}
+
+type ShardsReadTransactor struct {
+	shards *Shards
+}
+
+func (t *ShardsReadTransactor) BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error) {
+	return t.shards.GetReadReplica(ctx).BeginTx(ctx, txOptions)
+}
+
+func registerShard(ctx context.Context, pools *Pools, idx int) error {
+	var (
+		shardID   int
+		hasOrders bool
+	)
+
+	err := pools.Master.QueryRow(ctx,
+		`SELECT shard_id, EXISTS (SELECT 1 FROM orders) FROM shard_info`).Scan(&shardID, &hasOrders)
+	if err != nil {
+		return fmt.Errorf("select shard_info: %w", err)
+	}
+
+	if shardID == idx {
+		return nil
+	}
+
+	if hasOrders {
+		return fmt.Errorf("%w: configured as %d, registered as %d", ErrShardMismatch, idx, shardID)
+	}
+
+	if _, err := pools.Master.Exec(ctx, `UPDATE shard_info SET shard_id = $1`, idx); err != nil {
+		return fmt.Errorf("update shard_info: %w", err)
+	}
+
+	return nil
+}
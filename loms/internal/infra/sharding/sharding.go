@@ -0,0 +1,88 @@
+package sharding
+
+import (
+	"context"
+	"encoding/binary"
+	"hash/fnv"
+	"strconv"
+)
+
+const (
+	ShardBits = 16
+	LocalBits = 63 - ShardBits
+
+	MaxShards = 1 << ShardBits
+)
+
+type routeKey struct{}
+
+type route struct {
+	shard  int
+	userID int64
+	id     int64
+	kind   routeKind
+}
+
+type routeKind int
+
+const (
+	routeShard routeKind = iota + 1
+	routeUser
+	routeID
+)
+
+func WithShard(ctx context.Context, shard int) context.Context {
+	return context.WithValue(ctx, routeKey{}, route{kind: routeShard, shard: shard})
+}
+
+func WithUserID(ctx context.Context, userID int64) context.Context {
+	return context.WithValue(ctx, routeKey{}, route{kind: routeUser, userID: userID})
+}
+
+func WithID(ctx context.Context, id int64) context.Context {
+	return context.WithValue(ctx, routeKey{}, route{kind: routeID, id: id})
+}
+
+func ShardFromContext(ctx context.Context, count int) int {
+	r, ok := ctx.Value(routeKey{}).(route)
+	if !ok || count <= 1 {
+		return 0
+	}
+
+	switch r.kind {
+	case routeShard:
+		return r.shard
+	case routeUser:
+		return ShardByUserID(r.userID, count)
+	case routeID:
+		return ShardByID(r.id)
+	default:
+		return 0
+	}
+}
+
+func ShardLabel(ctx context.Context, count int) string {
+	return strconv.Itoa(ShardFromContext(ctx, count))
+}
+
+func ShardByUserID(userID int64, count int) int {
+	if count <= 1 {
+		return 0
+	}
+
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(userID)) // #nosec G115
+
+	hash := fnv.New64a()
+	_, _ = hash.Write(buf[:])
+
+	return int(hash.Sum64() % uint64(count)) // #nosec G115
+}
+
+func ShardByID(id int64) int {
+	return int(id >> LocalBits)
+}
+
+func ID(shard int, local int64) int64 {
+	return int64(shard)<<LocalBits | local
+}
@@ -0,0 +1,46 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+const RequestIDHeader = "x-request-id"
+
+type requestIDCtxKey struct{}
+
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDCtxKey{}, requestID)
+}
+
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDCtxKey{}).(string)
+	return requestID
+}
+
+func Inject(ctx context.Context) map[string]string {
+	carrier := make(map[string]string)
+
+	span := opentracing.SpanFromContext(ctx)
+	if span == nil {
+		return carrier
+	}
+
+	_ = opentracing.GlobalTracer().Inject(span.Context(), opentracing.TextMap, opentracing.TextMapCarrier(carrier))
+
+	return carrier
+}
+
+func Extract(carrier map[string]string) opentracing.SpanContext {
+	if len(carrier) == 0 {
+		return nil
+	}
+
+	spanCtx, err := opentracing.GlobalTracer().Extract(opentracing.TextMap, opentracing.TextMapCarrier(carrier))
+	if err != nil {
+		return nil
+	}
+
+	return spanCtx
+}
@@ -2,11 +2,22 @@ package txmanager
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math/rand/v2"
 	"route256/loms/internal/infra/logger"
+	"route256/loms/internal/infra/metrics"
+	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+)
+
+const (
+	sqlStateSerializationFailure = "40001"
+	sqlStateDeadlockDetected     = "40P01"
 )
 
 type transactor interface {
@@ -15,32 +26,80 @@ type transactor interface {
 
 type txKeyType struct{}
 
-var TxKey = txKeyType{}
+type shardTxKeyType struct{}
+
+var (
+	TxKey      = txKeyType{}
+	ShardTxKey = shardTxKeyType{}
+)
 
 type Handler func(ctx context.Context) error
 
+type RetryPolicy struct {
+	MaxAttempts int
+	BackoffBase time.Duration
+	BackoffMax  time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BackoffBase: 10 * time.Millisecond,
+	BackoffMax:  200 * time.Millisecond,
+}
+
+type Option func(mgr *TxManager)
+
+func WithReplica(replica transactor) Option {
+	return func(mgr *TxManager) {
+		mgr.replica = replica
+	}
+}
+
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(mgr *TxManager) {
+		mgr.retryPolicy = policy
+	}
+}
+
+func WithTxKey(key any) Option {
+	return func(mgr *TxManager) {
+		mgr.txKey = key
+	}
+}
+
 type TxManager struct {
-	db transactor
+	db          transactor
+	replica     transactor
+	retryPolicy RetryPolicy
+	txKey       any
 }
 
-func New(db transactor) *TxManager {
-	return &TxManager{
-		db: db,
+func New(db transactor, opts ...Option) *TxManager {
+	mgr := &TxManager{
+		db:          db,
+		retryPolicy: DefaultRetryPolicy,
+		txKey:       TxKey,
+	}
+
+	for _, opt := range opts {
+		opt(mgr)
 	}
+
+	return mgr
 }
 
-func (mgr *TxManager) transaction(ctx context.Context, opts pgx.TxOptions, fnc Handler) (err error) {
-	tx, ok := ctx.Value(TxKey).(pgx.Tx)
+func (mgr *TxManager) transaction(ctx context.Context, db transactor, opts pgx.TxOptions, fnc Handler) (err error) {
+	tx, ok := ctx.Value(mgr.txKey).(pgx.Tx)
 	if ok {
 		return fnc(ctx)
 	}
 
-	tx, err = mgr.db.BeginTx(ctx, opts)
+	tx, err = db.BeginTx(ctx, opts)
 	if err != nil {
 		return fmt.Errorf("failed to begin tx: %w", err)
 	}
 
-	ctx = context.WithValue(ctx, TxKey, tx)
+	ctx = context.WithValue(ctx, mgr.txKey, tx)
 
 	defer func() {
 		if rec := recover(); rec != nil {
@@ -70,10 +129,103 @@ func (mgr *TxManager) transaction(ctx context.Context, opts pgx.TxOptions, fnc H
 	return err
 }
 
+func (mgr *TxManager) transactionWithRetry(ctx context.Context, db transactor, opts pgx.TxOptions, fnc Handler) error {
+	if _, ok := ctx.Value(mgr.txKey).(pgx.Tx); ok {
+		return fnc(ctx)
+	}
+
+	span := opentracing.SpanFromContext(ctx)
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = mgr.transaction(ctx, db, opts, fnc)
+
+		sqlState, retryable := retryableSQLState(err)
+		if !retryable || attempt >= mgr.retryPolicy.MaxAttempts {
+			break
+		}
+
+		metrics.IncTxRetryCounter(string(opts.IsoLevel), sqlState)
+		if span != nil {
+			span.LogKV("event", "tx retry", "attempt", attempt, "sqlstate", sqlState)
+		}
+
+		logger.Warnf(ctx, "transaction attempt %d failed with sqlstate %s, retrying", attempt, sqlState)
+
+		select {
+		case <-ctx.Done():
+			return errors.Join(err, ctx.Err())
+		case <-time.After(mgr.backoff(attempt)):
+		}
+	}
+
+	if err != nil && span != nil {
+		ext.Error.Set(span, true)
+	}
+
+	return err
+}
+
+func (mgr *TxManager) backoff(attempt int) time.Duration {
+	delay := mgr.retryPolicy.BackoffBase << (attempt - 1)
+	if delay <= 0 || delay > mgr.retryPolicy.BackoffMax {
+		delay = mgr.retryPolicy.BackoffMax
+	}
+
+	if delay <= 0 {
+		return 0
+	}
+
+	return delay/2 + rand.N(delay/2+1) // #nosec G404
+}
+
+func retryableSQLState(err error) (string, bool) {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return "", false
+	}
+
+	switch pgErr.Code {
+	case sqlStateSerializationFailure, sqlStateDeadlockDetected:
+		return pgErr.Code, true
+	default:
+		return "", false
+	}
+}
+
 func (mgr *TxManager) ReadCommitted(ctx context.Context, fnc Handler) error {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "txmanager.ReadCommitted")
 	defer span.Finish()
 
 	txOpts := pgx.TxOptions{IsoLevel: pgx.ReadCommitted}
-	return mgr.transaction(ctx, txOpts, fnc)
+	return mgr.transactionWithRetry(ctx, mgr.db, txOpts, fnc)
+}
+
+func (mgr *TxManager) RepeatableRead(ctx context.Context, fnc Handler) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "txmanager.RepeatableRead")
+	defer span.Finish()
+
+	txOpts := pgx.TxOptions{IsoLevel: pgx.RepeatableRead}
+	return mgr.transactionWithRetry(ctx, mgr.db, txOpts, fnc)
+}
+
+func (mgr *TxManager) Serializable(ctx context.Context, fnc Handler) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "txmanager.Serializable")
+	defer span.Finish()
+
+	txOpts := pgx.TxOptions{IsoLevel: pgx.Serializable}
+	return mgr.transactionWithRetry(ctx, mgr.db, txOpts, fnc)
+}
+
+func (mgr *TxManager) ReadOnly(ctx context.Context, fnc Handler) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "txmanager.ReadOnly")
+	defer span.Finish()
+
+	db := mgr.db
+	if mgr.replica != nil {
+		db = mgr.replica
+	}
+
+	txOpts := pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly}
+	return mgr.transactionWithRetry(ctx, db, txOpts, fnc)
 }
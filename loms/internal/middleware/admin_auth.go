@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"context"
+	"crypto/subtle"
+	desc "route256/loms/internal/pb/loms/v1"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	authorizationKey = "authorization"
+	bearerPrefix     = "Bearer "
+)
+
+func AdminAuth(adminToken string) grpc.UnaryServerInterceptor {
+	return bearerAuth("admin", adminToken,
+		"/"+desc.StockAdmin_ServiceDesc.ServiceName+"/",
+		"/"+desc.OutboxAdmin_ServiceDesc.ServiceName+"/",
+	)
+}
+
+func bearerAuth(scope, secret string, prefixes ...string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !isProtectedMethod(info.FullMethod, prefixes) {
+			return handler(ctx, req)
+		}
+
+		if secret == "" {
+			return nil, status.Errorf(codes.PermissionDenied, "%s api is disabled", scope)
+		}
+
+		md, ok := metadata.FromIncomingContext(ctx)
+		if !ok {
+			return nil, status.Error(codes.Unauthenticated, "missing metadata")
+		}
+
+		values := md.Get(authorizationKey)
+		if len(values) == 0 || !strings.HasPrefix(values[0], bearerPrefix) {
+			return nil, status.Errorf(codes.Unauthenticated, "missing %s token", scope)
+		}
+
+		token := strings.TrimPrefix(values[0], bearerPrefix)
+		if subtle.ConstantTimeCompare([]byte(token), []byte(secret)) != 1 {
+			return nil, status.Errorf(codes.PermissionDenied, "invalid %s token", scope)
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func isProtectedMethod(fullMethod string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(fullMethod, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"context"
+	"regexp"
+	"route256/loms/internal/infra/logger"
+	pg "route256/loms/internal/infra/postgres"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const MinLSNKey = "x-min-lsn"
+
+const (
+	ReadConsistencyNone   = "none"
+	ReadConsistencyWait   = "wait"
+	ReadConsistencyMaster = "master"
+)
+
+var lsnPattern = regexp.MustCompile(`^[0-9A-Fa-f]{1,8}/[0-9A-Fa-f]{1,8}(,[0-9A-Fa-f]{1,8}/[0-9A-Fa-f]{1,8})*$`)
+
+type lsnSource interface {
+	CurrentLSN(ctx context.Context) (string, error)
+}
+
+func ReadYourWrites(source lsnSource, writeMethods []string, readMethods map[string]string) grpc.UnaryServerInterceptor {
+	writes := make(map[string]struct{}, len(writeMethods))
+	for _, method := range writeMethods {
+		writes[method] = struct{}{}
+	}
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if _, ok := writes[info.FullMethod]; ok {
+			res, err := handler(ctx, req)
+			if err != nil {
+				return res, err
+			}
+
+			lsn, lsnErr := source.CurrentLSN(ctx)
+			if lsnErr != nil {
+				logger.Warnf(ctx, "failed to get commit lsn: %v", lsnErr)
+				return res, nil
+			}
+
+			if trailerErr := grpc.SetTrailer(ctx, metadata.Pairs(MinLSNKey, lsn)); trailerErr != nil {
+				logger.Warnf(ctx, "failed to set lsn trailer: %v", trailerErr)
+			}
+
+			return res, nil
+		}
+
+		mode := readMethods[info.FullMethod]
+		if mode == "" || mode == ReadConsistencyNone {
+			return handler(ctx, req)
+		}
+
+		md, ok := metadata.FromIncomingContext(ctx)
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		values := md.Get(MinLSNKey)
+		if len(values) == 0 || !lsnPattern.MatchString(values[0]) {
+			return handler(ctx, req)
+		}
+
+		switch mode {
+		case ReadConsistencyMaster:
+			ctx = pg.WithMasterRead(ctx)
+		case ReadConsistencyWait:
+			ctx = pg.WithMinLSN(ctx, values[0])
+		}
+
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+)
+
+const (
+	EventStreamContentType = "text/event-stream"
+
+	eventStreamPrefix = "data: "
+)
+
+var eventStreamDelimiter = []byte("\n\n")
+
+type EventStreamMarshaler struct {
+	runtime.JSONPb
+}
+
+func (m *EventStreamMarshaler) ContentType(_ any) string {
+	return EventStreamContentType
+}
+
+func (m *EventStreamMarshaler) Marshal(v any) ([]byte, error) {
+	data, err := m.JSONPb.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(eventStreamPrefix), data...), nil
+}
+
+func (m *EventStreamMarshaler) Delimiter() []byte {
+	return eventStreamDelimiter
+}
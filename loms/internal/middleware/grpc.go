@@ -3,8 +3,10 @@ package middleware
 import (
 	"context"
 	"route256/loms/internal/infra/metrics"
+	"route256/loms/internal/infra/tracing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/opentracing/opentracing-go"
 	"github.com/opentracing/opentracing-go/ext"
 	"github.com/uber/jaeger-client-go"
@@ -25,6 +27,28 @@ func Validate(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grp
 	return handler(ctx, req)
 }
 
+type validatingStream struct {
+	grpc.ServerStream
+}
+
+func (s *validatingStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+
+	if v, ok := m.(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	return nil
+}
+
+func ValidateStream(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &validatingStream{ServerStream: ss})
+}
+
 func MetricsInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 	metrics.IncRequestCounter()
 
@@ -45,14 +69,35 @@ func MetricsInterceptor(ctx context.Context, req interface{}, info *grpc.UnarySe
 }
 
 func ServerTracingInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, info.FullMethod)
+	carrier := make(map[string]string)
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		for key, values := range md {
+			if len(values) > 0 {
+				carrier[key] = values[0]
+			}
+		}
+	}
+
+	span := opentracing.StartSpan(info.FullMethod, ext.RPCServerOption(tracing.Extract(carrier)))
 	defer span.Finish()
 
+	requestID := carrier[tracing.RequestIDHeader]
+	if requestID == "" {
+		requestID = uuid.NewString()
+	}
+	span.SetTag("request_id", requestID)
+
+	ctx = opentracing.ContextWithSpan(ctx, span)
+	ctx = tracing.WithRequestID(ctx, requestID)
+
 	spanContext, ok := span.Context().(jaeger.SpanContext)
 	if ok {
 		ctx = metadata.NewOutgoingContext(ctx, metadata.Pairs(traceIDKey, spanContext.TraceID().String()))
 
-		header := metadata.New(map[string]string{traceIDKey: spanContext.TraceID().String()})
+		header := metadata.New(map[string]string{
+			traceIDKey:              spanContext.TraceID().String(),
+			tracing.RequestIDHeader: requestID,
+		})
 		err := grpc.SendHeader(ctx, header)
 		if err != nil {
 			return nil, err
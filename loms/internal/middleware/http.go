@@ -3,9 +3,13 @@ package middleware
 import (
 	"net/http"
 	"route256/loms/internal/infra/metrics"
+	"route256/loms/internal/infra/tracing"
+	"strings"
 	"time"
 
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/opentracing/opentracing-go"
+	"github.com/uber/jaeger-client-go"
 )
 
 type statusResponseWriter struct {
@@ -26,6 +30,10 @@ func (w *statusResponseWriter) Write(data []byte) (int, error) {
 	return w.ResponseWriter.Write(data)
 }
 
+func (w *statusResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
 func HTTPMetrics(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		metrics.IncRequestCounter()
@@ -67,3 +75,12 @@ func TracingMiddleware(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r)
 	})
 }
+
+func GatewayHeaderMatcher(key string) (string, bool) {
+	switch strings.ToLower(key) {
+	case tracing.RequestIDHeader, jaeger.TraceContextHeaderName, MinLSNKey:
+		return strings.ToLower(key), true
+	default:
+		return runtime.DefaultHeaderMatcher(key)
+	}
+}
@@ -0,0 +1,13 @@
+package middleware
+
+import (
+	desc "route256/loms/internal/pb/loms/v1"
+
+	"google.golang.org/grpc"
+)
+
+func WebhookAuth(webhookSecret string) grpc.UnaryServerInterceptor {
+	return bearerAuth("webhook", webhookSecret,
+		"/"+desc.Payments_ServiceDesc.ServiceName+"/",
+	)
+}
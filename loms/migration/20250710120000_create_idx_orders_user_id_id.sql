@@ -0,0 +1,5 @@
+-- +goose Up
+CREATE INDEX idx_orders_user_id_id ON orders(user_id, id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_orders_user_id_id;
@@ -0,0 +1,6 @@
+-- +goose Up
+CREATE INDEX idx_orders_awaiting_payment ON orders(updated_at, id)
+WHERE status = 'awaiting payment';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_orders_awaiting_payment;
@@ -0,0 +1,12 @@
+-- +goose Up
+ALTER TABLE order_items
+    ADD COLUMN cancelled_count BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN returned_count BIGINT NOT NULL DEFAULT 0,
+    ADD CONSTRAINT order_items_active_count_check
+        CHECK (cancelled_count >= 0 AND returned_count >= 0 AND cancelled_count + returned_count <= count);
+
+-- +goose Down
+ALTER TABLE order_items
+    DROP CONSTRAINT IF EXISTS order_items_active_count_check,
+    DROP COLUMN IF EXISTS returned_count,
+    DROP COLUMN IF EXISTS cancelled_count;
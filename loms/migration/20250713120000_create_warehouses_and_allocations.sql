@@ -0,0 +1,41 @@
+-- +goose Up
+CREATE TABLE warehouses (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO warehouses (id, name) VALUES (1, 'main');
+SELECT setval('warehouses_id_seq', (SELECT MAX(id) FROM warehouses));
+
+ALTER TABLE stocks ADD COLUMN warehouse_id BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE stocks ALTER COLUMN warehouse_id DROP DEFAULT;
+ALTER TABLE stocks DROP CONSTRAINT stocks_pkey;
+ALTER TABLE stocks ADD PRIMARY KEY (warehouse_id, sku);
+CREATE INDEX idx_stocks_sku ON stocks (sku);
+
+CREATE TABLE order_item_allocations (
+    order_id BIGINT NOT NULL,
+    sku BIGINT NOT NULL,
+    warehouse_id BIGINT NOT NULL,
+    count BIGINT NOT NULL CHECK (count >= 0),
+    PRIMARY KEY (order_id, sku, warehouse_id)
+);
+
+INSERT INTO order_item_allocations (order_id, sku, warehouse_id, count)
+SELECT oi.order_id, oi.sku, 1, SUM(oi.count - oi.cancelled_count - oi.returned_count)
+FROM order_items oi
+JOIN orders o ON o.id = oi.order_id
+WHERE o.status IN ('awaiting payment', 'payed')
+GROUP BY oi.order_id, oi.sku;
+
+-- +goose Down
+DROP TABLE IF EXISTS order_item_allocations;
+
+DROP INDEX IF EXISTS idx_stocks_sku;
+DELETE FROM stocks WHERE warehouse_id <> 1;
+ALTER TABLE stocks DROP CONSTRAINT stocks_pkey;
+ALTER TABLE stocks ADD PRIMARY KEY (sku);
+ALTER TABLE stocks DROP COLUMN IF EXISTS warehouse_id;
+
+DROP TABLE IF EXISTS warehouses;
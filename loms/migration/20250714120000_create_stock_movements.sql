@@ -0,0 +1,33 @@
+-- +goose Up
+CREATE TABLE stock_movements (
+    id BIGSERIAL PRIMARY KEY,
+    warehouse_id BIGINT NOT NULL,
+    sku BIGINT NOT NULL,
+    kind TEXT NOT NULL,
+    order_id BIGINT,
+    reason TEXT,
+    total_delta BIGINT NOT NULL,
+    reserved_delta BIGINT NOT NULL,
+    total_count BIGINT NOT NULL,
+    reserved BIGINT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_stock_movements_sku_id ON stock_movements (sku, id);
+
+-- +goose StatementBegin
+CREATE FUNCTION stock_movements_append_only() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'stock_movements is append-only';
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER trg_stock_movements_append_only
+BEFORE UPDATE OR DELETE ON stock_movements
+FOR EACH ROW EXECUTE FUNCTION stock_movements_append_only();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS trg_stock_movements_append_only ON stock_movements;
+DROP FUNCTION IF EXISTS stock_movements_append_only();
+DROP TABLE IF EXISTS stock_movements;
@@ -0,0 +1,23 @@
+-- +goose Up
+CREATE TABLE order_status_history (
+    id BIGSERIAL PRIMARY KEY,
+    order_id BIGINT NOT NULL,
+    status order_status NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_order_status_history_order_id_id ON order_status_history (order_id, id);
+
+INSERT INTO order_status_history (order_id, status, created_at)
+SELECT id, 'new', created_at
+FROM orders
+ORDER BY id;
+
+INSERT INTO order_status_history (order_id, status, created_at)
+SELECT id, status, updated_at
+FROM orders
+WHERE status <> 'new' AND updated_at IS NOT NULL
+ORDER BY id;
+
+-- +goose Down
+DROP TABLE IF EXISTS order_status_history;
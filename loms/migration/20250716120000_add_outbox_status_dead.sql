@@ -0,0 +1,6 @@
+-- +goose NO TRANSACTION
+-- +goose Up
+ALTER TYPE outbox_status ADD VALUE IF NOT EXISTS 'dead';
+
+-- +goose Down
+SELECT 1;
@@ -0,0 +1,17 @@
+-- +goose Up
+ALTER TABLE outbox
+    ADD COLUMN attempts INT NOT NULL DEFAULT 0,
+    ADD COLUMN next_attempt_at TIMESTAMP NOT NULL DEFAULT NOW();
+
+UPDATE outbox
+SET status = 'dead'
+WHERE status = 'error';
+
+-- +goose Down
+UPDATE outbox
+SET status = 'error'
+WHERE status IN ('dead', 'pending');
+
+ALTER TABLE outbox
+    DROP COLUMN IF EXISTS next_attempt_at,
+    DROP COLUMN IF EXISTS attempts;
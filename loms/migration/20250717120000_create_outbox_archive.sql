@@ -0,0 +1,32 @@
+-- +goose Up
+CREATE TABLE outbox_archive (
+    id BIGINT NOT NULL,
+    topic TEXT NOT NULL,
+    key TEXT,
+    payload JSONB NOT NULL,
+    attempts INT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    sent_at TIMESTAMP NOT NULL,
+    archived_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (id, sent_at)
+) PARTITION BY RANGE (sent_at);
+
+-- +goose StatementBegin
+CREATE FUNCTION outbox_archive_create_partition(month_start TIMESTAMP) RETURNS VOID AS $$
+DECLARE
+    from_ts TIMESTAMP := date_trunc('month', month_start);
+    to_ts TIMESTAMP := date_trunc('month', month_start) + INTERVAL '1 month';
+BEGIN
+    EXECUTE format(
+        'CREATE TABLE IF NOT EXISTS %I PARTITION OF outbox_archive FOR VALUES FROM (%L) TO (%L)',
+        'outbox_archive_' || to_char(from_ts, 'YYYY_MM'),
+        from_ts,
+        to_ts
+    );
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP FUNCTION IF EXISTS outbox_archive_create_partition(TIMESTAMP);
+DROP TABLE IF EXISTS outbox_archive;
@@ -0,0 +1,7 @@
+-- +goose NO TRANSACTION
+-- +goose Up
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_outbox_sent_at ON outbox (sent_at)
+WHERE status = 'sent';
+
+-- +goose Down
+DROP INDEX CONCURRENTLY IF EXISTS idx_outbox_sent_at;
@@ -0,0 +1,13 @@
+-- +goose Up
+ALTER TABLE outbox
+    ALTER COLUMN payload TYPE BYTEA USING convert_to(payload::text, 'UTF8');
+
+ALTER TABLE outbox_archive
+    ALTER COLUMN payload TYPE BYTEA USING convert_to(payload::text, 'UTF8');
+
+-- +goose Down
+ALTER TABLE outbox_archive
+    ALTER COLUMN payload TYPE JSONB USING convert_from(payload, 'UTF8')::jsonb;
+
+ALTER TABLE outbox
+    ALTER COLUMN payload TYPE JSONB USING convert_from(payload, 'UTF8')::jsonb;
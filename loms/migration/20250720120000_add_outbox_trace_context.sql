@@ -0,0 +1,17 @@
+-- +goose Up
+ALTER TABLE outbox
+    ADD COLUMN trace_context JSONB NOT NULL DEFAULT '{}'::jsonb,
+    ADD COLUMN request_id TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE outbox_archive
+    ADD COLUMN trace_context JSONB NOT NULL DEFAULT '{}'::jsonb,
+    ADD COLUMN request_id TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE outbox_archive
+    DROP COLUMN request_id,
+    DROP COLUMN trace_context;
+
+ALTER TABLE outbox
+    DROP COLUMN request_id,
+    DROP COLUMN trace_context;
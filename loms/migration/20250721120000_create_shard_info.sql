@@ -0,0 +1,23 @@
+-- +goose Up
+CREATE TABLE shard_info (
+    singleton BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (singleton),
+    shard_id INT NOT NULL DEFAULT 0 CHECK (shard_id >= 0 AND shard_id < 65536)
+);
+
+INSERT INTO shard_info DEFAULT VALUES;
+
+-- +goose StatementBegin
+CREATE FUNCTION next_sharded_id(seq REGCLASS) RETURNS BIGINT AS $$
+    SELECT (shard_id::BIGINT << 47) | nextval(seq) FROM shard_info;
+$$ LANGUAGE SQL VOLATILE;
+-- +goose StatementEnd
+
+ALTER TABLE orders ALTER COLUMN id SET DEFAULT next_sharded_id('orders_id_seq');
+ALTER TABLE outbox ALTER COLUMN id SET DEFAULT next_sharded_id('outbox_id_seq');
+
+-- +goose Down
+ALTER TABLE outbox ALTER COLUMN id SET DEFAULT nextval('outbox_id_seq');
+ALTER TABLE orders ALTER COLUMN id SET DEFAULT nextval('orders_id_seq');
+
+DROP FUNCTION IF EXISTS next_sharded_id(REGCLASS);
+DROP TABLE IF EXISTS shard_info;
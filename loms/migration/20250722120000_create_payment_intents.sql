@@ -0,0 +1,21 @@
+-- +goose Up
+CREATE TYPE payment_status AS ENUM ('pending', 'authorized', 'captured', 'declined', 'refunded');
+
+CREATE TABLE payment_intents (
+    order_id BIGINT PRIMARY KEY,
+    provider TEXT NOT NULL,
+    provider_payment_id TEXT,
+    status payment_status NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    decline_reason TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_payment_intents_provider_payment_id
+    ON payment_intents (provider, provider_payment_id)
+    WHERE provider_payment_id IS NOT NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS payment_intents;
+DROP TYPE IF EXISTS payment_status;
@@ -0,0 +1,7 @@
+-- +goose NO TRANSACTION
+-- +goose Up
+ALTER TYPE order_status ADD VALUE IF NOT EXISTS 'refund requested';
+ALTER TYPE order_status ADD VALUE IF NOT EXISTS 'refunded';
+
+-- +goose Down
+SELECT 1;
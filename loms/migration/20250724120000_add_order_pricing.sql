@@ -0,0 +1,25 @@
+-- +goose Up
+ALTER TABLE order_items
+    ADD COLUMN price BIGINT NOT NULL DEFAULT 0 CHECK (price >= 0),
+    ADD COLUMN currency TEXT NOT NULL DEFAULT 'RUB';
+
+ALTER TABLE orders
+    ADD COLUMN total_price BIGINT NOT NULL DEFAULT 0 CHECK (total_price >= 0),
+    ADD COLUMN currency TEXT NOT NULL DEFAULT 'RUB';
+
+ALTER TABLE payment_intents
+    ADD COLUMN amount BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN currency TEXT NOT NULL DEFAULT 'RUB';
+
+-- +goose Down
+ALTER TABLE payment_intents
+    DROP COLUMN IF EXISTS currency,
+    DROP COLUMN IF EXISTS amount;
+
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS currency,
+    DROP COLUMN IF EXISTS total_price;
+
+ALTER TABLE order_items
+    DROP COLUMN IF EXISTS currency,
+    DROP COLUMN IF EXISTS price;
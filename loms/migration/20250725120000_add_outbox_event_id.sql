@@ -0,0 +1,13 @@
+-- +goose Up
+ALTER TABLE outbox
+    ADD COLUMN event_id TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE outbox_archive
+    ADD COLUMN event_id TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE outbox_archive
+    DROP COLUMN event_id;
+
+ALTER TABLE outbox
+    DROP COLUMN event_id;
@@ -11,7 +11,10 @@ import (
 	"github.com/IBM/sarama"
 )
 
+var ErrNoActiveSession = errors.New("consumer group has no active session")
+
 type Consumer struct {
+	client               sarama.Client
 	consumerGroup        sarama.ConsumerGroup
 	consumerGroupHandler *GroupHandler
 	topicName            string
@@ -28,20 +31,28 @@ func New(
 	config := sarama.NewConfig()
 
 	config.Consumer.Offsets.Initial = sarama.OffsetOldest
+	config.Consumer.IsolationLevel = sarama.ReadCommitted
 	config.Consumer.Offsets.AutoCommit.Enable = true
 	config.Consumer.Offsets.AutoCommit.Interval = time.Second
 	config.Consumer.Group.Rebalance.GroupStrategies = []sarama.BalanceStrategy{
 		sarama.NewBalanceStrategyRoundRobin(),
 	}
 
-	consumerGroup, err := sarama.NewConsumerGroup(brokers, groupID, config)
+	client, err := sarama.NewClient(brokers, config)
+	if err != nil {
+		return nil, fmt.Errorf("sarama.NewClient failed to create client: %w", err)
+	}
+
+	consumerGroup, err := sarama.NewConsumerGroupFromClient(groupID, client)
 	if err != nil {
-		return nil, fmt.Errorf("sarama.NewConsumerGroup failed to create consumer group: %w", err)
+		_ = client.Close()
+		return nil, fmt.Errorf("sarama.NewConsumerGroupFromClient failed to create consumer group: %w", err)
 	}
 
 	logger.Infof(ctx, "Consumer group successfully created for groupID=%s topic=%s", groupID, topic)
 
 	consumer := &Consumer{
+		client:               client,
 		consumerGroup:        consumerGroup,
 		consumerGroupHandler: consumerGroupHandler,
 		topicName:            topic,
@@ -65,8 +76,24 @@ func (c *Consumer) Consume(ctx context.Context) error {
 	return c.consume(ctx)
 }
 
+func (c *Consumer) Ping(_ context.Context) error {
+	if c.client.Closed() {
+		return sarama.ErrClosedClient
+	}
+
+	if err := c.client.RefreshMetadata(c.topicName); err != nil {
+		return fmt.Errorf("client.RefreshMetadata: %w", err)
+	}
+
+	if !c.consumerGroupHandler.Active() {
+		return ErrNoActiveSession
+	}
+
+	return nil
+}
+
 func (c *Consumer) Close() error {
-	return c.consumerGroup.Close()
+	return errors.Join(c.consumerGroup.Close(), c.client.Close())
 }
 
 func (c *Consumer) consume(ctx context.Context) error {
@@ -0,0 +1,47 @@
+package consumer
+
+import "sync"
+
+const (
+	eventIDHeader   = "x-event-id"
+	dedupWindowSize = 10000
+)
+
+type dedupWindow struct {
+	mu    sync.Mutex
+	seen  map[string]struct{}
+	order []string
+	next  int
+}
+
+func newDedupWindow(size int) *dedupWindow {
+	return &dedupWindow{
+		seen:  make(map[string]struct{}, size),
+		order: make([]string, size),
+	}
+}
+
+func (w *dedupWindow) Seen(eventID string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	_, ok := w.seen[eventID]
+	return ok
+}
+
+func (w *dedupWindow) Remember(eventID string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, ok := w.seen[eventID]; ok {
+		return
+	}
+
+	if evicted := w.order[w.next]; evicted != "" {
+		delete(w.seen, evicted)
+	}
+
+	w.order[w.next] = eventID
+	w.next = (w.next + 1) % len(w.order)
+	w.seen[eventID] = struct{}{}
+}
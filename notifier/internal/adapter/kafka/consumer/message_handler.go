@@ -3,11 +3,18 @@ package consumer
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"route256/notifier/internal/domain"
 	"route256/notifier/internal/infra/logger"
+	"route256/notifier/internal/infra/tracing"
+	eventsv1 "route256/notifier/internal/pb/events/v1"
+	"sync/atomic"
 	"time"
 
 	"github.com/IBM/sarama"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"google.golang.org/protobuf/proto"
 )
 
 type eventService interface {
@@ -15,53 +22,167 @@ type eventService interface {
 }
 type GroupHandler struct {
 	eventService eventService
+	dedup        *dedupWindow
+	active       atomic.Bool
 }
 
 type msgOrderEvent struct {
-	OrderID int64     `json:"order_id"`
-	Status  string    `json:"status"`
-	Moment  time.Time `json:"moment"`
+	OrderID int64               `json:"order_id"`
+	Status  string              `json:"status"`
+	Moment  time.Time           `json:"moment"`
+	Event   string              `json:"event,omitempty"`
+	Items   []msgOrderEventItem `json:"items,omitempty"`
+}
+
+type msgOrderEventItem struct {
+	Sku   int64 `json:"sku"`
+	Count int64 `json:"count"`
 }
 
 func NewGroupHandler(eventService eventService) *GroupHandler {
-	return &GroupHandler{eventService: eventService}
+	return &GroupHandler{
+		eventService: eventService,
+		dedup:        newDedupWindow(dedupWindowSize),
+	}
 }
 
 func (c *GroupHandler) Setup(sarama.ConsumerGroupSession) error {
+	c.active.Store(true)
 	return nil
 }
 
 func (c *GroupHandler) Cleanup(sarama.ConsumerGroupSession) error {
+	c.active.Store(false)
 	return nil
 }
 
+func (c *GroupHandler) Active() bool {
+	return c.active.Load()
+}
+
 func (c *GroupHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
 	ctx := context.Background()
 
 	for message := range claim.Messages() {
-		event := msgOrderEvent{}
-		if err := json.Unmarshal(message.Value, &event); err != nil {
-			logger.Errorf(ctx, "failed to unmarshal message: %v", err)
-			session.MarkMessage(message, "")
-			continue
-		}
+		c.handleMessage(session, message)
+	}
+
+	logger.Infof(ctx, "message channel was closed")
+	return nil
+}
 
-		if err := c.eventService.ProcessOrderEvent(session.Context(), mapOrderEvent(event)); err != nil {
-			logger.Errorf(ctx, "eventService.ProcessOrderEvent: error handling domain event: %v", err)
-			continue
+func (c *GroupHandler) handleMessage(session sarama.ConsumerGroupSession, message *sarama.ConsumerMessage) {
+	carrier := make(map[string]string, len(message.Headers))
+	for _, header := range message.Headers {
+		if header != nil {
+			carrier[string(header.Key)] = string(header.Value)
 		}
+	}
+
+	span := opentracing.StartSpan(
+		"kafkaConsumer.HandleOrderEvent",
+		opentracing.ChildOf(tracing.Extract(carrier)),
+		ext.SpanKindConsumer,
+	)
+	defer span.Finish()
+
+	ext.MessageBusDestination.Set(span, message.Topic)
+	span.SetTag("kafka.partition", message.Partition)
+	span.SetTag("kafka.offset", message.Offset)
 
+	ctx := opentracing.ContextWithSpan(session.Context(), span)
+	if requestID := carrier[tracing.RequestIDHeader]; requestID != "" {
+		span.SetTag("request_id", requestID)
+		ctx = tracing.WithRequestID(ctx, requestID)
+	}
+
+	eventID := carrier[eventIDHeader]
+	if eventID != "" && c.dedup.Seen(eventID) {
+		logger.Infof(ctx, "skip duplicate event: event_id = %s", eventID)
 		session.MarkMessage(message, "")
+		return
 	}
 
-	logger.Infof(ctx, "message channel was closed")
-	return nil
+	event, err := decodeOrderEvent(message.Value)
+	if err != nil {
+		ext.Error.Set(span, true)
+		logger.Errorf(ctx, "failed to unmarshal message: %v", err)
+		session.MarkMessage(message, "")
+		return
+	}
+
+	if err := c.eventService.ProcessOrderEvent(ctx, event); err != nil {
+		ext.Error.Set(span, true)
+		logger.Errorf(ctx, "eventService.ProcessOrderEvent: error handling domain event: %v", err)
+		return
+	}
+
+	if eventID != "" {
+		c.dedup.Remember(eventID)
+	}
+
+	session.MarkMessage(message, "")
+}
+
+func decodeOrderEvent(value []byte) (domain.OrderEvent, error) {
+	if len(value) > 0 && value[0] == '{' {
+		event := msgOrderEvent{}
+		if err := json.Unmarshal(value, &event); err != nil {
+			return domain.OrderEvent{}, fmt.Errorf("json.Unmarshal: %w", err)
+		}
+
+		return mapLegacyOrderEvent(event), nil
+	}
+
+	event := &eventsv1.OrderEvent{}
+	if err := proto.Unmarshal(value, event); err != nil {
+		return domain.OrderEvent{}, fmt.Errorf("proto.Unmarshal: %w", err)
+	}
+
+	if event.GetVersion() != eventsv1.OrderEventVersion_ORDER_EVENT_VERSION_1 {
+		return domain.OrderEvent{}, fmt.Errorf("unsupported event version: %v", event.GetVersion())
+	}
+
+	return mapOrderEvent(event), nil
 }
 
-func mapOrderEvent(in msgOrderEvent) domain.OrderEvent {
-	return domain.OrderEvent{
+func mapLegacyOrderEvent(in msgOrderEvent) domain.OrderEvent {
+	out := domain.OrderEvent{
+		Type:    in.Event,
 		OrderID: in.OrderID,
 		Status:  in.Status,
 		Moment:  in.Moment,
+		Items:   make([]domain.OrderEventItem, len(in.Items)),
+	}
+
+	for idx, item := range in.Items {
+		out.Items[idx] = domain.OrderEventItem{
+			Sku:   item.Sku,
+			Count: item.Count,
+		}
+	}
+
+	return out
+}
+
+func mapOrderEvent(in *eventsv1.OrderEvent) domain.OrderEvent {
+	out := domain.OrderEvent{
+		EventID:        in.GetEventId(),
+		Type:           in.GetType().String(),
+		OrderID:        in.GetOrderId(),
+		UserID:         in.GetUserId(),
+		Status:         in.GetStatus(),
+		PreviousStatus: in.GetPreviousStatus(),
+		Moment:         in.GetMoment().AsTime(),
+		Items:          make([]domain.OrderEventItem, len(in.GetItems())),
+	}
+
+	for idx, item := range in.GetItems() {
+		out.Items[idx] = domain.OrderEventItem{
+			Sku:   item.GetSku(),
+			Count: item.GetCount(),
+		}
 	}
+
+	return out
 }
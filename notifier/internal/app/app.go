@@ -3,19 +3,23 @@ package app
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
 	"route256/notifier/internal/infra/closer"
 	"route256/notifier/internal/infra/config"
 	"route256/notifier/internal/infra/logger"
+	"route256/notifier/internal/infra/tracing"
 	"sync"
 	"syscall"
+	"time"
 
 	"go.uber.org/zap/zapcore"
 )
 
 type App struct {
-	config *config.Config
+	config     *config.Config
+	httpServer *http.Server
 
 	serviceProvider *serviceProvider
 }
@@ -43,7 +47,9 @@ func NewApp(ctx context.Context, configPath string) (*App, error) {
 func (app *App) initDeps(ctx context.Context) error {
 	inits := []func(ctx context.Context) error{
 		app.initLogger,
+		app.initTracing,
 		app.initServiceProvider,
+		app.initHTTPServer,
 	}
 
 	for _, f := range inits {
@@ -76,6 +82,22 @@ func (app *App) Run(ctx context.Context) error {
 		}
 	}()
 
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := app.runHTTPServer(ctx); err != nil {
+			logger.Errorf(ctx, "http server error: %v", err)
+			cancel()
+		}
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		app.serviceProvider.HealthChecker(ctx).Run(ctx, app.serviceProvider.HealthCheckPeriod())
+	}()
+
 	gracefulShutdown(ctx, cancel, wg)
 
 	return nil
@@ -88,6 +110,20 @@ func (app *App) initServiceProvider(_ context.Context) error {
 	return nil
 }
 
+func (app *App) initHTTPServer(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.Handle("/livez", app.serviceProvider.HealthChecker(ctx).LivenessHandler())
+	mux.Handle("/readyz", app.serviceProvider.HealthChecker(ctx).ReadinessHandler())
+
+	app.httpServer = &http.Server{
+		Addr:              fmt.Sprintf("%s:%d", app.config.Server.Host, app.config.Server.HTTPPort),
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	return nil
+}
+
 func (app *App) initLogger(_ context.Context) error {
 	var level zapcore.Level
 
@@ -114,6 +150,17 @@ func (app *App) initLogger(_ context.Context) error {
 	return nil
 }
 
+func (app *App) initTracing(_ context.Context) error {
+	address := fmt.Sprintf("%v:%v", app.config.Jaeger.Host, app.config.Jaeger.Port)
+
+	err := tracing.Init(address)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (app *App) runOrderConsumer(ctx context.Context) error {
 	c := app.serviceProvider.OrderConsumer(ctx)
 
@@ -125,6 +172,25 @@ func (app *App) runOrderConsumer(ctx context.Context) error {
 	return nil
 }
 
+func (app *App) runHTTPServer(ctx context.Context) error {
+	logger.Infof(ctx, "HTTP server is running on %s", app.httpServer.Addr)
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- app.httpServer.ListenAndServe()
+	}()
+
+	select {
+	case <-ctx.Done():
+		logger.Infof(ctx, "HTTP server shutdown initiated")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		return app.httpServer.Shutdown(shutdownCtx)
+	case err := <-errCh:
+		return err
+	}
+}
+
 func gracefulShutdown(ctx context.Context, cancel context.CancelFunc, wg *sync.WaitGroup) {
 	sigterm := make(chan os.Signal, 1)
 	signal.Notify(sigterm, syscall.SIGINT, syscall.SIGTERM)
@@ -6,8 +6,10 @@ import (
 	serviceEvent "route256/notifier/internal/business/service/event"
 	"route256/notifier/internal/infra/closer"
 	"route256/notifier/internal/infra/config"
+	"route256/notifier/internal/infra/health"
 	"route256/notifier/internal/infra/logger"
 	"strings"
+	"time"
 )
 
 type serviceProvider struct {
@@ -16,7 +18,8 @@ type serviceProvider struct {
 	orderСonsumer             *kafkaConsumer.Consumer
 	orderConsumerGroupHandler *kafkaConsumer.GroupHandler
 
-	serviceEvent *serviceEvent.Service
+	serviceEvent  *serviceEvent.Service
+	healthChecker *health.Checker
 }
 
 func newServiceProvider() *serviceProvider {
@@ -64,3 +67,28 @@ func (srv *serviceProvider) OrderConsumer(ctx context.Context) *kafkaConsumer.Co
 
 	return srv.orderСonsumer
 }
+
+func (srv *serviceProvider) HealthChecker(ctx context.Context) *health.Checker {
+	if srv.healthChecker == nil {
+		srv.healthChecker = health.New(srv.healthCheckTimeout())
+		srv.healthChecker.Add("kafka_consumer_group", srv.OrderConsumer(ctx).Ping)
+	}
+
+	return srv.healthChecker
+}
+
+func (srv *serviceProvider) HealthCheckPeriod() time.Duration {
+	if srv.config.Health.CheckPeriodMs <= 0 {
+		return 5 * time.Second
+	}
+
+	return time.Duration(srv.config.Health.CheckPeriodMs) * time.Millisecond
+}
+
+func (srv *serviceProvider) healthCheckTimeout() time.Duration {
+	if srv.config.Health.CheckTimeoutMs <= 0 {
+		return 2 * time.Second
+	}
+
+	return time.Duration(srv.config.Health.CheckTimeoutMs) * time.Millisecond
+}
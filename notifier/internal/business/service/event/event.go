@@ -8,7 +8,8 @@ import (
 
 func (s *Service) ProcessOrderEvent(ctx context.Context, event domain.OrderEvent) error {
 	date := event.Moment.Format("2006-01-02 15:04:05")
-	logger.Infof(ctx, "order_id = %v, status = %s, moment = %v", event.OrderID, event.Status, date)
+	logger.Infof(ctx, "event_id = %s, type = %s, order_id = %v, user_id = %v, status = %s, previous_status = %s, items = %v, moment = %v",
+		event.EventID, event.Type, event.OrderID, event.UserID, event.Status, event.PreviousStatus, event.Items, date)
 
 	return nil
 }
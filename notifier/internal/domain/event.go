@@ -3,7 +3,17 @@ package domain
 import "time"
 
 type OrderEvent struct {
-	OrderID int64
-	Status  string
-	Moment  time.Time
+	EventID        string
+	Type           string
+	OrderID        int64
+	UserID         int64
+	Status         string
+	PreviousStatus string
+	Items          []OrderEventItem
+	Moment         time.Time
+}
+
+type OrderEventItem struct {
+	Sku   int64
+	Count int64
 }
@@ -10,12 +10,26 @@ import (
 type Config struct {
 	Kafka  KafkaConfig  `yaml:"kafka"`
 	Server ServerConfig `yaml:"server"`
+	Jaeger JaegerConfig `yaml:"jaeger"`
+	Health HealthConfig `yaml:"health"`
 }
 
 type ServerConfig struct {
+	Host     string `yaml:"host"`
+	HTTPPort int    `yaml:"http_port"`
 	LogLevel string `yaml:"log_level"`
 }
 
+type HealthConfig struct {
+	CheckPeriodMs  int `yaml:"check_period_ms"`
+	CheckTimeoutMs int `yaml:"check_timeout_ms"`
+}
+
+type JaegerConfig struct {
+	Host string `yaml:"host"`
+	Port int    `yaml:"port"`
+}
+
 type KafkaConfig struct {
 	Host                 string `yaml:"host"`
 	Port                 int    `yaml:"port"`
@@ -0,0 +1,166 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"route256/notifier/internal/infra/logger"
+	"sync"
+	"time"
+)
+
+var ErrCheckTimeout = errors.New("check timed out")
+
+const (
+	statusOK          = "ok"
+	statusUnavailable = "unavailable"
+)
+
+type CheckFunc func(ctx context.Context) error
+
+type check struct {
+	name string
+	fnc  CheckFunc
+}
+
+type Checker struct {
+	timeout time.Duration
+	checks  []check
+
+	mx      sync.RWMutex
+	results map[string]error
+}
+
+func New(timeout time.Duration) *Checker {
+	return &Checker{
+		timeout: timeout,
+	}
+}
+
+func (c *Checker) Add(name string, fnc CheckFunc) {
+	c.checks = append(c.checks, check{name: name, fnc: fnc})
+}
+
+func (c *Checker) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		c.runChecks(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *Checker) Ready() (bool, map[string]error) {
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+
+	if c.results == nil {
+		return false, nil
+	}
+
+	ready := true
+	for _, err := range c.results {
+		if err != nil {
+			ready = false
+		}
+	}
+
+	return ready, c.results
+}
+
+func (c *Checker) LivenessHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(statusOK))
+	}
+}
+
+func (c *Checker) ReadinessHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ready, results := c.Ready()
+
+		resp := struct {
+			Status string            `json:"status"`
+			Checks map[string]string `json:"checks"`
+		}{
+			Status: statusOK,
+			Checks: make(map[string]string, len(results)),
+		}
+
+		for name, err := range results {
+			resp.Checks[name] = statusOK
+			if err != nil {
+				resp.Checks[name] = err.Error()
+			}
+		}
+
+		code := http.StatusOK
+		if !ready {
+			resp.Status = statusUnavailable
+			code = http.StatusServiceUnavailable
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			logger.Warnf(r.Context(), "failed to write readiness response: %v", err)
+		}
+	}
+}
+
+func (c *Checker) runChecks(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	type result struct {
+		name string
+		err  error
+	}
+
+	resultCh := make(chan result, len(c.checks))
+	for _, chk := range c.checks {
+		go func() {
+			resultCh <- result{name: chk.name, err: chk.fnc(ctx)}
+		}()
+	}
+
+	results := make(map[string]error, len(c.checks))
+	for _, chk := range c.checks {
+		results[chk.name] = ErrCheckTimeout
+	}
+
+	for range c.checks {
+		select {
+		case res := <-resultCh:
+			results[res.name] = res.err
+		case <-ctx.Done():
+		}
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	c.mx.Lock()
+	prev := c.results
+	c.results = results
+	c.mx.Unlock()
+
+	for name, err := range results {
+		if err != nil && (prev == nil || prev[name] == nil) {
+			logger.Warnf(ctx, "health check %s failed: %v", name, err)
+		}
+
+		if err == nil && prev != nil && prev[name] != nil {
+			logger.Infof(ctx, "health check %s recovered", name)
+		}
+	}
+}
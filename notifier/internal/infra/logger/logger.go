@@ -2,8 +2,11 @@ package logger
 
 import (
 	"context"
+	"route256/notifier/internal/infra/tracing"
 	"sync"
 
+	"github.com/opentracing/opentracing-go"
+	"github.com/uber/jaeger-client-go"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -54,7 +57,7 @@ func Debugf(ctx context.Context, msg string, keysAndValues ...interface{}) {
 		l.Debugf(msg, keysAndValues...)
 	}
 
-	globalLogger.Debugf(msg, keysAndValues...)
+	loggerWithTrace(ctx).Debugf(msg, keysAndValues...)
 }
 
 func Infof(ctx context.Context, msg string, keysAndValues ...interface{}) {
@@ -63,7 +66,7 @@ func Infof(ctx context.Context, msg string, keysAndValues ...interface{}) {
 		return
 	}
 
-	globalLogger.Infof(msg, keysAndValues...)
+	loggerWithTrace(ctx).Infof(msg, keysAndValues...)
 }
 
 func Warnf(ctx context.Context, msg string, keysAndValues ...interface{}) {
@@ -72,7 +75,7 @@ func Warnf(ctx context.Context, msg string, keysAndValues ...interface{}) {
 		return
 	}
 
-	globalLogger.Warnf(msg, keysAndValues...)
+	loggerWithTrace(ctx).Warnf(msg, keysAndValues...)
 }
 
 func Errorf(ctx context.Context, msg string, keysAndValues ...interface{}) {
@@ -80,7 +83,7 @@ func Errorf(ctx context.Context, msg string, keysAndValues ...interface{}) {
 		l.Errorf(msg, keysAndValues...)
 	}
 
-	globalLogger.Errorf(msg, keysAndValues...)
+	loggerWithTrace(ctx).Errorf(msg, keysAndValues...)
 }
 
 func Fatalf(ctx context.Context, msg string, keysAndValues ...interface{}) {
@@ -88,5 +91,27 @@ func Fatalf(ctx context.Context, msg string, keysAndValues ...interface{}) {
 		l.Fatalf(msg, keysAndValues...)
 	}
 
-	globalLogger.Fatalf(msg, keysAndValues...)
+	loggerWithTrace(ctx).Fatalf(msg, keysAndValues...)
+}
+
+func loggerWithTrace(ctx context.Context) *zap.SugaredLogger {
+	l := globalLogger
+
+	if requestID := tracing.RequestIDFromContext(ctx); requestID != "" {
+		l = l.With("request_id", requestID)
+	}
+
+	span := opentracing.SpanFromContext(ctx)
+	if span == nil {
+		return l
+	}
+
+	if sc, ok := span.Context().(jaeger.SpanContext); ok {
+		l = l.With(
+			"trace_id", sc.TraceID().String(),
+			"span_id", sc.SpanID().String(),
+		)
+	}
+
+	return l
 }
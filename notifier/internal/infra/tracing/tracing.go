@@ -0,0 +1,27 @@
+package tracing
+
+import (
+	"github.com/uber/jaeger-client-go/config"
+)
+
+const serviceName = "notifier"
+
+func Init(address string) error {
+	cfg := config.Configuration{
+		ServiceName: serviceName,
+		Sampler: &config.SamplerConfig{
+			Type:  "const",
+			Param: 1,
+		},
+		Reporter: &config.ReporterConfig{
+			LocalAgentHostPort: address,
+		},
+	}
+
+	_, err := cfg.InitGlobalTracer(serviceName)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}